@@ -45,6 +45,9 @@ func main() {
 	db := database.GetDB()
 	userRepo = repository.NewUserRepository(db)
 
+	// Tutup otomatis sesi absensi yang lupa Clock Out
+	handlers.StartAutoClockOutScheduler(15 * time.Minute)
+
 	// Setup Gin
 	setupGin()
 
@@ -97,6 +100,18 @@ func setupGin() {
 			public.POST("/login", handlers.Login)
 		}
 
+		// Signed file downloads (local storage) - authorized by URL signature, not bearer token
+		api.GET("/files/*key", handlers.ServeFile)
+
+		// Kiosk QR code - authorized by kiosk token ("Authorization: Kiosk <token>"), not user JWT
+		api.GET("/kiosk/code", handlers.GetKioskCode)
+
+		// Scheduled jobs - authorized by CRON_SECRET ("Authorization: Bearer <secret>"), not user JWT
+		api.GET("/jobs/auto-clock-out", handlers.RunAutoClockOutJob)
+
+		// Team presence stream - also accepts a short-lived stream token (?token=) because EventSource cannot send headers
+		api.GET("/attendance/team-presence/stream", middleware.GinStreamAuthMiddleware(), handlers.StreamTeamPresence)
+
 		// Protected routes - Authentication required
 		protected := api.Group("")
 		protected.Use(middleware.GinAuthMiddleware())
@@ -128,8 +143,131 @@ func setupGin() {
 			// Attendance routes
 			protected.POST("/attendance/clock-in", handlers.ClockIn)
 			protected.POST("/attendance/clock-out", handlers.ClockOut)
+			protected.POST("/attendance/sync", handlers.SyncOfflineAttendance)
 			protected.POST("/attendance/check-location", handlers.CheckLocation)
 			protected.GET("/attendance", handlers.GetAttendance)
+			protected.GET("/attendance/breaks", handlers.GetCurrentBreaks)
+			protected.POST("/attendance/breaks/start", handlers.StartBreak)
+			protected.POST("/attendance/breaks/end", handlers.EndBreak)
+
+			// Attendance approval routes - supervisor of the employee or admin/HR
+			protected.GET("/attendance/approvals", handlers.GetPendingApprovals)
+			protected.POST("/attendance/approvals/bulk", handlers.BulkDecideAttendances)
+			protected.POST("/attendance/:id/approve", handlers.ApproveAttendance)
+			protected.POST("/attendance/:id/reject", handlers.RejectAttendance)
+
+			// Team presence board - supervisors see direct reports, admin/HR the whole tenant
+			protected.GET("/attendance/team-presence", handlers.GetTeamPresence)
+			protected.POST("/attendance/team-presence/stream-token", handlers.CreateTeamPresenceStreamToken)
+
+			// Attendance correction routes - employee proposes, supervisor of the employee or admin/HR decides
+			protected.GET("/attendance/corrections", handlers.GetAttendanceCorrections)
+			protected.POST("/attendance/:id/corrections", handlers.CreateAttendanceCorrection)
+			protected.POST("/attendance/corrections/:id/cancel", handlers.CancelAttendanceCorrection)
+			protected.GET("/attendance/corrections/approvals", handlers.GetPendingAttendanceCorrections)
+			protected.POST("/attendance/corrections/:id/approve", handlers.ApproveAttendanceCorrection)
+			protected.POST("/attendance/corrections/:id/reject", handlers.RejectAttendanceCorrection)
+
+			// Overtime routes - requested ahead of time, decided by the supervisor of the employee or admin/HR
+			protected.GET("/overtime/requests", handlers.GetOvertimeRequests)
+			protected.POST("/overtime/requests", handlers.CreateOvertimeRequest)
+			protected.POST("/overtime/requests/:id/cancel", handlers.CancelOvertimeRequest)
+			protected.GET("/overtime/approvals", handlers.GetPendingOvertimeRequests)
+			protected.POST("/overtime/requests/:id/approve", handlers.ApproveOvertimeRequest)
+			protected.POST("/overtime/requests/:id/reject", handlers.RejectOvertimeRequest)
+			protected.GET("/overtime/report", handlers.GetOvertimeReport)
+
+			// Office location routes (multi-office geofencing)
+			protected.GET("/office-locations", handlers.GetOfficeLocations)
+			protected.GET("/office-locations/:id", handlers.GetOfficeLocation)
+			protected.GET("/office-locations/:id/signals", handlers.GetOfficePresenceSignals)
+
+			// Work schedule routes (lateness & overtime)
+			protected.GET("/schedules", handlers.GetSchedules)
+			protected.GET("/schedules/:id", handlers.GetSchedule)
+
+			// Working-day calendar routes (holidays & company days off)
+			protected.GET("/calendar", handlers.GetCalendar)
+			protected.GET("/settings/timezone", handlers.GetTenantTimezone)
+
+			// Leave routes (cuti, izin, sakit)
+			protected.GET("/leave/types", handlers.GetLeaveTypes)
+			protected.GET("/leave/balances", handlers.GetLeaveBalances)
+			protected.GET("/leave/requests", handlers.GetLeaveRequests)
+			protected.POST("/leave/requests", handlers.CreateLeaveRequest)
+			protected.POST("/leave/requests/:id/cancel", handlers.CancelLeaveRequest)
+			protected.GET("/leave/approvals", handlers.GetPendingLeaveRequests)
+			protected.POST("/leave/requests/:id/approve", handlers.ApproveLeaveRequest)
+			protected.POST("/leave/requests/:id/reject", handlers.RejectLeaveRequest)
+
+			// Shift roster routes (night shifts & shift swaps)
+			protected.GET("/rosters", handlers.GetRoster)
+			protected.GET("/shift-swaps", handlers.GetShiftSwaps)
+			protected.POST("/shift-swaps", handlers.CreateShiftSwap)
+			protected.POST("/shift-swaps/:id/cancel", handlers.CancelShiftSwap)
+			protected.GET("/shift-swaps/approvals", handlers.GetPendingShiftSwaps)
+			protected.POST("/shift-swaps/:id/approve", handlers.ApproveShiftSwap)
+			protected.POST("/shift-swaps/:id/reject", handlers.RejectShiftSwap)
+
+			// Remote work (WFH) routes
+			protected.GET("/wfh/home-location", handlers.GetHomeLocation)
+			protected.PUT("/wfh/home-location", handlers.SaveHomeLocation)
+			protected.GET("/wfh/requests", handlers.GetWFHRequests)
+			protected.POST("/wfh/requests", handlers.CreateWFHRequest)
+			protected.POST("/wfh/requests/:id/cancel", handlers.CancelWFHRequest)
+			protected.GET("/wfh/approvals", handlers.GetPendingWFHRequests)
+			protected.POST("/wfh/requests/:id/approve", handlers.ApproveWFHRequest)
+			protected.POST("/wfh/requests/:id/reject", handlers.RejectWFHRequest)
+
+			// Notification routes
+			protected.GET("/notifications", handlers.GetNotifications)
+			protected.POST("/notifications/:id/read", handlers.MarkNotificationRead)
+			protected.POST("/notifications/read-all", handlers.MarkAllNotificationsRead)
+
+			// Admin routes - admin/HR only
+			admin := protected.Group("")
+			admin.Use(middleware.GinRequireRole("admin", "hr"))
+			{
+				admin.POST("/office-locations", handlers.CreateOfficeLocation)
+				admin.PUT("/office-locations/:id", handlers.UpdateOfficeLocation)
+				admin.DELETE("/office-locations/:id", handlers.DeleteOfficeLocation)
+				admin.POST("/office-locations/:id/signals", handlers.CreateOfficePresenceSignal)
+				admin.DELETE("/office-locations/:id/signals/:signalId", handlers.DeleteOfficePresenceSignal)
+				admin.GET("/kiosks", handlers.GetKiosks)
+				admin.POST("/kiosks", handlers.RegisterKiosk)
+				admin.POST("/kiosks/:id/rotate-token", handlers.RotateKioskToken)
+				admin.DELETE("/kiosks/:id", handlers.DeleteKiosk)
+				admin.POST("/schedules", handlers.CreateSchedule)
+				admin.PUT("/schedules/:id", handlers.UpdateSchedule)
+				admin.DELETE("/schedules/:id", handlers.DeleteSchedule)
+				admin.POST("/schedules/:id/assign", handlers.AssignSchedule)
+				admin.GET("/attendance/reports/recap", handlers.GetAttendanceRecap)
+				admin.GET("/attendance/:id/audit-log", handlers.GetAttendanceAuditLog)
+				admin.POST("/leave/types", handlers.CreateLeaveType)
+				admin.PUT("/leave/types/:id", handlers.UpdateLeaveType)
+				admin.DELETE("/leave/types/:id", handlers.DeleteLeaveType)
+				admin.POST("/leave/balances/accrue", handlers.AccrueLeaveBalances)
+				admin.PUT("/calendar", handlers.UpdateCalendar)
+				admin.POST("/calendar/holidays", handlers.CreateHoliday)
+				admin.POST("/calendar/holidays/import", handlers.ImportNationalHolidays)
+				admin.DELETE("/calendar/holidays/:id", handlers.DeleteHoliday)
+				admin.PUT("/settings/timezone", handlers.UpdateTenantTimezone)
+				admin.PUT("/rosters", handlers.SaveRoster)
+				admin.DELETE("/rosters/:id", handlers.DeleteRosterEntry)
+				admin.GET("/attendance/settings/auto-clock-out", handlers.GetAutoClockOutPolicy)
+				admin.PUT("/attendance/settings/auto-clock-out", handlers.UpdateAutoClockOutPolicy)
+				admin.GET("/attendance/settings/breaks", handlers.GetBreakPolicy)
+				admin.PUT("/attendance/settings/breaks", handlers.UpdateBreakPolicy)
+				admin.GET("/attendance/settings/remote-work", handlers.GetRemoteWorkPolicy)
+				admin.PUT("/attendance/settings/remote-work", handlers.UpdateRemoteWorkPolicy)
+				admin.GET("/attendance/settings/overtime", handlers.GetOvertimePolicy)
+				admin.PUT("/attendance/settings/overtime", handlers.UpdateOvertimePolicy)
+				admin.GET("/attendance/settings/policy", handlers.GetAttendancePolicy)
+				admin.PUT("/attendance/settings/policy", handlers.UpdateAttendancePolicy)
+				admin.POST("/attendance/geofence-simulation", handlers.SimulateGeofence)
+				admin.GET("/wfh/home-locations", handlers.GetHomeLocations)
+				admin.DELETE("/wfh/home-locations/:employeeId", handlers.DeleteHomeLocation)
+			}
 
 			// CRM routes - FIXED: Added missing routes
 			protected.GET("/crm/projects", handlers.GetCRMProjects)
@@ -138,6 +276,13 @@ func setupGin() {
 			protected.PUT("/crm/projects/:id", handlers.UpdateCRMProject)
 			protected.DELETE("/crm/projects/:id", handlers.DeleteCRMProject)
 
+			// Client visit routes - geotagged visits linked to CRM deals, separate from office attendance
+			protected.POST("/crm/visits/check-in", handlers.CheckInClientVisit)
+			protected.POST("/crm/visits/:id/check-out", handlers.CheckOutClientVisit)
+			protected.GET("/crm/visits/current", handlers.GetCurrentClientVisit)
+			protected.GET("/crm/visits/report", handlers.GetDailyVisitReport)
+			protected.GET("/crm/projects/:id/visits", handlers.GetCRMProjectVisits)
+
 			// Project routes
 			protected.GET("/projects", handlers.GetProjects)
 			protected.GET("/projects/:id", handlers.GetProject)
@@ -158,6 +303,9 @@ func setupGin() {
 	log.Printf("   - GET  /swagger")
 	log.Printf("   - POST /api/v1/auth/register")
 	log.Printf("   - POST /api/v1/auth/login")
+	log.Printf("   - GET  /api/v1/files/*key")
+	log.Printf("   - GET  /api/v1/kiosk/code")
+	log.Printf("   - GET  /api/v1/jobs/auto-clock-out")
 	log.Printf("   - GET  /api/v1/home") // DITAMBAHKAN
 	log.Printf("   - GET  /api/v1/dashboard/stats")
 	log.Printf("   - GET  /api/v1/teams")
@@ -173,11 +321,112 @@ func setupGin() {
 	log.Printf("   - GET  /api/v1/tasks/statistics")
 	log.Printf("   - POST /api/v1/attendance/clock-in")
 	log.Printf("   - POST /api/v1/attendance/clock-out")
+	log.Printf("   - POST /api/v1/attendance/sync")
+	log.Printf("   - GET  /api/v1/attendance/breaks")
+	log.Printf("   - POST /api/v1/attendance/breaks/start")
+	log.Printf("   - POST /api/v1/attendance/breaks/end")
+	log.Printf("   - GET  /api/v1/attendance/approvals")
+	log.Printf("   - POST /api/v1/attendance/approvals/bulk")
+	log.Printf("   - POST /api/v1/attendance/:id/approve")
+	log.Printf("   - POST /api/v1/attendance/:id/reject")
+	log.Printf("   - GET  /api/v1/attendance/team-presence")
+	log.Printf("   - GET  /api/v1/attendance/team-presence/stream")
+	log.Printf("   - POST /api/v1/attendance/team-presence/stream-token")
+	log.Printf("   - GET  /api/v1/attendance/corrections")
+	log.Printf("   - POST /api/v1/attendance/:id/corrections")
+	log.Printf("   - POST /api/v1/attendance/corrections/:id/cancel")
+	log.Printf("   - GET  /api/v1/attendance/corrections/approvals")
+	log.Printf("   - POST /api/v1/attendance/corrections/:id/approve")
+	log.Printf("   - POST /api/v1/attendance/corrections/:id/reject")
+	log.Printf("   - GET  /api/v1/attendance/:id/audit-log")
+	log.Printf("   - GET  /api/v1/overtime/requests")
+	log.Printf("   - POST /api/v1/overtime/requests")
+	log.Printf("   - POST /api/v1/overtime/requests/:id/cancel")
+	log.Printf("   - GET  /api/v1/overtime/approvals")
+	log.Printf("   - POST /api/v1/overtime/requests/:id/approve")
+	log.Printf("   - POST /api/v1/overtime/requests/:id/reject")
+	log.Printf("   - GET  /api/v1/overtime/report")
+	log.Printf("   - GET  /api/v1/attendance/reports/recap")
+	log.Printf("   - GET  /api/v1/office-locations")
+	log.Printf("   - POST /api/v1/office-locations")
+	log.Printf("   - GET  /api/v1/office-locations/:id")
+	log.Printf("   - PUT  /api/v1/office-locations/:id")
+	log.Printf("   - DELETE /api/v1/office-locations/:id")
+	log.Printf("   - GET  /api/v1/office-locations/:id/signals")
+	log.Printf("   - POST /api/v1/office-locations/:id/signals")
+	log.Printf("   - DELETE /api/v1/office-locations/:id/signals/:signalId")
+	log.Printf("   - GET  /api/v1/kiosks")
+	log.Printf("   - POST /api/v1/kiosks")
+	log.Printf("   - POST /api/v1/kiosks/:id/rotate-token")
+	log.Printf("   - DELETE /api/v1/kiosks/:id")
+	log.Printf("   - GET  /api/v1/leave/types")
+	log.Printf("   - POST /api/v1/leave/types")
+	log.Printf("   - PUT  /api/v1/leave/types/:id")
+	log.Printf("   - DELETE /api/v1/leave/types/:id")
+	log.Printf("   - GET  /api/v1/leave/balances")
+	log.Printf("   - POST /api/v1/leave/balances/accrue")
+	log.Printf("   - GET  /api/v1/leave/requests")
+	log.Printf("   - POST /api/v1/leave/requests")
+	log.Printf("   - POST /api/v1/leave/requests/:id/cancel")
+	log.Printf("   - GET  /api/v1/leave/approvals")
+	log.Printf("   - POST /api/v1/leave/requests/:id/approve")
+	log.Printf("   - POST /api/v1/leave/requests/:id/reject")
+	log.Printf("   - GET  /api/v1/calendar")
+	log.Printf("   - PUT  /api/v1/calendar")
+	log.Printf("   - POST /api/v1/calendar/holidays")
+	log.Printf("   - POST /api/v1/calendar/holidays/import")
+	log.Printf("   - DELETE /api/v1/calendar/holidays/:id")
+	log.Printf("   - GET  /api/v1/settings/timezone")
+	log.Printf("   - PUT  /api/v1/settings/timezone")
+	log.Printf("   - GET  /api/v1/rosters")
+	log.Printf("   - PUT  /api/v1/rosters")
+	log.Printf("   - DELETE /api/v1/rosters/:id")
+	log.Printf("   - GET  /api/v1/wfh/home-location")
+	log.Printf("   - PUT  /api/v1/wfh/home-location")
+	log.Printf("   - GET  /api/v1/wfh/home-locations")
+	log.Printf("   - DELETE /api/v1/wfh/home-locations/:employeeId")
+	log.Printf("   - GET  /api/v1/wfh/requests")
+	log.Printf("   - POST /api/v1/wfh/requests")
+	log.Printf("   - POST /api/v1/wfh/requests/:id/cancel")
+	log.Printf("   - GET  /api/v1/wfh/approvals")
+	log.Printf("   - POST /api/v1/wfh/requests/:id/approve")
+	log.Printf("   - POST /api/v1/wfh/requests/:id/reject")
+	log.Printf("   - GET  /api/v1/notifications")
+	log.Printf("   - POST /api/v1/notifications/:id/read")
+	log.Printf("   - POST /api/v1/notifications/read-all")
+	log.Printf("   - GET  /api/v1/attendance/settings/auto-clock-out")
+	log.Printf("   - PUT  /api/v1/attendance/settings/auto-clock-out")
+	log.Printf("   - GET  /api/v1/attendance/settings/breaks")
+	log.Printf("   - PUT  /api/v1/attendance/settings/breaks")
+	log.Printf("   - GET  /api/v1/attendance/settings/remote-work")
+	log.Printf("   - PUT  /api/v1/attendance/settings/remote-work")
+	log.Printf("   - GET  /api/v1/attendance/settings/overtime")
+	log.Printf("   - PUT  /api/v1/attendance/settings/overtime")
+	log.Printf("   - GET  /api/v1/attendance/settings/policy")
+	log.Printf("   - PUT  /api/v1/attendance/settings/policy")
+	log.Printf("   - POST /api/v1/attendance/geofence-simulation")
+	log.Printf("   - GET  /api/v1/shift-swaps")
+	log.Printf("   - POST /api/v1/shift-swaps")
+	log.Printf("   - POST /api/v1/shift-swaps/:id/cancel")
+	log.Printf("   - GET  /api/v1/shift-swaps/approvals")
+	log.Printf("   - POST /api/v1/shift-swaps/:id/approve")
+	log.Printf("   - POST /api/v1/shift-swaps/:id/reject")
+	log.Printf("   - GET  /api/v1/schedules")
+	log.Printf("   - POST /api/v1/schedules")
+	log.Printf("   - GET  /api/v1/schedules/:id")
+	log.Printf("   - PUT  /api/v1/schedules/:id")
+	log.Printf("   - DELETE /api/v1/schedules/:id")
+	log.Printf("   - POST /api/v1/schedules/:id/assign")
 	log.Printf("   - GET  /api/v1/crm/projects") // FIXED: Added CRM routes
 	log.Printf("   - POST /api/v1/crm/projects")
 	log.Printf("   - GET  /api/v1/crm/projects/:id")
 	log.Printf("   - PUT  /api/v1/crm/projects/:id")
 	log.Printf("   - DELETE /api/v1/crm/projects/:id")
+	log.Printf("   - POST /api/v1/crm/visits/check-in")
+	log.Printf("   - POST /api/v1/crm/visits/:id/check-out")
+	log.Printf("   - GET  /api/v1/crm/visits/current")
+	log.Printf("   - GET  /api/v1/crm/visits/report")
+	log.Printf("   - GET  /api/v1/crm/projects/:id/visits")
 	log.Printf("   - GET  /api/v1/projects")
 	log.Printf("   - GET  /api/v1/projects/:id")
 }
@@ -0,0 +1,140 @@
+// Command migrate-photos moves legacy base64 selfies stored in godplan.attendances
+// (check_in_photo / check_out_photo) into the configured blob storage and replaces
+// the column value with the object key.
+//
+// Usage: go run ./cmd/migrate-photos [-batch 100] [-dry-run]
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/storage"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+type legacyPhotoRow struct {
+	id            uuid.UUID
+	tenantID      uuid.UUID
+	userID        uuid.UUID
+	createdAt     time.Time
+	checkInPhoto  sql.NullString
+	checkOutPhoto sql.NullString
+}
+
+func main() {
+	batchSize := flag.Int("batch", 100, "rows fetched per batch")
+	dryRun := flag.Bool("dry-run", false, "only report what would be migrated")
+	flag.Parse()
+
+	cfg := config.Load()
+	if err := database.InitDB(cfg); err != nil {
+		log.Fatalf("❌ Database connection failed: %v", err)
+	}
+	defer database.CloseDB()
+
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Storage init failed: %v", err)
+	}
+
+	ctx := context.Background()
+	lastID := uuid.Nil
+	migrated, skipped := 0, 0
+
+	for {
+		rows, err := fetchLegacyRows(lastID, *batchSize)
+		if err != nil {
+			log.Fatalf("❌ Failed to fetch attendances: %v", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			lastID = row.id
+
+			checkInKey, okIn := migratePhoto(ctx, store, row, "checkin", row.checkInPhoto, *dryRun)
+			checkOutKey, okOut := migratePhoto(ctx, store, row, "checkout", row.checkOutPhoto, *dryRun)
+			if !okIn || !okOut {
+				skipped++
+				continue
+			}
+			if *dryRun {
+				migrated++
+				continue
+			}
+
+			_, err := database.DB.Exec(
+				`UPDATE godplan.attendances SET check_in_photo = $1, check_out_photo = $2 WHERE id = $3`,
+				checkInKey, checkOutKey, row.id,
+			)
+			if err != nil {
+				log.Printf("⚠️ Attendance %s: failed to update row: %v", row.id, err)
+				skipped++
+				continue
+			}
+			migrated++
+		}
+	}
+
+	log.Printf("✅ Done. Migrated: %d, skipped: %d (dry run: %t)", migrated, skipped, *dryRun)
+}
+
+func fetchLegacyRows(afterID uuid.UUID, limit int) ([]legacyPhotoRow, error) {
+	rows, err := database.DB.Query(
+		`SELECT id, tenant_id, user_id, created_at, check_in_photo, check_out_photo
+		FROM godplan.attendances
+		WHERE id > $1
+		AND ((check_in_photo <> '' AND check_in_photo NOT LIKE $2)
+			OR (check_out_photo <> '' AND check_out_photo NOT LIKE $2))
+		ORDER BY id
+		LIMIT $3`,
+		afterID, storage.AttendancePhotoPrefix+"%", limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []legacyPhotoRow
+	for rows.Next() {
+		var row legacyPhotoRow
+		if err := rows.Scan(&row.id, &row.tenantID, &row.userID, &row.createdAt, &row.checkInPhoto, &row.checkOutPhoto); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// migratePhoto uploads one legacy photo and returns the value to store in the column.
+// Values that are already keys (or empty) are returned unchanged.
+func migratePhoto(ctx context.Context, store storage.Storage, row legacyPhotoRow, kind string, photo sql.NullString, dryRun bool) (sql.NullString, bool) {
+	if !photo.Valid || photo.String == "" || storage.IsAttendancePhotoKey(photo.String) {
+		return photo, true
+	}
+
+	data, contentType, err := utils.DecodeBase64Image(photo.String)
+	if err != nil {
+		log.Printf("⚠️ Attendance %s: %s photo is not a valid image, skipped: %v", row.id, kind, err)
+		return photo, false
+	}
+
+	key := storage.AttendancePhotoKey(row.tenantID, row.userID, kind, row.createdAt, utils.ImageExtension(contentType))
+	if dryRun {
+		return sql.NullString{String: key, Valid: true}, true
+	}
+
+	if err := store.Put(ctx, key, data, contentType); err != nil {
+		log.Printf("⚠️ Attendance %s: failed to upload %s photo: %v", row.id, kind, err)
+		return photo, false
+	}
+	return sql.NullString{String: key, Valid: true}, true
+}
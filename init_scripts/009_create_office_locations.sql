@@ -0,0 +1,30 @@
+-- Migration: Create office locations table for multi-office geofencing
+-- Description: Tenant-scoped branches and client sites used to validate attendance location
+
+CREATE TABLE IF NOT EXISTS godplan.office_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude >= -90 AND latitude <= 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude >= -180 AND longitude <= 180),
+    radius INT NOT NULL DEFAULT 100 CHECK (radius > 0),
+    address TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_office_locations_tenant ON godplan.office_locations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_office_locations_active ON godplan.office_locations(tenant_id, is_active);
+
+-- Store the office resolved at clock-in and clock-out time
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS office_location_id UUID REFERENCES godplan.office_locations(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS check_out_office_location_id UUID REFERENCES godplan.office_locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_attendances_office ON godplan.attendances(office_location_id);
+
+COMMENT ON TABLE godplan.office_locations IS 'Office, branch and client-site geofences per tenant';
+COMMENT ON COLUMN godplan.office_locations.radius IS 'Base geofence radius in meters (before GPS accuracy adjustment)';
+COMMENT ON COLUMN godplan.attendances.office_location_id IS 'Nearest active office resolved at clock-in (NULL = env-configured default office)';
+COMMENT ON COLUMN godplan.attendances.check_out_office_location_id IS 'Nearest active office resolved at clock-out';
@@ -0,0 +1,15 @@
+-- Migration: Add polygon boundary to office locations
+-- Description: Allow irregular (campus-style) geofences defined as GeoJSON polygons with optional holes
+
+ALTER TABLE godplan.office_locations
+ADD COLUMN IF NOT EXISTS boundary JSONB;
+
+-- Polygon geofences do not need a radius
+ALTER TABLE godplan.office_locations DROP CONSTRAINT IF EXISTS office_locations_radius_check;
+ALTER TABLE godplan.office_locations ADD CONSTRAINT office_locations_radius_check CHECK (radius >= 0);
+
+ALTER TABLE godplan.office_locations DROP CONSTRAINT IF EXISTS office_locations_shape_check;
+ALTER TABLE godplan.office_locations ADD CONSTRAINT office_locations_shape_check
+    CHECK (radius > 0 OR (boundary IS NOT NULL AND boundary->>'type' = 'Polygon'));
+
+COMMENT ON COLUMN godplan.office_locations.boundary IS 'GeoJSON Polygon ([lng, lat] positions, first ring outer, others holes). Overrides radius when set';
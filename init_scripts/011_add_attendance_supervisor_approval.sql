@@ -0,0 +1,22 @@
+-- Migration: Supervisor approval workflow for attendances
+-- Description: Link employees to their direct supervisor and track approval decisions on godplan.attendances
+
+ALTER TABLE godplan.employees
+ADD COLUMN IF NOT EXISTS supervisor_id UUID REFERENCES godplan.employees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_employees_supervisor ON godplan.employees(supervisor_id)
+WHERE supervisor_id IS NOT NULL;
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+-- Supervisor queue lookups
+CREATE INDEX IF NOT EXISTS idx_attendances_pending ON godplan.attendances(tenant_id, created_at)
+WHERE status IN ('pending', 'pending_forced');
+
+COMMENT ON COLUMN godplan.employees.supervisor_id IS 'Direct supervisor who approves this employee''s pending attendances';
+COMMENT ON COLUMN godplan.attendances.approved_by IS 'User who approved or rejected this attendance';
+COMMENT ON COLUMN godplan.attendances.approved_at IS 'Timestamp when the attendance was approved or rejected';
+COMMENT ON COLUMN godplan.attendances.rejection_reason IS 'Reason provided when attendance is rejected';
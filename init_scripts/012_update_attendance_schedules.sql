@@ -0,0 +1,32 @@
+-- Migration: Tenant work schedules for lateness and overtime
+-- Description: Scope attendance_schedules per tenant, assign schedules to employees and store computed minutes on attendances
+
+ALTER TABLE godplan.attendance_schedules
+ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+ADD COLUMN IF NOT EXISTS is_active BOOLEAN DEFAULT true,
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_attendance_schedules_tenant ON godplan.attendance_schedules(tenant_id);
+
+-- Only one default schedule per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_schedules_default
+ON godplan.attendance_schedules(tenant_id) WHERE is_default = true;
+
+ALTER TABLE godplan.employees
+ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES godplan.attendance_schedules(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_employees_schedule ON godplan.employees(schedule_id)
+WHERE schedule_id IS NOT NULL;
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES godplan.attendance_schedules(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS late_minutes NUMERIC(10,2) DEFAULT 0,
+ADD COLUMN IF NOT EXISTS early_leave_minutes NUMERIC(10,2) DEFAULT 0,
+ADD COLUMN IF NOT EXISTS overtime_minutes NUMERIC(10,2) DEFAULT 0;
+
+COMMENT ON COLUMN godplan.attendance_schedules.working_days IS 'Bitmask of working weekdays (bit 0 = Sunday ... bit 6 = Saturday). 0 means Monday-Friday';
+COMMENT ON COLUMN godplan.attendance_schedules.tolerance_late IS 'Minutes after start_time before a clock in counts as late';
+COMMENT ON COLUMN godplan.attendance_schedules.tolerance_early IS 'Minutes before end_time a clock out may happen without counting as early leave';
+COMMENT ON COLUMN godplan.employees.schedule_id IS 'Assigned work schedule. NULL falls back to the tenant default schedule';
+COMMENT ON COLUMN godplan.attendances.schedule_id IS 'Schedule used to compute late/early leave/overtime minutes';
+COMMENT ON COLUMN godplan.attendances.early_leave_minutes IS 'Minutes clocked out before the scheduled end time';
@@ -0,0 +1,17 @@
+-- Migration: GPS spoofing detection for attendances
+-- Description: Store fraud score and flags per attendance so suspicious clock events go to supervisor approval
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS fraud_score INTEGER DEFAULT 0,
+ADD COLUMN IF NOT EXISTS fraud_flags TEXT[] DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS is_suspicious BOOLEAN DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_attendances_suspicious ON godplan.attendances(tenant_id, attendance_date)
+WHERE is_suspicious = true;
+
+-- Impossible travel and reused coordinate lookups per user
+CREATE INDEX IF NOT EXISTS idx_attendances_user_checkin ON godplan.attendances(tenant_id, user_id, check_in_time DESC);
+
+COMMENT ON COLUMN godplan.attendances.fraud_score IS 'GPS spoofing risk score 0-100 (highest of clock in / clock out)';
+COMMENT ON COLUMN godplan.attendances.fraud_flags IS 'Triggered signals: mock_location, perfect_accuracy, zero_altitude, excessive_speed, impossible_travel, reused_coordinates';
+COMMENT ON COLUMN godplan.attendances.is_suspicious IS 'True when fraud_score reached the approval threshold';
@@ -0,0 +1,28 @@
+-- Migration: Wi-Fi / BLE proof of presence
+-- Description: Register office Wi-Fi BSSIDs and BLE beacons as indoor alternatives to GPS, and record the proof method per attendance
+
+CREATE TABLE IF NOT EXISTS godplan.office_presence_signals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    office_location_id UUID NOT NULL REFERENCES godplan.office_locations(id) ON DELETE CASCADE,
+    signal_type VARCHAR(10) NOT NULL CHECK (signal_type IN ('wifi', 'ble')),
+    identifier VARCHAR(100) NOT NULL,
+    label VARCHAR(200),
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_presence_signal_per_tenant UNIQUE(tenant_id, signal_type, identifier)
+);
+
+CREATE INDEX IF NOT EXISTS idx_office_presence_signals_office ON godplan.office_presence_signals(office_location_id);
+CREATE INDEX IF NOT EXISTS idx_office_presence_signals_lookup ON godplan.office_presence_signals(tenant_id, signal_type, identifier)
+WHERE is_active = true;
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS presence_method VARCHAR(10),
+ADD COLUMN IF NOT EXISTS check_out_presence_method VARCHAR(10);
+
+COMMENT ON TABLE godplan.office_presence_signals IS 'Wi-Fi access points and BLE beacons that prove presence at an office';
+COMMENT ON COLUMN godplan.office_presence_signals.identifier IS 'Normalized BSSID (aa:bb:cc:dd:ee:ff) or beacon ID (lowercase, e.g. iBeacon uuid:major:minor)';
+COMMENT ON COLUMN godplan.attendances.presence_method IS 'How clock in presence was proven: gps, wifi, ble or force';
+COMMENT ON COLUMN godplan.attendances.check_out_presence_method IS 'How clock out presence was proven: gps, wifi, ble or force';
@@ -0,0 +1,29 @@
+-- Migration: QR kiosk attendance
+-- Description: Register reception kiosks that display a rotating signed QR code per office
+
+CREATE TABLE IF NOT EXISTS godplan.attendance_kiosks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    office_location_id UUID NOT NULL REFERENCES godplan.office_locations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    last_seen_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_kiosk_token_hash UNIQUE(token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_kiosks_tenant ON godplan.attendance_kiosks(tenant_id);
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS kiosk_id UUID REFERENCES godplan.attendance_kiosks(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS check_out_kiosk_id UUID REFERENCES godplan.attendance_kiosks(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE godplan.attendance_kiosks IS 'Reception tablets showing a rotating QR code that employees scan to clock in/out';
+COMMENT ON COLUMN godplan.attendance_kiosks.secret IS 'Hex HMAC key used to sign the QR code of each 30 second period, never returned by the API';
+COMMENT ON COLUMN godplan.attendance_kiosks.token_hash IS 'SHA-256 of the kiosk API token, the token itself is only shown once at registration';
+COMMENT ON COLUMN godplan.attendances.kiosk_id IS 'Kiosk whose QR code was scanned at clock in';
+COMMENT ON COLUMN godplan.attendances.check_out_kiosk_id IS 'Kiosk whose QR code was scanned at clock out';
+COMMENT ON COLUMN godplan.attendances.presence_method IS 'How clock in presence was proven: gps, wifi, ble, qr or force';
+COMMENT ON COLUMN godplan.attendances.check_out_presence_method IS 'How clock out presence was proven: gps, wifi, ble, qr or force';
@@ -0,0 +1,81 @@
+-- Migration: Leave and absence management
+-- Description: Tenant leave types (cuti, izin, sakit), yearly balances per employee and leave requests with approval
+
+CREATE TABLE IF NOT EXISTS godplan.leave_types (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    code VARCHAR(30) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_paid BOOLEAN DEFAULT true,
+    tracks_balance BOOLEAN DEFAULT false,
+    annual_quota INTEGER DEFAULT 0,
+    max_carry_over INTEGER DEFAULT 0,
+    requires_attachment BOOLEAN DEFAULT false,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_leave_type_code_per_tenant UNIQUE(tenant_id, code),
+    CONSTRAINT check_leave_type_quota CHECK (annual_quota >= 0 AND max_carry_over >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS godplan.leave_balances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES godplan.leave_types(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    entitled INTEGER NOT NULL DEFAULT 0,
+    carried_over INTEGER NOT NULL DEFAULT 0,
+    used INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_leave_balance_per_year UNIQUE(employee_id, leave_type_id, year)
+);
+
+CREATE TABLE IF NOT EXISTS godplan.leave_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES godplan.leave_types(id),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    days INTEGER NOT NULL,
+    reason TEXT,
+    attachment_key VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    rejection_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_leave_request_period CHECK (end_date >= start_date),
+    CONSTRAINT check_leave_request_status CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_types_tenant ON godplan.leave_types(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_leave_balances_employee_year ON godplan.leave_balances(employee_id, year);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_employee_period ON godplan.leave_requests(employee_id, start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_tenant_status ON godplan.leave_requests(tenant_id, status);
+
+-- Jenis cuti default untuk tenant yang sudah ada (tenant baru di-seed saat pertama kali membuka daftar jenis cuti)
+INSERT INTO godplan.leave_types (tenant_id, code, name, is_paid, tracks_balance, annual_quota, max_carry_over, requires_attachment)
+SELECT t.id, v.code, v.name, v.is_paid, v.tracks_balance, v.annual_quota, v.max_carry_over, v.requires_attachment
+FROM godplan.tenants t
+CROSS JOIN (VALUES
+    ('annual', 'Cuti Tahunan', true, true, 12, 0, false),
+    ('sick', 'Sakit', true, false, 0, 0, true),
+    ('permission', 'Izin', true, false, 0, 0, false),
+    ('unpaid', 'Cuti Tidak Dibayar', false, false, 0, 0, false)
+) AS v(code, name, is_paid, tracks_balance, annual_quota, max_carry_over, requires_attachment)
+ON CONFLICT (tenant_id, code) DO NOTHING;
+
+COMMENT ON TABLE godplan.leave_types IS 'Tenant leave types such as cuti tahunan, izin and sakit';
+COMMENT ON COLUMN godplan.leave_types.tracks_balance IS 'Requests are limited by the yearly balance (entitled + carried_over - used)';
+COMMENT ON COLUMN godplan.leave_types.annual_quota IS 'Days granted per year, prorated by join month in the first year';
+COMMENT ON COLUMN godplan.leave_types.max_carry_over IS 'Maximum unused days carried into the next year';
+COMMENT ON COLUMN godplan.leave_types.requires_attachment IS 'Requests need a supporting document, e.g. a doctor''s note for sick leave';
+COMMENT ON TABLE godplan.leave_balances IS 'Yearly leave balance per employee and leave type';
+COMMENT ON TABLE godplan.leave_requests IS 'Employee leave requests with supervisor approval';
+COMMENT ON COLUMN godplan.leave_requests.days IS 'Working days covered by the request according to the employee schedule';
+COMMENT ON COLUMN godplan.leave_requests.attachment_key IS 'Storage object key of the supporting document';
@@ -0,0 +1,29 @@
+-- Migration: Tenant working-day calendar
+-- Description: Working weekdays per tenant plus national holidays and company days off
+
+CREATE TABLE IF NOT EXISTS godplan.tenant_calendars (
+    tenant_id UUID PRIMARY KEY REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    working_days INTEGER NOT NULL DEFAULT 62,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_tenant_calendar_working_days CHECK (working_days BETWEEN 1 AND 127)
+);
+
+CREATE TABLE IF NOT EXISTS godplan.tenant_holidays (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    holiday_type VARCHAR(20) NOT NULL DEFAULT 'company',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_tenant_holiday_date UNIQUE(tenant_id, holiday_date),
+    CONSTRAINT check_tenant_holiday_type CHECK (holiday_type IN ('national', 'company'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_holidays_tenant_date ON godplan.tenant_holidays(tenant_id, holiday_date);
+
+COMMENT ON TABLE godplan.tenant_calendars IS 'Working weekdays of a tenant, used when a schedule does not set its own working days';
+COMMENT ON COLUMN godplan.tenant_calendars.working_days IS 'Bitmask of working weekdays, bit 0 = Sunday ... bit 6 = Saturday (62 = Monday-Friday)';
+COMMENT ON TABLE godplan.tenant_holidays IS 'Non-working dates of a tenant: imported national holidays and company-specific days off';
+COMMENT ON COLUMN godplan.tenant_holidays.holiday_type IS 'national (libur nasional / cuti bersama) or company (company-specific day off)';
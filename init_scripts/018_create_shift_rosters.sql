@@ -0,0 +1,51 @@
+-- Migration: Shift rostering
+-- Description: Per-day shift rosters (including night shifts that cross midnight), shift swaps with manager approval
+-- and attendances tied to the rostered shift instance
+
+CREATE TABLE IF NOT EXISTS godplan.shift_rosters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    shift_date DATE NOT NULL,
+    schedule_id UUID REFERENCES godplan.attendance_schedules(id) ON DELETE CASCADE,
+    notes VARCHAR(255),
+    created_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_shift_roster_per_day UNIQUE(employee_id, shift_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_rosters_tenant_date ON godplan.shift_rosters(tenant_id, shift_date);
+
+CREATE TABLE IF NOT EXISTS godplan.shift_swap_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    requester_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    requester_date DATE NOT NULL,
+    target_employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    target_date DATE NOT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    rejection_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_shift_swap_status CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    CONSTRAINT check_shift_swap_employees CHECK (requester_id <> target_employee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_swap_requests_pending
+ON godplan.shift_swap_requests(tenant_id) WHERE status = 'pending';
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS roster_id UUID REFERENCES godplan.shift_rosters(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_attendances_roster ON godplan.attendances(roster_id)
+WHERE roster_id IS NOT NULL;
+
+COMMENT ON TABLE godplan.shift_rosters IS 'Rostered shift of an employee per day. Overrides the assigned schedule on that day';
+COMMENT ON COLUMN godplan.shift_rosters.shift_date IS 'Date the shift starts. A night shift (end_time < start_time) ends on the next day';
+COMMENT ON COLUMN godplan.shift_rosters.schedule_id IS 'Schedule used as shift definition. NULL marks a rostered day off';
+COMMENT ON TABLE godplan.shift_swap_requests IS 'Shift swaps between two employees, applied to the roster once approved by the requester''s manager';
+COMMENT ON COLUMN godplan.attendances.roster_id IS 'Rostered shift instance of the attendance. attendance_date is the shift start date for rostered attendances';
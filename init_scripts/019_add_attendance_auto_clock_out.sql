@@ -0,0 +1,28 @@
+-- Migration: Automatic clock-out for forgotten sessions
+-- Description: Mark attendances closed by the auto clock-out job and add in-app notifications for employees
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS auto_closed BOOLEAN DEFAULT false,
+ADD COLUMN IF NOT EXISTS auto_closed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_attendances_open_sessions ON godplan.attendances(check_in_time)
+WHERE check_out_time IS NULL;
+
+CREATE TABLE IF NOT EXISTS godplan.notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    reference_id UUID,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON godplan.notifications(user_id, created_at DESC);
+
+COMMENT ON COLUMN godplan.attendances.auto_closed IS 'true when the session was closed by the auto clock-out job instead of the employee';
+COMMENT ON COLUMN godplan.attendances.auto_closed_at IS 'When the auto clock-out job closed the session. check_out_time holds the capped check out time';
+COMMENT ON TABLE godplan.notifications IS 'In-app notifications for users, e.g. sessions closed by auto clock-out';
+COMMENT ON COLUMN godplan.notifications.reference_id IS 'ID of the related record, e.g. the attendance for auto_clock_out notifications';
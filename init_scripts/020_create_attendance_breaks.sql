@@ -0,0 +1,29 @@
+-- Migration: Break tracking inside attendance sessions
+-- Description: Record lunch/prayer breaks of an open attendance and the break minutes subtracted from total_hours
+
+CREATE TABLE IF NOT EXISTS godplan.attendance_breaks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    attendance_id UUID NOT NULL REFERENCES godplan.attendances(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    break_type VARCHAR(20) NOT NULL DEFAULT 'other' CHECK (break_type IN ('lunch', 'prayer', 'other')),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    duration_minutes NUMERIC(10,2),
+    exceeded_minutes NUMERIC(10,2) DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_breaks_attendance ON godplan.attendance_breaks(attendance_id, started_at);
+
+-- Maksimal satu istirahat yang sedang berjalan per sesi absensi
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_breaks_open ON godplan.attendance_breaks(attendance_id)
+WHERE ended_at IS NULL;
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS break_minutes NUMERIC(10,2) DEFAULT 0;
+
+COMMENT ON TABLE godplan.attendance_breaks IS 'Break intervals inside an attendance session. Break time is not counted as worked time';
+COMMENT ON COLUMN godplan.attendance_breaks.ended_at IS 'NULL while the break is running. Closed at clock out when the employee forgot to end it';
+COMMENT ON COLUMN godplan.attendance_breaks.exceeded_minutes IS 'Minutes over the tenant break limits (per break or total per session)';
+COMMENT ON COLUMN godplan.attendances.break_minutes IS 'Total break minutes subtracted from total_hours';
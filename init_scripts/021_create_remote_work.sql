@@ -0,0 +1,43 @@
+-- Migration: Remote-work (WFH) days
+-- Description: Employee home locations, WFH requests with approval and the work mode of attendances
+
+CREATE TABLE IF NOT EXISTS godplan.employee_home_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL DEFAULT 'Rumah',
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude >= -90 AND latitude <= 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude >= -180 AND longitude <= 180),
+    radius INT NOT NULL DEFAULT 150 CHECK (radius > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT employee_home_locations_employee_unique UNIQUE (employee_id)
+);
+
+CREATE TABLE IF NOT EXISTS godplan.wfh_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    rejection_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT wfh_requests_period_check CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wfh_requests_employee ON godplan.wfh_requests(employee_id, start_date);
+CREATE INDEX IF NOT EXISTS idx_wfh_requests_pending ON godplan.wfh_requests(tenant_id, status)
+WHERE status = 'pending';
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS work_mode VARCHAR(20) DEFAULT 'office';
+
+COMMENT ON TABLE godplan.employee_home_locations IS 'Registered home geofence of an employee, used on approved WFH days';
+COMMENT ON TABLE godplan.wfh_requests IS 'Work-from-home requests. Approved days relax or replace the office geofence';
+COMMENT ON COLUMN godplan.attendances.work_mode IS 'office or wfh. wfh attendances are validated against the home geofence, or skip the geofence without a home location';
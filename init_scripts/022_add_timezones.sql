@@ -0,0 +1,15 @@
+-- Migration: Timezone-correct attendance dates
+-- Description: IANA timezone per tenant and office location, and the timezone each attendance date was computed in
+
+ALTER TABLE godplan.tenants
+ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta';
+
+ALTER TABLE godplan.office_locations
+ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+COMMENT ON COLUMN godplan.tenants.timezone IS 'IANA timezone of the tenant, used for greetings, dashboards and offices without their own timezone';
+COMMENT ON COLUMN godplan.office_locations.timezone IS 'IANA timezone of the office (NULL = tenant timezone)';
+COMMENT ON COLUMN godplan.attendances.timezone IS 'IANA timezone attendance_date, lateness and displayed times are computed in (office at clock-in, else tenant)';
@@ -0,0 +1,30 @@
+-- Migration: Offline clock-in queue
+-- Description: Idempotency log of clock events captured offline and synced in batches by the mobile app
+
+CREATE TABLE IF NOT EXISTS godplan.attendance_sync_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    client_event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('clock_in', 'clock_out')),
+    device_recorded_at TIMESTAMPTZ NOT NULL,
+    recorded_at TIMESTAMPTZ,
+    clock_drift_seconds INT NOT NULL DEFAULT 0,
+    result VARCHAR(20) NOT NULL DEFAULT 'processing' CHECK (result IN ('processing', 'accepted', 'rejected')),
+    message TEXT,
+    attendance_id UUID REFERENCES godplan.attendances(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT attendance_sync_events_client_unique UNIQUE (tenant_id, user_id, client_event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_sync_events_attendance ON godplan.attendance_sync_events(attendance_id);
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS is_offline BOOLEAN DEFAULT false;
+
+COMMENT ON TABLE godplan.attendance_sync_events IS 'Offline clock events by client idempotency key. A key is processed once, retries return the stored result';
+COMMENT ON COLUMN godplan.attendance_sync_events.device_recorded_at IS 'Event time reported by the device clock';
+COMMENT ON COLUMN godplan.attendance_sync_events.recorded_at IS 'Event time after removing the device clock drift measured at sync';
+COMMENT ON COLUMN godplan.attendance_sync_events.clock_drift_seconds IS 'Device clock minus server clock at sync. Beyond 5 minutes the attendance is flagged clock_drift';
+COMMENT ON COLUMN godplan.attendances.is_offline IS 'true when the clock in or clock out was captured offline and synced later';
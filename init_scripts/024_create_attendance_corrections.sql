@@ -0,0 +1,54 @@
+-- Migration: Attendance correction requests
+-- Description: Employee requests to correct clock in/out times of an attendance, and the audit trail of changed attendances
+
+CREATE TABLE IF NOT EXISTS godplan.attendance_correction_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    attendance_id UUID NOT NULL REFERENCES godplan.attendances(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    proposed_check_in_time TIMESTAMP,
+    proposed_check_out_time TIMESTAMP,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    rejection_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT attendance_correction_requests_times_check
+        CHECK (proposed_check_in_time IS NOT NULL OR proposed_check_out_time IS NOT NULL)
+);
+
+-- Satu attendance hanya boleh punya satu koreksi yang menunggu keputusan
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_correction_requests_pending_unique
+ON godplan.attendance_correction_requests(attendance_id)
+WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_attendance_correction_requests_employee
+ON godplan.attendance_correction_requests(employee_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attendance_correction_requests_status
+ON godplan.attendance_correction_requests(tenant_id, status);
+
+CREATE TABLE IF NOT EXISTS godplan.attendance_audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    attendance_id UUID NOT NULL REFERENCES godplan.attendances(id) ON DELETE CASCADE,
+    correction_request_id UUID REFERENCES godplan.attendance_correction_requests(id) ON DELETE SET NULL,
+    changed_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'correction',
+    old_check_in_time TIMESTAMP,
+    old_check_out_time TIMESTAMP,
+    old_total_hours NUMERIC(10,2),
+    old_auto_closed BOOLEAN,
+    new_check_in_time TIMESTAMP,
+    new_check_out_time TIMESTAMP,
+    new_total_hours NUMERIC(10,2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_audit_logs_attendance ON godplan.attendance_audit_logs(attendance_id, created_at);
+
+COMMENT ON TABLE godplan.attendance_correction_requests IS 'Employee requests to correct the clock in/out of an attendance, e.g. a forgotten clock out or a failed GPS fix';
+COMMENT ON COLUMN godplan.attendance_correction_requests.proposed_check_in_time IS 'Proposed clock in, NULL keeps the recorded clock in';
+COMMENT ON COLUMN godplan.attendance_correction_requests.proposed_check_out_time IS 'Proposed clock out, NULL keeps the recorded clock out';
+COMMENT ON TABLE godplan.attendance_audit_logs IS 'Original and new values of attendances changed after the fact, e.g. by an approved correction';
@@ -0,0 +1,40 @@
+-- Migration: Client visits
+-- Description: Geotagged check-in/check-out of sales visits to clients, linked to CRM deals and separate from office attendance
+
+CREATE TABLE IF NOT EXISTS godplan.client_visits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES godplan.projects(id) ON DELETE CASCADE,
+    client VARCHAR(200) NOT NULL,
+    visit_date DATE NOT NULL,
+    check_in_time TIMESTAMP NOT NULL,
+    check_in_lat DOUBLE PRECISION NOT NULL CHECK (check_in_lat >= -90 AND check_in_lat <= 90),
+    check_in_lng DOUBLE PRECISION NOT NULL CHECK (check_in_lng >= -180 AND check_in_lng <= 180),
+    check_in_accuracy DOUBLE PRECISION,
+    check_in_photo VARCHAR(500),
+    check_in_notes TEXT,
+    check_out_time TIMESTAMP,
+    check_out_lat DOUBLE PRECISION CHECK (check_out_lat >= -90 AND check_out_lat <= 90),
+    check_out_lng DOUBLE PRECISION CHECK (check_out_lng >= -180 AND check_out_lng <= 180),
+    check_out_accuracy DOUBLE PRECISION,
+    check_out_photo VARCHAR(500),
+    check_out_notes TEXT,
+    check_out_distance DOUBLE PRECISION,
+    duration_minutes NUMERIC(10,2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Maksimal satu kunjungan yang sedang berjalan per karyawan
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_visits_open ON godplan.client_visits(employee_id)
+WHERE check_out_time IS NULL;
+CREATE INDEX IF NOT EXISTS idx_client_visits_project ON godplan.client_visits(project_id, check_in_time);
+CREATE INDEX IF NOT EXISTS idx_client_visits_employee_date ON godplan.client_visits(employee_id, visit_date);
+
+COMMENT ON TABLE godplan.client_visits IS 'Sales visits to clients with geotagged check-in/check-out, photo and notes. Not counted as office attendance';
+COMMENT ON COLUMN godplan.client_visits.client IS 'Client visited, defaults to the client of the CRM deal';
+COMMENT ON COLUMN godplan.client_visits.visit_date IS 'Date of the check-in in the tenant timezone';
+COMMENT ON COLUMN godplan.client_visits.check_out_distance IS 'Meters between the check-in and check-out location';
+COMMENT ON COLUMN godplan.client_visits.duration_minutes IS 'Minutes between check-in and check-out';
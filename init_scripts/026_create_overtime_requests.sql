@@ -0,0 +1,41 @@
+-- Migration: Overtime requests
+-- Description: Pre-approved overtime windows, reconciled with actual clock out times and converted to compensable hours
+
+CREATE TABLE IF NOT EXISTS godplan.overtime_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES godplan.tenants(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL REFERENCES godplan.employees(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES godplan.users(id) ON DELETE CASCADE,
+    overtime_date DATE NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    planned_minutes NUMERIC(10,2) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    approved_by UUID REFERENCES godplan.users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    rejection_reason TEXT,
+    day_type VARCHAR(10) CHECK (day_type IN ('weekday', 'weekend', 'holiday')),
+    attendance_id UUID REFERENCES godplan.attendances(id) ON DELETE SET NULL,
+    actual_minutes NUMERIC(10,2) DEFAULT 0,
+    multiplier NUMERIC(4,2),
+    compensable_hours NUMERIC(10,2) DEFAULT 0,
+    reconciled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT overtime_requests_window_check CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_overtime_requests_employee
+ON godplan.overtime_requests(employee_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_overtime_requests_status
+ON godplan.overtime_requests(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_overtime_requests_user_window
+ON godplan.overtime_requests(user_id, start_time, end_time) WHERE status = 'approved';
+
+COMMENT ON TABLE godplan.overtime_requests IS 'Overtime requested ahead of time by employees and approved by their supervisor or admin/HR';
+COMMENT ON COLUMN godplan.overtime_requests.overtime_date IS 'Date of start_time in the tenant timezone';
+COMMENT ON COLUMN godplan.overtime_requests.day_type IS 'weekday, weekend or holiday, set on approval from the tenant calendar';
+COMMENT ON COLUMN godplan.overtime_requests.actual_minutes IS 'Approved minutes actually worked: overlap of the window with the clocked attendance sessions';
+COMMENT ON COLUMN godplan.overtime_requests.multiplier IS 'Tenant overtime multiplier of the day type used for compensable_hours';
+COMMENT ON COLUMN godplan.overtime_requests.compensable_hours IS 'actual_minutes / 60 * multiplier';
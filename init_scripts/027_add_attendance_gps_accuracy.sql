@@ -0,0 +1,12 @@
+-- Migration: GPS accuracy of attendance clock events
+-- Description: Store the reported (or fused) GPS accuracy so geofence changes can be replayed against past clock ins
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS check_in_accuracy DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS check_out_accuracy DOUBLE PRECISION;
+
+-- Geofence simulation scans clock ins of a tenant per date range
+CREATE INDEX IF NOT EXISTS idx_attendances_tenant_date ON godplan.attendances(tenant_id, attendance_date);
+
+COMMENT ON COLUMN godplan.attendances.check_in_accuracy IS 'GPS accuracy in meters at clock in, NULL when the device did not report it';
+COMMENT ON COLUMN godplan.attendances.check_out_accuracy IS 'GPS accuracy in meters at clock out, NULL when the device did not report it';
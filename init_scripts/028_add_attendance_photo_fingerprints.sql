@@ -0,0 +1,16 @@
+-- Migration: Selfie reuse detection for attendances
+-- Description: Store a perceptual hash and EXIF capture time per clock in/out selfie so reused or shared photos are flagged for review
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS check_in_photo_hash BIGINT,
+ADD COLUMN IF NOT EXISTS check_out_photo_hash BIGINT,
+ADD COLUMN IF NOT EXISTS check_in_photo_taken_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS check_out_photo_taken_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS photo_reuse_attendance_id UUID REFERENCES godplan.attendances(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN godplan.attendances.check_in_photo_hash IS '64-bit difference hash (dHash) of the clock in selfie';
+COMMENT ON COLUMN godplan.attendances.check_out_photo_hash IS '64-bit difference hash (dHash) of the clock out selfie';
+COMMENT ON COLUMN godplan.attendances.check_in_photo_taken_at IS 'EXIF capture time of the clock in selfie, NULL when the photo has no EXIF';
+COMMENT ON COLUMN godplan.attendances.check_out_photo_taken_at IS 'EXIF capture time of the clock out selfie, NULL when the photo has no EXIF';
+COMMENT ON COLUMN godplan.attendances.photo_reuse_attendance_id IS 'Earlier attendance whose selfie matched a selfie of this attendance';
+COMMENT ON COLUMN godplan.attendances.fraud_flags IS 'Triggered signals: mock_location, perfect_accuracy, zero_altitude, excessive_speed, impossible_travel, reused_coordinates, clock_drift, reused_photo, shared_photo, stale_photo';
@@ -0,0 +1,11 @@
+-- Migration: Location name of attendances
+-- Description: Store the name of the location validated at clock in, WFH attendances have no office_location_id
+
+ALTER TABLE godplan.attendances
+ADD COLUMN IF NOT EXISTS location_name VARCHAR(200);
+
+-- Attendance WFH lama tidak menyimpan nama lokasi rumah
+UPDATE godplan.attendances SET location_name = 'WFH'
+WHERE work_mode = 'wfh' AND office_location_id IS NULL AND location_name IS NULL;
+
+COMMENT ON COLUMN godplan.attendances.location_name IS 'Office or home location name at clock in; office names are read from office_locations, this column labels WFH attendances';
@@ -0,0 +1,11 @@
+-- Migration: Repeated clock in selfie at clock out
+-- Description: A clock out selfie matching the clock in selfie of the same attendance is flagged as repeated_photo instead of reused_photo pointing at itself
+
+-- Clock out lama mencocokkan selfie clock in attendance yang sama sebagai reused_photo
+UPDATE godplan.attendances
+SET fraud_flags = array_replace(fraud_flags, 'reused_photo', 'repeated_photo'),
+    photo_reuse_attendance_id = NULL
+WHERE photo_reuse_attendance_id = id;
+
+COMMENT ON COLUMN godplan.attendances.photo_reuse_attendance_id IS 'Earlier attendance whose selfie matched a selfie of this attendance, never the attendance itself';
+COMMENT ON COLUMN godplan.attendances.fraud_flags IS 'Triggered signals: mock_location, perfect_accuracy, zero_altitude, excessive_speed, impossible_travel, reused_coordinates, clock_drift, reused_photo, shared_photo, stale_photo, repeated_photo';
@@ -26,6 +26,24 @@ type Config struct {
 	OfficeLongitude        float64
 	AttendanceRadiusMeters float64
 	EnableLocationCheck    bool
+
+	// Photo storage untuk selfie absensi: "local" (default) atau "s3" (wajib di Vercel)
+	StorageDriver           string
+	StorageLocalPath        string
+	StoragePublicBaseURL    string // Prefix URL untuk signed URL storage local, kosong = relative path
+	StorageSigningSecret    string
+	StorageURLExpiryMinutes int
+
+	// S3-compatible object storage (AWS S3, MinIO, Cloudflare R2, dll)
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UsePathStyle    bool
+
+	// Secret untuk endpoint job terjadwal (Vercel Cron / scheduler eksternal), kosong = endpoint nonaktif
+	CronSecret string
 }
 
 // Variabel cache untuk environment
@@ -56,6 +74,26 @@ func Load() *Config {
 		OfficeLongitude:        getEnvFloat("OFFICE_LONGITUDE", 106.678055),      // GodJah Studio BSD (update via env if needed)
 		AttendanceRadiusMeters: getEnvFloat("ATTENDANCE_RADIUS_METERS", 5000),    // 5km radius for production flexibility
 		EnableLocationCheck:    getEnvBool("ENABLE_LOCATION_CHECK", true),
+
+		StorageDriver:           getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath:        getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		StoragePublicBaseURL:    getEnv("STORAGE_PUBLIC_BASE_URL", ""),
+		StorageSigningSecret:    getEnv("STORAGE_SIGNING_SECRET", ""),
+		StorageURLExpiryMinutes: getEnvInt("STORAGE_URL_EXPIRY_MINUTES", 15),
+
+		S3Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		S3UsePathStyle:    getEnvBool("S3_USE_PATH_STYLE", false),
+
+		CronSecret: getEnv("CRON_SECRET", ""),
+	}
+
+	// Signed URL storage local memakai JWT secret jika secret khusus tidak di-set
+	if cfg.StorageSigningSecret == "" {
+		cfg.StorageSigningSecret = cfg.JWTSecret
 	}
 
 	// Hanya log di development
@@ -84,6 +122,7 @@ func logConfig(cfg *Config) {
 	fmt.Printf("🏢 Office Location: %.6f, %.6f\n", cfg.OfficeLatitude, cfg.OfficeLongitude)
 	fmt.Printf("📏 Attendance Radius: %.0f meters\n", cfg.AttendanceRadiusMeters)
 	fmt.Printf("📍 Location Check Enabled: %t\n", cfg.EnableLocationCheck)
+	fmt.Printf("🗄️ Photo Storage Driver: %s\n", cfg.StorageDriver)
 
 	fmt.Println("✅ Configuration loaded successfully")
 }
@@ -178,7 +217,7 @@ func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
 		// Hanya log di development untuk non-sensitive values
-		if key != "DB_PASSWORD" && key != "JWT_SECRET" {
+		if !isSecretEnv(key) {
 			env := os.Getenv("ENV")
 			if env == "" || env == "development" {
 				fmt.Printf("⚙️ Using default for %s: %s\n", key, defaultValue)
@@ -188,7 +227,7 @@ func getEnv(key, defaultValue string) string {
 	}
 
 	// Hanya log di development
-	if key != "DB_PASSWORD" && key != "JWT_SECRET" {
+	if !isSecretEnv(key) {
 		env := os.Getenv("ENV")
 		if env == "" || env == "development" {
 			fmt.Printf("✅ %s: %s\n", key, value)
@@ -203,6 +242,30 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// isSecretEnv menandai environment variable yang nilainya tidak boleh di-log
+func isSecretEnv(key string) bool {
+	switch key {
+	case "DB_PASSWORD", "JWT_SECRET", "STORAGE_SIGNING_SECRET", "S3_SECRET_ACCESS_KEY", "CRON_SECRET":
+		return true
+	}
+	return false
+}
+
+// getEnvInt mendapatkan environment variable sebagai int
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		fmt.Printf("❌ Invalid int value for %s: %s, using default: %d\n", key, value, defaultValue)
+		return defaultValue
+	}
+	return result
+}
+
 // 🔥 NEW: getEnvFloat mendapatkan environment variable sebagai float64
 func getEnvFloat(key string, defaultValue float64) float64 {
 	value := os.Getenv(key)
@@ -247,6 +310,20 @@ func IsProduction() bool {
 	return result
 }
 
+// IsServerless mengecek apakah berjalan sebagai Vercel function (api/index.go)
+func IsServerless() bool {
+	return os.Getenv("VERCEL") != ""
+}
+
+// ValidateStorage memastikan foto selfie disimpan di storage yang persisten. Filesystem Vercel
+// read-only dan hilang setiap deploy, sehingga di serverless STORAGE_DRIVER wajib "s3".
+func (c *Config) ValidateStorage() error {
+	if IsServerless() && c.StorageDriver != "s3" {
+		return fmt.Errorf("STORAGE_DRIVER=s3 is required on Vercel, the local filesystem is not persistent (got %q)", c.StorageDriver)
+	}
+	return nil
+}
+
 // IsDevelopment mengecek environment
 func IsDevelopment() bool {
 	if isDevelopment != nil {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -9,50 +10,316 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/nepskuy/be-godplan/pkg/config"
 	"github.com/nepskuy/be-godplan/pkg/database"
 	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/service"
 	"github.com/nepskuy/be-godplan/pkg/utils"
 )
 
 type ClockInRequest struct {
-	Latitude    float64 `json:"latitude" example:"-6.2088"`
-	Longitude   float64 ` json:"longitude" example:"106.8456"`
-	PhotoSelfie string  `json:"photo_selfie" example:"base64_encoded_image"`
-	Force       bool    `json:"force" example:"false"`
-	Accuracy    float64 `json:"accuracy" example:"15.5"` // GPS accuracy in meters
+	Latitude    float64  `json:"latitude" example:"-6.2088"`
+	Longitude   float64  ` json:"longitude" example:"106.8456"`
+	PhotoSelfie string   `json:"photo_selfie" example:"base64_encoded_image"`
+	Force       bool     `json:"force" example:"false"`
+	Accuracy    float64  `json:"accuracy" example:"15.5"` // GPS accuracy in meters
+	Altitude    *float64 `json:"altitude,omitempty" example:"32.5"`
+	Heading     *float64 `json:"heading,omitempty" example:"90"`
+	Speed       *float64 `json:"speed,omitempty" example:"0.3"` // meters per second
+	IsMocked    bool     `json:"is_mocked" example:"false"`     // mock location flag reported by the OS
+
+	// Wi-Fi BSSIDs / BLE beacons yang terlihat device, membuktikan kehadiran saat GPS indoor meleset
+	models.PresenceProof
+	// KioskCode adalah isi QR code yang di-scan dari kiosk resepsionis
+	KioskCode string `json:"kiosk_code,omitempty" binding:"max=200"`
+	// Samples adalah beberapa reading GPS terakhir; jika diisi, posisi gabungannya menggantikan latitude/longitude/accuracy
+	Samples []utils.GPSSample `json:"samples,omitempty" binding:"max=10"`
 }
 
 type ClockOutRequest struct {
-	Latitude    float64 `json:"latitude" example:"-6.2088"`
-	Longitude   float64 `json:"longitude" example:"106.8456"`
-	PhotoSelfie string  `json:"photo_selfie" example:"base64_encoded_image"`
-	Force       bool    `json:"force" example:"false"`
-	Accuracy    float64 `json:"accuracy" example:"15.5"` // GPS accuracy in meters
+	Latitude    float64  `json:"latitude" example:"-6.2088"`
+	Longitude   float64  `json:"longitude" example:"106.8456"`
+	PhotoSelfie string   `json:"photo_selfie" example:"base64_encoded_image"`
+	Force       bool     `json:"force" example:"false"`
+	Accuracy    float64  `json:"accuracy" example:"15.5"` // GPS accuracy in meters
+	Altitude    *float64 `json:"altitude,omitempty" example:"32.5"`
+	Heading     *float64 `json:"heading,omitempty" example:"90"`
+	Speed       *float64 `json:"speed,omitempty" example:"0.3"` // meters per second
+	IsMocked    bool     `json:"is_mocked" example:"false"`     // mock location flag reported by the OS
+
+	// Wi-Fi BSSIDs / BLE beacons yang terlihat device, membuktikan kehadiran saat GPS indoor meleset
+	models.PresenceProof
+	// KioskCode adalah isi QR code yang di-scan dari kiosk resepsionis
+	KioskCode string `json:"kiosk_code,omitempty" binding:"max=200"`
+	// Samples adalah beberapa reading GPS terakhir; jika diisi, posisi gabungannya menggantikan latitude/longitude/accuracy
+	Samples []utils.GPSSample `json:"samples,omitempty" binding:"max=10"`
+}
+
+// applyGPSFusion menggabungkan samples dan menimpa reading tunggal dengan posisi gabungan.
+// deviceTime adalah waktu event menurut jam device, pembanding timestamp samples.
+// Mengembalikan nil jika request tidak mengirim samples.
+func applyGPSFusion(samples []utils.GPSSample, deviceTime time.Time, lat, lng, accuracy *float64) (*utils.GPSFusion, error) {
+	if len(samples) == 0 {
+		return nil, nil
+	}
+	fused, err := utils.FuseGPSSamples(samples, deviceTime)
+	if err != nil {
+		return nil, err
+	}
+	// Accuracy reading tunggal tetap ikut dinilai fraud scoring walaupun posisinya diganti
+	if *accuracy > 0 && (fused.MinRawAccuracy == 0 || *accuracy < fused.MinRawAccuracy) {
+		fused.MinRawAccuracy = *accuracy
+	}
+	*lat, *lng, *accuracy = fused.Latitude, fused.Longitude, fused.Accuracy
+	return &fused, nil
+}
+
+// rawAccuracy mengembalikan accuracy yang dilaporkan device untuk fraud scoring. Fused accuracy
+// dibatasi minimal 3m, sehingga reading mentah dipakai agar accuracy sempurna (< 1m) tetap terdeteksi.
+func rawAccuracy(accuracy float64, fusion *utils.GPSFusion) float64 {
+	if fusion != nil && fusion.MinRawAccuracy > 0 {
+		return fusion.MinRawAccuracy
+	}
+	return accuracy
+}
+
+func (r ClockInRequest) locationCheck(fusion *utils.GPSFusion) utils.LocationCheckWithAccuracy {
+	return utils.LocationCheckWithAccuracy{
+		Latitude: r.Latitude, Longitude: r.Longitude, Accuracy: rawAccuracy(r.Accuracy, fusion),
+		Altitude: r.Altitude, Heading: r.Heading, Speed: r.Speed, IsMocked: r.IsMocked,
+	}
+}
+
+func (r ClockOutRequest) locationCheck(fusion *utils.GPSFusion) utils.LocationCheckWithAccuracy {
+	return utils.LocationCheckWithAccuracy{
+		Latitude: r.Latitude, Longitude: r.Longitude, Accuracy: rawAccuracy(r.Accuracy, fusion),
+		Altitude: r.Altitude, Heading: r.Heading, Speed: r.Speed, IsMocked: r.IsMocked,
+	}
+}
+
+// findPresenceOffice mencari kantor aktif yang sinyal Wi-Fi/BLE-nya terlihat oleh device.
+// Mengembalikan nil jika tidak ada sinyal terdaftar yang cocok.
+func findPresenceOffice(tenantID uuid.UUID, offices []models.OfficeLocation, proof models.PresenceProof) (*models.OfficeLocation, string) {
+	presence := getOfficeLocationService().MatchPresence(tenantID, proof)
+	if presence == nil {
+		return nil, ""
+	}
+	for i := range offices {
+		if offices[i].ID == presence.OfficeLocationID {
+			return &offices[i], presence.Method
+		}
+	}
+	return nil, ""
+}
+
+// attendanceOffice adalah kantor hasil resolve clock in/out beserta bukti kehadirannya
+type attendanceOffice struct {
+	utils.OfficeMatch
+	PresenceMethod string     // gps, wifi, ble, qr, force
+	KioskID        *uuid.UUID // kiosk yang QR code-nya di-scan
+}
+
+// resolveAttendanceOffice menentukan kantor dan metode bukti kehadiran untuk clock in/out.
+// QR code kiosk dan sinyal Wi-Fi/BLE kantor dianggap in range walaupun GPS indoor meleset;
+// jika tidak ada bukti lain dipakai kantor terdekat berdasarkan GPS.
+// QR code yang tidak valid atau kedaluwarsa pada waktu event (at) mengembalikan utils.ErrInvalidKioskCode.
+// Toleransi GPS mengikuti attendance policy tenant.
+func resolveAttendanceOffice(tenantID uuid.UUID, policy models.AttendancePolicy, lat, lng, accuracy float64, proof models.PresenceProof, kioskCode string, at time.Time) (attendanceOffice, error) {
+	offices, err := getOfficeLocationService().GetGeofences(tenantID)
+	if err != nil {
+		return attendanceOffice{}, err
+	}
+
+	if kioskCode != "" {
+		kiosk, err := getKioskService().VerifyCode(tenantID, kioskCode, at)
+		if err != nil {
+			return attendanceOffice{}, err
+		}
+		for _, office := range offices {
+			if office.ID != kiosk.OfficeLocationID {
+				continue
+			}
+			match := utils.FindNearestOfficeWithPolicy(policy, []models.OfficeLocation{office}, lat, lng, accuracy)
+			match.InRange = true
+			return attendanceOffice{OfficeMatch: match, PresenceMethod: models.PresenceMethodQR, KioskID: &kiosk.ID}, nil
+		}
+		return attendanceOffice{}, utils.ErrInvalidKioskCode
+	}
+
+	if office, method := findPresenceOffice(tenantID, offices, proof); office != nil {
+		match := utils.FindNearestOfficeWithPolicy(policy, []models.OfficeLocation{*office}, lat, lng, accuracy)
+		match.InRange = true
+		return attendanceOffice{OfficeMatch: match, PresenceMethod: method}, nil
+	}
+
+	match := utils.FindNearestOfficeWithPolicy(policy, offices, lat, lng, accuracy)
+	return gpsAttendanceOffice(policy, match), nil
 }
 
+// gpsAttendanceOffice memberi metode bukti kehadiran untuk match GPS. Overage di bawah margin
+// auto-approve policy tenant dianggap in range (kemungkinan error GPS) sehingga tidak butuh force.
+func gpsAttendanceOffice(policy models.AttendancePolicy, match utils.OfficeMatch) attendanceOffice {
+	switch utils.GeofenceOutcome(policy, match) {
+	case models.GeofenceOutcomeApproved:
+		return attendanceOffice{OfficeMatch: match, PresenceMethod: models.PresenceMethodGPS}
+	case models.GeofenceOutcomeAutoApproved:
+		match.InRange = true
+		return attendanceOffice{OfficeMatch: match, PresenceMethod: models.PresenceMethodGPS}
+	default:
+		return attendanceOffice{OfficeMatch: match, PresenceMethod: models.PresenceMethodForce}
+	}
+}
+
+// resolveWorkLocation menentukan lokasi kerja dan work mode clock in/out pada tanggal kerja.
+// Pada hari WFH yang disetujui, kantor tetap diterima jika karyawan ternyata datang ke kantor;
+// selain itu lokasi divalidasi terhadap geofence rumah, atau geofence dilewati jika karyawan
+// belum mendaftarkan lokasi rumah (kecuali tenant mewajibkannya: service.ErrHomeLocationNeeded).
+func resolveWorkLocation(tenantID, userID uuid.UUID, policy models.AttendancePolicy, workDate string, lat, lng, accuracy float64, proof models.PresenceProof, kioskCode string, at time.Time) (attendanceOffice, string, error) {
+	resolved, err := resolveAttendanceOffice(tenantID, policy, lat, lng, accuracy, proof, kioskCode, at)
+	if err != nil {
+		return resolved, models.WorkModeOffice, err
+	}
+	return resolveRemoteWork(tenantID, userID, policy, workDate, resolved, lat, lng, accuracy)
+}
+
+// resolveRemoteWork melanjutkan resolveAttendanceOffice untuk tanggal kerja: kantor yang
+// in range tetap dipakai, di luar kantor dicek apakah tanggal tersebut hari WFH yang disetujui
+func resolveRemoteWork(tenantID, userID uuid.UUID, policy models.AttendancePolicy, workDate string, resolved attendanceOffice, lat, lng, accuracy float64) (attendanceOffice, string, error) {
+	if resolved.InRange {
+		return resolved, models.WorkModeOffice, nil
+	}
+
+	remote, err := getRemoteWorkService().GetRemoteWorkDay(tenantID, userID, workDate)
+	if err != nil || remote == nil {
+		return resolved, models.WorkModeOffice, err
+	}
+
+	if remote.Home == nil {
+		if remote.RequireHomeLocation {
+			return resolved, models.WorkModeWFH, service.ErrHomeLocationNeeded
+		}
+		match := utils.OfficeMatch{Office: &models.OfficeLocation{Name: models.WFHLocationName, IsActive: true}, InRange: true}
+		return attendanceOffice{OfficeMatch: match, PresenceMethod: models.PresenceMethodRemote}, models.WorkModeWFH, nil
+	}
+
+	home := homeGeofence(remote.Home)
+	match := utils.FindNearestOfficeWithPolicy(policy, []models.OfficeLocation{home}, lat, lng, accuracy)
+	return gpsAttendanceOffice(policy, match), models.WorkModeWFH, nil
+}
+
+// homeGeofence memakai lokasi rumah sebagai geofence lingkaran. ID dikosongkan agar
+// office_location_id attendance tetap NULL.
+func homeGeofence(home *models.HomeLocation) models.OfficeLocation {
+	return models.OfficeLocation{
+		TenantID:  home.TenantID,
+		Name:      home.Name,
+		Latitude:  home.Latitude,
+		Longitude: home.Longitude,
+		Radius:    home.Radius,
+		IsActive:  true,
+	}
+}
+
+// clockEvent adalah waktu clock in/out yang dicatat. Event offline memakai waktu device
+// yang sudah dikoreksi dengan selisih jam device terhadap server (ClockDrift).
+type clockEvent struct {
+	At         time.Time
+	Offline    bool
+	ClockDrift time.Duration
+}
+
+// clockError adalah kegagalan clock in/out beserta HTTP status untuk response
+type clockError struct {
+	Status  int
+	Message string
+}
+
+func (e *clockError) Error() string {
+	return e.Message
+}
+
+// workLocationError mengubah kegagalan resolveWorkLocation menjadi clockError
+func workLocationError(err error) *clockError {
+	switch err {
+	case utils.ErrInvalidKioskCode:
+		return &clockError{http.StatusBadRequest, "QR code kiosk tidak valid atau sudah kedaluwarsa, silakan scan ulang"}
+	case service.ErrHomeLocationNeeded:
+		return &clockError{http.StatusBadRequest, "Hari ini Anda WFH, daftarkan lokasi rumah terlebih dahulu sebelum Clock In"}
+	default:
+		return &clockError{http.StatusInternalServerError, "Failed to validate attendance location"}
+	}
+}
+
+// outOfRangeMessage menjelaskan posisi user terhadap geofence kantor atau rumah (WFH)
+func outOfRangeMessage(match utils.OfficeMatch, workMode string, accuracy float64, allowForce bool) string {
+	if workMode == models.WorkModeWFH {
+		message := fmt.Sprintf("Hari ini Anda WFH, tetapi lokasi di luar jangkauan lokasi rumah %s. Jarak: %.0fm | Jangkauan adaptive: %.0fm (GPS accuracy: %.0fm).",
+			match.OfficeName(), match.Distance, match.AdaptiveRadius, accuracy)
+		if allowForce {
+			message += " Gunakan force=true jika Anda yakin sudah di rumah."
+		}
+		return message
+	}
+	message := fmt.Sprintf("Lokasi di luar jangkauan %s. Jarak: %.0fm | Jangkauan adaptive: %.0fm (GPS accuracy: %.0fm).",
+		match.OfficeName(), match.Distance, match.AdaptiveRadius, accuracy)
+	if allowForce {
+		message += " Gunakan force=true jika Anda yakin sudah di kantor."
+	}
+	return message
+}
+
+// forceNotAllowedMessage dipakai jika tenant mematikan force attendance
+const forceNotAllowedMessage = "Clock In/Out di luar jangkauan dengan force tidak diizinkan oleh kebijakan perusahaan"
+
+// selfieRequiredMessage dipakai jika tenant mewajibkan selfie saat clock in/out
+const selfieRequiredMessage = "Foto selfie wajib dilampirkan untuk Clock In/Out"
+
 type LocationCheckRequest struct {
 	Latitude  float64 `json:"latitude" example:"-6.2088"`
 	Longitude float64 `json:"longitude" example:"106.8456"`
 	Accuracy  float64 `json:"accuracy" example:"15.5"` // GPS accuracy in meters
+
+	models.PresenceProof
+	// Samples adalah beberapa reading GPS terakhir; jika diisi, posisi gabungannya menggantikan latitude/longitude/accuracy
+	Samples []utils.GPSSample `json:"samples,omitempty" binding:"max=10"`
 }
 
 type AttendanceResponse struct {
-	ID              uuid.UUID `json:"id"`
-	UserID          uuid.UUID `json:"user_id"`
-	Type            string    `json:"type"`
-	Status          string    `json:"status"`
-	Date            string    `json:"date"`           // Added for mobile
-	Time            string    `json:"time"`           // Added for mobile
-	LocationName    string    `json:"location_name"` // Added for mobile
-	Latitude        float64   `json:"latitude"`
-	Longitude       float64   `json:"longitude"`
-	PhotoSelfie     string    `json:"photo_selfie,omitempty"`
-	InRange         bool      `json:"in_range"`
-	ForceAttendance bool      `json:"force_attendance"`
-	CreatedAt       time.Time `json:"created_at"`
-	Distance        float64   `json:"distance,omitempty"`
-	MaxRadius       float64   `json:"max_radius,omitempty"`
+	ID                uuid.UUID  `json:"id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	Type              string     `json:"type"`
+	Status            string     `json:"status"`
+	Date              string     `json:"date"`          // Added for mobile
+	Time              string     `json:"time"`          // Added for mobile
+	LocationName      string     `json:"location_name"` // Added for mobile
+	Latitude          float64    `json:"latitude"`
+	Longitude         float64    `json:"longitude"`
+	PhotoURL          string     `json:"photo_url,omitempty"`
+	CheckOutPhotoURL  string     `json:"check_out_photo_url,omitempty"`
+	InRange           bool       `json:"in_range"`
+	ForceAttendance   bool       `json:"force_attendance"`
+	PresenceMethod    string     `json:"presence_method,omitempty"` // gps, wifi, ble, qr, remote, force
+	WorkMode          string     `json:"work_mode,omitempty"`       // office, wfh
+	RosterID          *uuid.UUID `json:"roster_id,omitempty"`
+	ShiftDate         string     `json:"shift_date,omitempty"`  // start date of the rostered shift
+	AutoClosed        bool       `json:"auto_closed,omitempty"` // closed by auto clock-out, not by the employee
+	Offline           bool       `json:"offline,omitempty"`     // clock in or out synced from the offline queue
+	CreatedAt         time.Time  `json:"created_at"`
+	Distance          float64    `json:"distance,omitempty"`
+	MaxRadius         float64    `json:"max_radius,omitempty"`
+	LateMinutes       float64    `json:"late_minutes"`
+	EarlyLeaveMinutes float64    `json:"early_leave_minutes"`
+	OvertimeMinutes   float64    `json:"overtime_minutes"`
+	TotalHours        float64    `json:"total_hours,omitempty"`
+	BreakMinutes      float64    `json:"break_minutes,omitempty"`
+	ApprovedBy        *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedByName    string     `json:"approved_by_name,omitempty"`
+	ApprovedAt        *time.Time `json:"approved_at,omitempty"`
+	RejectionReason   string     `json:"rejection_reason,omitempty"`
+
+	// FusedPosition adalah posisi gabungan jika request mengirim beberapa reading GPS
+	FusedPosition *utils.GPSFusion `json:"fused_position,omitempty"`
 }
 
 // CheckLocation godoc
@@ -89,8 +356,63 @@ func CheckLocation(c *gin.Context) {
 		return
 	}
 
-	// 🚀 NEW: Gunakan adaptive location validation dengan GPS accuracy
-	validation := utils.ValidateLocationAdaptive(req.Latitude, req.Longitude, req.Accuracy)
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	// 🚀 NEW: Gunakan adaptive location validation dengan GPS accuracy terhadap kantor terdekat
+	// Beberapa reading GPS digabung dulu, fix pertama yang meleset tidak menentukan hasil
+	fusion, err := applyGPSFusion(req.Samples, time.Now(), &req.Latitude, &req.Longitude, &req.Accuracy)
+	if err != nil {
+		utils.GinErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy := tenantAttendancePolicy(tenantID)
+	offices, err := getOfficeLocationService().GetGeofences(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, http.StatusInternalServerError, "Failed to fetch office locations")
+		return
+	}
+	validation := utils.ValidateLocationWithPolicy(policy, offices, req.Latitude, req.Longitude, req.Accuracy)
+	presenceMethod := models.PresenceMethodGPS
+
+	// Sinyal Wi-Fi/BLE kantor yang terdaftar membuktikan kehadiran walaupun GPS indoor meleset
+	if office, method := findPresenceOffice(tenantID, offices, req.PresenceProof); office != nil {
+		validation = utils.ValidateLocationWithPolicy(policy, []models.OfficeLocation{*office}, req.Latitude, req.Longitude, req.Accuracy)
+		if !validation.InRange || validation.NeedForce {
+			validation.InRange = true
+			validation.NeedForce = false
+			validation.Message = fmt.Sprintf("Lokasi valid, terdeteksi sinyal %s %s", presenceMethodLabel(method), office.Name)
+			validation.Recommendation = ""
+		}
+		presenceMethod = method
+	}
+
+	// Hari WFH yang disetujui: di luar kantor, lokasi divalidasi terhadap geofence rumah
+	workMode := models.WorkModeOffice
+	if !validation.InRange {
+		nearest := utils.FindNearestOfficeWithPolicy(policy, offices, req.Latitude, req.Longitude, req.Accuracy)
+		today := time.Now().In(getTimezoneService().OfficeLocation(tenantID, nearest.Office)).Format("2006-01-02")
+		if remote, err := getRemoteWorkService().GetRemoteWorkDay(tenantID, userID, today); err == nil && remote != nil {
+			workMode = models.WorkModeWFH
+			switch {
+			case remote.Home != nil:
+				validation = utils.ValidateLocationWithPolicy(policy, []models.OfficeLocation{homeGeofence(remote.Home)}, req.Latitude, req.Longitude, req.Accuracy)
+			case !remote.RequireHomeLocation:
+				validation.InRange = true
+				validation.NeedForce = false
+				validation.Message = "Hari ini Anda WFH, lokasi tidak perlu divalidasi"
+				validation.Recommendation = ""
+				presenceMethod = models.PresenceMethodRemote
+			default:
+				validation.Message = "Hari ini Anda WFH, daftarkan lokasi rumah terlebih dahulu sebelum Clock In"
+			}
+		}
+	}
+
+	validation.FusedPosition = fusion
 
 	// Enhanced response dengan informasi GPS quality
 	response := map[string]interface{}{
@@ -104,11 +426,26 @@ func CheckLocation(c *gin.Context) {
 		"gps_accuracy":     validation.GPSAccuracy,
 		"gps_quality":      validation.GPSQuality,
 		"recommendation":   validation.Recommendation,
+		"office_id":        validation.OfficeID,
+		"office_name":      validation.OfficeName,
+		"presence_method":  presenceMethod,
+		"work_mode":        workMode,
+		"allow_force":      policy.AllowForce,
+		"require_selfie":   policy.RequireSelfie,
+		"fused_position":   validation.FusedPosition,
 	}
 
 	utils.GinSuccessResponse(c, http.StatusOK, "Location validation successful", response)
 }
 
+// presenceMethodLabel mengembalikan nama sinyal untuk pesan ke user
+func presenceMethodLabel(method string) string {
+	if method == models.PresenceMethodBLE {
+		return "beacon BLE"
+	}
+	return "Wi-Fi"
+}
+
 // ClockIn godoc
 // @Summary Clock in attendance
 // @Description Record user clock-in with location validation
@@ -173,15 +510,64 @@ func ClockIn(c *gin.Context) {
 		return
 	}
 
-	// Validate location with adaptive threshold based on GPS accuracy
-	inRange, distance, adaptiveRadius := utils.IsWithinOfficeRangeAdaptive(req.Latitude, req.Longitude, req.Accuracy)
-	cfg := config.Load()
+	response, message, clockErr := recordClockIn(c.Request.Context(), tenantID, userID, req, clockEvent{At: time.Now()})
+	if clockErr != nil {
+		utils.GinErrorResponse(c, clockErr.Status, clockErr.Message)
+		return
+	}
+
+	utils.GinSuccessResponse(c, http.StatusCreated, message, response)
+}
+
+// recordClockIn memvalidasi lokasi dan mencatat clock in pada waktu event.
+// Dipakai oleh ClockIn dan oleh sinkronisasi event offline.
+func recordClockIn(ctx context.Context, tenantID, userID uuid.UUID, req ClockInRequest, event clockEvent) (*AttendanceResponse, string, *clockError) {
+	// Beberapa reading GPS digabung dulu, fix pertama yang meleset tidak menentukan hasil
+	fusion, err := applyGPSFusion(req.Samples, event.At.Add(event.ClockDrift), &req.Latitude, &req.Longitude, &req.Accuracy)
+	if err != nil {
+		return nil, "", &clockError{http.StatusBadRequest, err.Error()}
+	}
+
+	now := event.At
+	policy := tenantAttendancePolicy(tenantID)
+	if policy.RequireSelfie && req.PhotoSelfie == "" {
+		return nil, "", &clockError{http.StatusBadRequest, selfieRequiredMessage}
+	}
+
+	// Validate location with kiosk QR code, Wi-Fi/BLE proof or adaptive GPS threshold against the nearest active office
+	office, err := resolveAttendanceOffice(tenantID, policy, req.Latitude, req.Longitude, req.Accuracy, req.PresenceProof, req.KioskCode, now)
+	if err != nil {
+		return nil, "", workLocationError(err)
+	}
+
+	// Tanggal attendance, shift dan keterlambatan dihitung dalam timezone kantor (localNow),
+	// bukan timezone server. now tetap dipakai untuk kolom timestamp di database.
+	loc := attendanceTimezone(tenantID, office)
+	localNow := now.In(loc)
+
+	// Karyawan shift: attendance terikat ke shift yang sedang berjalan (termasuk shift malam
+	// yang melewati tengah malam), bukan ke tanggal kalender
+	shift := getRosterService().FindShiftInstance(tenantID, userID, localNow)
+	var rosterID *uuid.UUID
+	workDate := localNow.Format("2006-01-02")
+	if shift != nil {
+		rosterID = &shift.RosterID
+		workDate = shift.ShiftDate
+	}
+
+	// Di luar kantor pada hari WFH yang disetujui, lokasi divalidasi terhadap geofence rumah
+	resolved, workMode, err := resolveRemoteWork(tenantID, userID, policy, workDate, office, req.Latitude, req.Longitude, req.Accuracy)
+	if err != nil {
+		return nil, "", workLocationError(err)
+	}
+	match, presenceMethod := resolved.OfficeMatch, resolved.PresenceMethod
+	inRange, distance := match.InRange, match.Distance
 
 	if !inRange && !req.Force {
-		utils.GinErrorResponse(c, http.StatusBadRequest,
-			fmt.Sprintf("Lokasi di luar jangkauan kantor. Jarak: %.0fm | Jangkauan adaptive: %.0fm (GPS accuracy: %.0fm). Gunakan force=true jika Anda yakin sudah di kantor.",
-				distance, adaptiveRadius, req.Accuracy))
-		return
+		return nil, "", &clockError{http.StatusBadRequest, outOfRangeMessage(match, workMode, req.Accuracy, policy.AllowForce)}
+	}
+	if !inRange && !policy.AllowForce {
+		return nil, "", &clockError{http.StatusBadRequest, forceNotAllowedMessage}
 	}
 
 	// Determine status
@@ -190,39 +576,93 @@ func ClockIn(c *gin.Context) {
 		status = "pending_forced"
 	}
 
-	// Check if already clocked in today
+	// Check if already clocked in today (or for this shift)
 	var existingID uuid.UUID
 	checkErr := database.DB.QueryRow(
 		`SELECT id FROM godplan.attendances 
-		 WHERE user_id = $1 AND tenant_id = $2 AND attendance_date = CURRENT_DATE`,
-		userID, tenantID,
+		 WHERE user_id = $1 AND tenant_id = $2
+		 AND (($3::uuid IS NOT NULL AND roster_id = $3) OR ($3::uuid IS NULL AND attendance_date = $4))`,
+		userID, tenantID, rosterID, workDate,
 	).Scan(&existingID)
 
 	if checkErr == nil {
-		utils.GinErrorResponse(c, http.StatusBadRequest, "Sudah melakukan Clock In hari ini")
-		return
+		if shift != nil {
+			return nil, "", &clockError{http.StatusBadRequest, "Sudah melakukan Clock In untuk shift ini"}
+		}
+		return nil, "", &clockError{http.StatusBadRequest, "Sudah melakukan Clock In hari ini"}
+	}
+
+	// Deteksi GPS spoofing. Clock in mencurigakan tetap dicatat tapi menunggu approval supervisor.
+	// Event offline dari device yang jamnya meleset jauh juga menunggu approval.
+	fraud := getAttendanceService().AssessClockEvent(tenantID, userID, req.locationCheck(fusion), now, workDate)
+	if event.Offline {
+		fraud = utils.MergeFraudAssessments(fraud, utils.AssessClockDrift(event.ClockDrift))
+	}
+
+	// Selfie yang pernah diupload (oleh user sendiri atau karyawan lain) atau diambil jauh
+	// dari waktu event juga dinilai, sehingga attendance masuk review supervisor
+	selfie, err := decodeAttendanceSelfie(req.PhotoSelfie, loc)
+	if err != nil {
+		return nil, "", photoError(err)
+	}
+	var photoMatch *models.PhotoMatch
+	if selfie != nil {
+		var selfieFraud models.FraudAssessment
+		selfieFraud, photoMatch = getAttendanceService().AssessSelfie(tenantID, userID, nil, nil, selfie.Hash, selfie.TakenAt, now)
+		fraud = utils.MergeFraudAssessments(fraud, selfieFraud)
+	}
+	if fraud.Suspicious && status == models.AttendanceStatusApproved {
+		status = models.AttendanceStatusPending
+	}
+
+	// Hitung keterlambatan dari jadwal karyawan (atau jadwal default tenant).
+	// Clock in pada hari libur tenant tidak pernah dihitung terlambat.
+	var scheduleID *uuid.UUID
+	var lateMinutes float64
+	if shift != nil {
+		// Shift dari roster selalu hari kerja, keterlambatan dihitung dari awal shift
+		scheduleID = &shift.Schedule.ID
+		if start, err := utils.ParseShiftDate(shift.ShiftDate, loc); err == nil {
+			lateMinutes = utils.CalculateShiftLateMinutes(&shift.Schedule, start, localNow)
+		}
+	} else if schedule := getScheduleService().GetScheduleForUser(tenantID, userID); schedule != nil {
+		scheduleID = &schedule.ID
+		if resolved, workingDay := getCalendarService().ResolveSchedule(tenantID, schedule, localNow); workingDay {
+			lateMinutes = utils.CalculateLateMinutes(resolved, localNow)
+		}
+	}
+
+	// Selfie disimpan di blob storage, row hanya menyimpan object key
+	photoKey, err := storeAttendancePhoto(ctx, tenantID, userID, "checkin", selfie, now)
+	if err != nil {
+		return nil, "", photoError(err)
 	}
 
 	// Insert new attendance record with correct schema columns
 	var attendanceID uuid.UUID
-	now := time.Now()
 	err = database.DB.QueryRow(
 		`INSERT INTO godplan.attendances (
 			tenant_id, user_id, type, status, 
 			check_in_time, check_in_lat, check_in_lng, check_in_photo,
-			attendance_date, in_range, force_attendance, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_DATE, $9, $10, $11) RETURNING id`,
+			attendance_date, in_range, force_attendance, office_location_id,
+			schedule_id, late_minutes, fraud_score, fraud_flags, is_suspicious, presence_method, kiosk_id, created_at, roster_id, work_mode, timezone, is_offline,
+			check_in_accuracy, check_in_photo_hash, check_in_photo_taken_at, photo_reuse_attendance_id, location_name
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $20, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $21, $22, $23, $24, $25, $26, $27, $28, $29) RETURNING id`,
 		tenantID, userID, "CheckIn", status,
-		now, req.Latitude, req.Longitude, req.PhotoSelfie,
-		inRange, req.Force, now,
+		now, req.Latitude, req.Longitude, sql.NullString{String: photoKey, Valid: photoKey != ""},
+		inRange, req.Force, match.OfficeID(),
+		scheduleID, lateMinutes, fraud.Score, pq.Array(fraud.Flags), fraud.Suspicious, presenceMethod, resolved.KioskID, now,
+		workDate, rosterID, workMode, loc.String(), event.Offline,
+		sql.NullFloat64{Float64: req.Accuracy, Valid: req.Accuracy > 0},
+		selfieHash(selfie), selfieTakenAt(selfie), photoMatchID(photoMatch), match.OfficeName(),
 	).Scan(&attendanceID)
 
 	if err != nil {
 		if config.IsDevelopment() {
 			fmt.Printf("🔴 ClockIn database error: %v\n", err)
 		}
-		utils.GinErrorResponse(c, http.StatusInternalServerError, "Failed to clock in: "+err.Error())
-		return
+		deleteAttendancePhoto(photoKey)
+		return nil, "", &clockError{http.StatusInternalServerError, "Failed to clock in: " + err.Error()}
 	}
 
 	response := AttendanceResponse{
@@ -230,17 +670,32 @@ func ClockIn(c *gin.Context) {
 		UserID:          userID,
 		Type:            "CheckIn",
 		Status:          status,
+		LocationName:    match.OfficeName(),
 		Latitude:        req.Latitude,
 		Longitude:       req.Longitude,
-		PhotoSelfie:     req.PhotoSelfie,
+		PhotoURL:        attendancePhotoURL(photoKey),
 		InRange:         inRange,
 		ForceAttendance: req.Force,
-		CreatedAt:       now,
+		PresenceMethod:  presenceMethod,
+		WorkMode:        workMode,
+		CreatedAt:       localNow,
 		Distance:        distance,
-		MaxRadius:       cfg.AttendanceRadiusMeters,
+		MaxRadius:       match.BaseRadius(),
+		LateMinutes:     lateMinutes,
+		RosterID:        rosterID,
+		Offline:         event.Offline,
+		FusedPosition:   fusion,
+	}
+	if shift != nil {
+		response.ShiftDate = shift.ShiftDate
 	}
 
-	utils.GinSuccessResponse(c, http.StatusCreated, "Clock in successful", response)
+	message := "Clock in successful"
+	if fraud.Suspicious {
+		message = "Clock in recorded and waiting for supervisor approval"
+	}
+
+	return &response, message, nil
 }
 
 // ClockOut godoc
@@ -304,40 +759,134 @@ func ClockOut(c *gin.Context) {
 		return
 	}
 
-	// Validate location with adaptive threshold based on GPS accuracy
-	inRange, distance, adaptiveRadius := utils.IsWithinOfficeRangeAdaptive(req.Latitude, req.Longitude, req.Accuracy)
-	cfg := config.Load()
-
-	if !inRange && !req.Force {
-		utils.GinErrorResponse(c, http.StatusBadRequest,
-			fmt.Sprintf("Lokasi di luar jangkauan kantor. Jarak: %.0fm | Jangkauan adaptive: %.0fm (GPS accuracy: %.0fm). Gunakan force=true jika Anda yakin sudah di kantor.",
-				distance, adaptiveRadius, req.Accuracy))
+	response, message, clockErr := recordClockOut(c.Request.Context(), tenantID, userID, req, clockEvent{At: time.Now()})
+	if clockErr != nil {
+		utils.GinErrorResponse(c, clockErr.Status, clockErr.Message)
 		return
 	}
 
+	utils.GinSuccessResponse(c, http.StatusOK, message, response)
+}
+
+// recordClockOut memvalidasi lokasi dan menutup sesi clock in yang aktif pada waktu event.
+// Dipakai oleh ClockOut dan oleh sinkronisasi event offline.
+func recordClockOut(ctx context.Context, tenantID, userID uuid.UUID, req ClockOutRequest, event clockEvent) (*AttendanceResponse, string, *clockError) {
 	// Find existing clock-in record (latest active session, even from previous days)
 	var attendanceID uuid.UUID
 	var checkInTime time.Time
+	var currentStatus string
+	var scheduleID, rosterID uuid.NullUUID
+	var attendanceDate, timezone string
+	var checkInFraud models.FraudAssessment
+	var checkInPhotoHash sql.NullInt64
 	findErr := database.DB.QueryRow(
-		`SELECT id, check_in_time FROM godplan.attendances 
+		`SELECT id, check_in_time, status, schedule_id, COALESCE(fraud_score, 0), COALESCE(fraud_flags, '{}'),
+			roster_id, TO_CHAR(attendance_date, 'YYYY-MM-DD'), COALESCE(timezone, ''), check_in_photo_hash
+		 FROM godplan.attendances 
 		 WHERE user_id = $1 AND tenant_id = $2 AND check_out_time IS NULL
 		 ORDER BY created_at DESC LIMIT 1`,
 		userID, tenantID,
-	).Scan(&attendanceID, &checkInTime)
+	).Scan(&attendanceID, &checkInTime, &currentStatus, &scheduleID, &checkInFraud.Score, pq.Array(&checkInFraud.Flags),
+		&rosterID, &attendanceDate, &timezone, &checkInPhotoHash)
 
 	if findErr != nil {
-		utils.GinErrorResponse(c, http.StatusBadRequest, "Tidak ada sesi Clock In yang aktif (atau sudah Clock Out)")
-		return
+		return nil, "", &clockError{http.StatusBadRequest, "Tidak ada sesi Clock In yang aktif (atau sudah Clock Out)"}
+	}
+	if !event.At.After(checkInTime) {
+		return nil, "", &clockError{http.StatusBadRequest, "Waktu Clock Out harus setelah Clock In"}
 	}
 
-	// Calculate total hours
-	now := time.Now()
-	totalHours := now.Sub(checkInTime).Hours()
+	policy := tenantAttendancePolicy(tenantID)
+	if policy.RequireSelfie && req.PhotoSelfie == "" {
+		return nil, "", &clockError{http.StatusBadRequest, selfieRequiredMessage}
+	}
+	fusion, err := applyGPSFusion(req.Samples, event.At.Add(event.ClockDrift), &req.Latitude, &req.Longitude, &req.Accuracy)
+	if err != nil {
+		return nil, "", &clockError{http.StatusBadRequest, err.Error()}
+	}
 
-	// Update status if force is used
-	status := "approved"
+	// Validate location with kiosk QR code, Wi-Fi/BLE proof or adaptive GPS threshold against the nearest active office,
+	// or against the home geofence when the session date is an approved WFH day
+	resolved, workMode, err := resolveWorkLocation(tenantID, userID, policy, attendanceDate, req.Latitude, req.Longitude, req.Accuracy, req.PresenceProof, req.KioskCode, event.At)
+	if err != nil {
+		return nil, "", workLocationError(err)
+	}
+	match, presenceMethod := resolved.OfficeMatch, resolved.PresenceMethod
+	inRange, distance := match.InRange, match.Distance
+
+	if !inRange && !req.Force {
+		return nil, "", &clockError{http.StatusBadRequest, outOfRangeMessage(match, workMode, req.Accuracy, policy.AllowForce)}
+	}
+	if !inRange && !policy.AllowForce {
+		return nil, "", &clockError{http.StatusBadRequest, forceNotAllowedMessage}
+	}
+
+	// Pulang cepat & lembur dihitung dalam timezone yang dipakai saat Clock In
+	// (attendance lama tanpa timezone memakai timezone tenant)
+	loc := utils.ResolveTimezone(timezone)
+	if timezone == "" {
+		loc = getTimezoneService().TenantLocation(tenantID)
+	}
+	checkInTime = checkInTime.In(loc)
+
+	// Durasi shift minimal mengikuti attendance policy tenant
+	if earliest, tooEarly := utils.EarliestClockOut(policy, checkInTime, event.At); tooEarly {
+		return nil, "", &clockError{http.StatusBadRequest, fmt.Sprintf("Durasi kerja minimal %d menit, Clock Out baru bisa dilakukan setelah %s",
+			policy.MinShiftMinutes, earliest.Format("15:04"))}
+	}
+
+	// Calculate total hours, istirahat tidak dihitung sebagai jam kerja
+	now := event.At
+	localNow := now.In(loc)
+	// Istirahat yang masih berjalan dihitung sampai jam pulang, tapi baru ditutup setelah clock out
+	// tersimpan agar clock out yang ditolak tidak mengakhiri istirahat karyawan
+	breakMinutes, err := getBreakService().SessionBreakMinutes(attendanceID, now)
+	if err != nil {
+		return nil, "", &clockError{http.StatusInternalServerError, "Failed to calculate break minutes"}
+	}
+	totalHours := utils.WorkedHours(checkInTime, now, breakMinutes)
+
+	// Pulang cepat & lembur dihitung dari jadwal yang dipakai saat Clock In.
+	// Seluruh jam kerja di hari libur tenant dihitung sebagai lembur.
+	stats := getScheduleService().SessionTimeStats(tenantID, rosterID, scheduleID, attendanceDate, loc, checkInTime, &localNow)
+
+	// Update status if force is used. Status clock in yang masih menunggu approval
+	// tidak boleh tertimpa menjadi approved oleh clock out yang normal.
+	status := currentStatus
 	if !inRange && req.Force {
-		status = "pending_forced"
+		status = models.AttendanceStatusPendingForced
+	}
+
+	// Skor fraud attendance menjumlahkan flag clock in dan clock out (flag yang sama dihitung sekali)
+	fraud := utils.MergeFraudAssessments(checkInFraud,
+		getAttendanceService().AssessClockEvent(tenantID, userID, req.locationCheck(fusion), now, attendanceDate))
+	if event.Offline {
+		fraud = utils.MergeFraudAssessments(fraud, utils.AssessClockDrift(event.ClockDrift))
+	}
+
+	// Selfie yang pernah diupload (oleh user sendiri atau karyawan lain) atau diambil jauh
+	// dari waktu event juga dinilai, sehingga attendance masuk review supervisor
+	selfie, err := decodeAttendanceSelfie(req.PhotoSelfie, loc)
+	if err != nil {
+		return nil, "", photoError(err)
+	}
+	var photoMatch *models.PhotoMatch
+	if selfie != nil {
+		var selfieFraud models.FraudAssessment
+		var checkInHash *int64
+		if checkInPhotoHash.Valid {
+			checkInHash = &checkInPhotoHash.Int64
+		}
+		selfieFraud, photoMatch = getAttendanceService().AssessSelfie(tenantID, userID, &attendanceID, checkInHash, selfie.Hash, selfie.TakenAt, now)
+		fraud = utils.MergeFraudAssessments(fraud, selfieFraud)
+	}
+	if fraud.Suspicious && status == models.AttendanceStatusApproved {
+		status = models.AttendanceStatusPending
+	}
+
+	photoKey, err := storeAttendancePhoto(ctx, tenantID, userID, "checkout", selfie, now)
+	if err != nil {
+		return nil, "", photoError(err)
 	}
 
 	// Update existing record with checkout data
@@ -350,36 +899,86 @@ func ClockOut(c *gin.Context) {
 			total_hours = $5,
 			type = 'CheckOut',
 			status = $6,
-			updated_at = $7
-		WHERE id = $8 AND tenant_id = $9`,
-		now, req.Latitude, req.Longitude, req.PhotoSelfie,
-		totalHours, status, now, attendanceID, tenantID,
+			check_out_office_location_id = $7,
+			early_leave_minutes = $8,
+			overtime_minutes = $9,
+			fraud_score = $10,
+			fraud_flags = $11,
+			is_suspicious = $12,
+			check_out_presence_method = $13,
+			check_out_kiosk_id = $14,
+			break_minutes = $15,
+			is_offline = is_offline OR $19,
+			check_out_accuracy = $20,
+			check_out_photo_hash = $21,
+			check_out_photo_taken_at = $22,
+			photo_reuse_attendance_id = COALESCE($23, photo_reuse_attendance_id),
+			updated_at = $16
+		WHERE id = $17 AND tenant_id = $18`,
+		now, req.Latitude, req.Longitude, sql.NullString{String: photoKey, Valid: photoKey != ""},
+		totalHours, status, match.OfficeID(),
+		stats.EarlyLeaveMinutes, stats.OvertimeMinutes,
+		fraud.Score, pq.Array(fraud.Flags), fraud.Suspicious,
+		presenceMethod, resolved.KioskID, breakMinutes, now, attendanceID, tenantID, event.Offline,
+		sql.NullFloat64{Float64: req.Accuracy, Valid: req.Accuracy > 0},
+		selfieHash(selfie), selfieTakenAt(selfie), photoMatchID(photoMatch),
 	)
 
 	if err != nil {
 		if config.IsDevelopment() {
 			fmt.Printf("🔴 ClockOut database error: %v\n", err)
 		}
-		utils.GinErrorResponse(c, http.StatusInternalServerError, "Failed to clock out: "+err.Error())
-		return
+		deleteAttendancePhoto(photoKey)
+		return nil, "", &clockError{http.StatusInternalServerError, "Failed to clock out: " + err.Error()}
+	}
+
+	// Clock out sudah tersimpan dengan break_minutes di atas, kegagalan menutup istirahat
+	// tidak membatalkannya
+	if _, err := getBreakService().CloseSessionBreaks(tenantID, attendanceID, now); err != nil && config.IsDevelopment() {
+		fmt.Printf("⚠️ Close running break error: %v\n", err)
+	}
+
+	// Lembur yang sudah disetujui direkonsiliasi dengan jam pulang sebenarnya.
+	// Clock out tetap berhasil walaupun rekonsiliasi gagal.
+	if err := getOvertimeService().ReconcileSession(tenantID, userID, attendanceID, checkInTime, now); err != nil && config.IsDevelopment() {
+		fmt.Printf("⚠️ Overtime reconciliation error: %v\n", err)
 	}
 
 	response := AttendanceResponse{
-		ID:              attendanceID,
-		UserID:          userID,
-		Type:            "CheckOut",
-		Status:          status,
-		Latitude:        req.Latitude,
-		Longitude:       req.Longitude,
-		PhotoSelfie:     req.PhotoSelfie,
-		InRange:         inRange,
-		ForceAttendance: req.Force,
-		CreatedAt:       now,
-		Distance:        distance,
-		MaxRadius:       cfg.AttendanceRadiusMeters,
+		ID:                attendanceID,
+		UserID:            userID,
+		Type:              "CheckOut",
+		Status:            status,
+		LocationName:      match.OfficeName(),
+		Latitude:          req.Latitude,
+		Longitude:         req.Longitude,
+		CheckOutPhotoURL:  attendancePhotoURL(photoKey),
+		InRange:           inRange,
+		ForceAttendance:   req.Force,
+		PresenceMethod:    presenceMethod,
+		CreatedAt:         localNow,
+		Distance:          distance,
+		MaxRadius:         match.BaseRadius(),
+		LateMinutes:       stats.LateMinutes,
+		EarlyLeaveMinutes: stats.EarlyLeaveMinutes,
+		OvertimeMinutes:   stats.OvertimeMinutes,
+		WorkMode:          workMode,
+		TotalHours:        totalHours,
+		BreakMinutes:      breakMinutes,
+		Offline:           event.Offline,
+		FusedPosition:     fusion,
+	}
+	if rosterID.Valid {
+		response.RosterID = &rosterID.UUID
+		response.ShiftDate = attendanceDate
 	}
 
-	utils.GinSuccessResponse(c, http.StatusOK, "Clock out successful", response)
+	message := "Clock out successful"
+	if fraud.Suspicious && status == models.AttendanceStatusPending {
+		message = "Clock out recorded and waiting for supervisor approval"
+	}
+
+	return &response, message, nil
 }
 
 // GetAttendance godoc
@@ -462,22 +1061,36 @@ func GetAttendance(c *gin.Context) {
 
 	if dateFilter != "" {
 		rows, err = database.DB.Query(
-			`SELECT id, user_id, type, status, attendance_date, 
-				COALESCE(TO_CHAR(check_in_time, 'HH24:MI'), TO_CHAR(created_at, 'HH24:MI')) as time,
-				check_in_lat as latitude, check_in_lng as longitude, check_in_photo as photo_selfie, in_range, force_attendance, created_at 
-			FROM godplan.attendances 
-			WHERE user_id = $1 AND tenant_id = $2 AND attendance_date = $3 
-			ORDER BY created_at DESC LIMIT $4`,
+			`SELECT a.id, a.user_id, a.type, a.status, a.attendance_date, 
+				COALESCE(a.check_in_time, a.created_at) as time, COALESCE(a.timezone, ol.timezone, ''),
+				a.check_in_lat as latitude, a.check_in_lng as longitude, a.check_in_photo, a.check_out_photo, a.in_range, a.force_attendance, a.created_at,
+				COALESCE(ol.name, a.location_name) as location_name,
+				a.approved_by, COALESCE(approver.full_name, approver.username), a.approved_at, a.rejection_reason,
+				COALESCE(a.late_minutes, 0), COALESCE(a.early_leave_minutes, 0), COALESCE(a.overtime_minutes, 0),
+				COALESCE(a.presence_method, ''), COALESCE(a.auto_closed, false), COALESCE(a.work_mode, 'office'),
+				COALESCE(a.is_offline, false)
+			FROM godplan.attendances a
+			LEFT JOIN godplan.office_locations ol ON ol.id = a.office_location_id AND ol.tenant_id = a.tenant_id
+			LEFT JOIN godplan.users approver ON approver.id = a.approved_by
+			WHERE a.user_id = $1 AND a.tenant_id = $2 AND a.attendance_date = $3 
+			ORDER BY a.created_at DESC LIMIT $4`,
 			userID, tenantID, dateFilter, limit,
 		)
 	} else {
 		rows, err = database.DB.Query(
-			`SELECT id, user_id, type, status, attendance_date, 
-				COALESCE(TO_CHAR(check_in_time, 'HH24:MI'), TO_CHAR(created_at, 'HH24:MI')) as time,
-				check_in_lat as latitude, check_in_lng as longitude, check_in_photo as photo_selfie, in_range, force_attendance, created_at 
-			FROM godplan.attendances 
-			WHERE user_id = $1 AND tenant_id = $2 
-			ORDER BY created_at DESC LIMIT $3`,
+			`SELECT a.id, a.user_id, a.type, a.status, a.attendance_date, 
+				COALESCE(a.check_in_time, a.created_at) as time, COALESCE(a.timezone, ol.timezone, ''),
+				a.check_in_lat as latitude, a.check_in_lng as longitude, a.check_in_photo, a.check_out_photo, a.in_range, a.force_attendance, a.created_at,
+				COALESCE(ol.name, a.location_name) as location_name,
+				a.approved_by, COALESCE(approver.full_name, approver.username), a.approved_at, a.rejection_reason,
+				COALESCE(a.late_minutes, 0), COALESCE(a.early_leave_minutes, 0), COALESCE(a.overtime_minutes, 0),
+				COALESCE(a.presence_method, ''), COALESCE(a.auto_closed, false), COALESCE(a.work_mode, 'office'),
+				COALESCE(a.is_offline, false)
+			FROM godplan.attendances a
+			LEFT JOIN godplan.office_locations ol ON ol.id = a.office_location_id AND ol.tenant_id = a.tenant_id
+			LEFT JOIN godplan.users approver ON approver.id = a.approved_by
+			WHERE a.user_id = $1 AND a.tenant_id = $2 
+			ORDER BY a.created_at DESC LIMIT $3`,
 			userID, tenantID, limit,
 		)
 	}
@@ -491,17 +1104,31 @@ func GetAttendance(c *gin.Context) {
 	}
 	defer rows.Close()
 
+	// Jam ditampilkan dalam timezone attendance (atau kantornya), attendance lama memakai timezone tenant
+	tenantLoc := getTimezoneService().TenantLocation(tenantID)
+
 	var attendances []AttendanceResponse
 	for rows.Next() {
 		var att models.Attendance
 		var attendanceDate string
-		var attendanceTime string
-		
+		var attendanceTime time.Time
+		var timezone string
+		var locationName sql.NullString
+		var approvedByName, rejectionReason sql.NullString
+		var approvedAt sql.NullTime
+		var checkInPhoto, checkOutPhoto sql.NullString
+		var autoClosed, offline bool
+		var workMode string
+
 		err := rows.Scan(
 			&att.ID, &att.UserID, &att.Type, &att.Status,
-			&attendanceDate, &attendanceTime,
-			&att.Latitude, &att.Longitude, &att.PhotoSelfie,
+			&attendanceDate, &attendanceTime, &timezone,
+			&att.Latitude, &att.Longitude, &checkInPhoto, &checkOutPhoto,
 			&att.InRange, &att.ForceAttendance, &att.CreatedAt,
+			&locationName,
+			&att.ApprovedBy, &approvedByName, &approvedAt, &rejectionReason,
+			&att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+			&att.PresenceMethod, &autoClosed, &workMode, &offline,
 		)
 		if err != nil {
 			if config.IsDevelopment() {
@@ -510,20 +1137,50 @@ func GetAttendance(c *gin.Context) {
 			continue
 		}
 
+		loc := tenantLoc
+		if timezone != "" {
+			loc = utils.ResolveTimezone(timezone)
+		}
+
+		// Attendance WFH memakai nama lokasi rumah saat Clock In, attendance lain tanpa
+		// office_location_id tercatat di kantor default (env var)
+		switch {
+		case locationName.Valid:
+			att.LocationName = locationName.String
+		case workMode == models.WorkModeWFH:
+			att.LocationName = models.WFHLocationName
+		default:
+			att.LocationName = utils.DefaultOfficeName
+		}
+
 		attendance := AttendanceResponse{
-			ID:              att.ID,
-			UserID:          att.UserID,
-			Type:            att.Type,
-			Status:          att.Status,
-			Date:            attendanceDate,
-			Time:            attendanceTime,
-			LocationName:    "Kantor Pusat Godplan", // Default location name
-			Latitude:        att.Latitude,
-			Longitude:       att.Longitude,
-			PhotoSelfie:     att.PhotoSelfie,
-			InRange:         att.InRange,
-			ForceAttendance: att.ForceAttendance,
-			CreatedAt:       att.CreatedAt,
+			ID:                att.ID,
+			UserID:            att.UserID,
+			Type:              att.Type,
+			Status:            att.Status,
+			Date:              attendanceDate,
+			Time:              attendanceTime.In(loc).Format("15:04"),
+			LocationName:      att.LocationName,
+			Latitude:          att.Latitude,
+			Longitude:         att.Longitude,
+			PhotoURL:          attendancePhotoURL(checkInPhoto.String),
+			CheckOutPhotoURL:  attendancePhotoURL(checkOutPhoto.String),
+			InRange:           att.InRange,
+			ForceAttendance:   att.ForceAttendance,
+			PresenceMethod:    att.PresenceMethod,
+			AutoClosed:        autoClosed,
+			Offline:           offline,
+			WorkMode:          workMode,
+			CreatedAt:         att.CreatedAt,
+			ApprovedBy:        att.ApprovedBy,
+			ApprovedByName:    approvedByName.String,
+			RejectionReason:   rejectionReason.String,
+			LateMinutes:       att.LateMinutes,
+			EarlyLeaveMinutes: att.EarlyLeaveMinutes,
+			OvertimeMinutes:   att.OvertimeMinutes,
+		}
+		if approvedAt.Valid {
+			attendance.ApprovedAt = &approvedAt.Time
 		}
 		attendances = append(attendances, attendance)
 	}
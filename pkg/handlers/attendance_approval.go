@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	attendanceRepo    repository.AttendanceRepository
+	attendanceService service.AttendanceService
+	attendanceOnce    sync.Once
+)
+
+// getAttendanceService returns lazily initialized attendance service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getAttendanceService() service.AttendanceService {
+	attendanceOnce.Do(func() {
+		attendanceRepo = repository.NewAttendanceRepository(database.GetDB())
+		attendanceService = service.NewAttendanceService(attendanceRepo, repository.NewCalendarRepository(database.GetDB()))
+	})
+	return attendanceService
+}
+
+// GetPendingApprovals godoc
+// @Summary Get pending attendance approvals
+// @Description Get forced/pending attendances waiting for a decision. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Failure 401 {object} utils.GinResponse
+// @Failure 500 {object} utils.GinResponse
+// @Router /attendance/approvals [get]
+func GetPendingApprovals(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	attendances, err := getAttendanceService().GetPendingApprovals(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending approvals")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending approvals retrieved successfully", attendances)
+}
+
+// ApproveAttendance godoc
+// @Summary Approve attendance
+// @Description Approve a pending or forced attendance of a direct report
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attendance ID"
+// @Param request body models.AttendanceDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/{id}/approve [post]
+func ApproveAttendance(c *gin.Context) {
+	decideAttendance(c, true)
+}
+
+// RejectAttendance godoc
+// @Summary Reject attendance
+// @Description Reject a pending or forced attendance of a direct report. A reason is required.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attendance ID"
+// @Param request body models.AttendanceDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/{id}/reject [post]
+func RejectAttendance(c *gin.Context) {
+	decideAttendance(c, false)
+}
+
+func decideAttendance(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid attendance ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.AttendanceDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getAttendanceService().DecideAttendance(tenantID, approver, attendanceID, approve, req.Reason)
+	if err != nil {
+		utils.GinErrorResponse(c, attendanceDecisionErrorCode(err), attendanceDecisionErrorMessage(err))
+		return
+	}
+
+	result := models.AttendanceDecisionResult{AttendanceID: attendanceID, Success: true, Status: status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "Attendance approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "Attendance rejected successfully", result)
+}
+
+// BulkDecideAttendances godoc
+// @Summary Bulk approve or reject attendances
+// @Description Apply one decision to up to 100 attendances. Each item is processed independently and reported in the result list.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkAttendanceDecisionRequest true "Bulk decision"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /attendance/approvals/bulk [post]
+func BulkDecideAttendances(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkAttendanceDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	if req.Action == "reject" && req.Reason == "" {
+		utils.GinErrorResponse(c, 400, service.ErrRejectionReasonRequired.Error())
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	results := getAttendanceService().BulkDecideAttendances(tenantID, approver, req)
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Success {
+			succeeded++
+		}
+	}
+
+	utils.GinSuccessResponse(c, 200, "Bulk decision processed", gin.H{
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	})
+}
+
+func attendanceDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfApproval:
+		return 403
+	case repository.ErrAttendanceNotFound:
+		return 404
+	case repository.ErrAttendanceNotPending:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func attendanceDecisionErrorMessage(err error) string {
+	if attendanceDecisionErrorCode(err) == 500 {
+		return "Failed to process attendance decision"
+	}
+	return err.Error()
+}
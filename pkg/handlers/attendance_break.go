@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	breakRepo    repository.BreakRepository
+	breakService service.BreakService
+	breakOnce    sync.Once
+)
+
+// getBreakService returns lazily initialized attendance break service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getBreakService() service.BreakService {
+	breakOnce.Do(func() {
+		breakRepo = repository.NewBreakRepository(database.GetDB())
+		breakService = service.NewBreakService(breakRepo, repository.NewTenantSettingsRepository(database.GetDB()))
+	})
+	return breakService
+}
+
+// breakErrorCode maps break service errors to HTTP status codes
+func breakErrorCode(err error) int {
+	switch err {
+	case repository.ErrNoOpenAttendance, repository.ErrNoActiveBreak:
+		return 400
+	case repository.ErrBreakAlreadyStarted, service.ErrBreakLimitReached, service.ErrBreakAllowanceUsed:
+		return 409
+	}
+	return 500
+}
+
+// GetCurrentBreaks godoc
+// @Summary Get breaks of the current session
+// @Description Get the breaks of the open attendance session and the remaining break allowance
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /attendance/breaks [get]
+func GetCurrentBreaks(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	summary, err := getBreakService().GetCurrentBreaks(tenantID, userID, time.Now())
+	if err != nil {
+		if code := breakErrorCode(err); code != 500 {
+			utils.GinErrorResponse(c, code, err.Error())
+			return
+		}
+		utils.GinErrorResponse(c, 500, "Failed to fetch breaks")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Breaks retrieved successfully", summary)
+}
+
+// StartBreak godoc
+// @Summary Start break
+// @Description Start a lunch, prayer or other break inside the open attendance session.
+// @Description Break time is subtracted from total_hours at clock out.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.StartBreakRequest false "Break type (default: other)"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/breaks/start [post]
+func StartBreak(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.StartBreakRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	attendanceBreak, err := getBreakService().StartBreak(tenantID, userID, req.Type, time.Now())
+	if err != nil {
+		if code := breakErrorCode(err); code != 500 {
+			utils.GinErrorResponse(c, code, err.Error())
+			return
+		}
+		utils.GinErrorResponse(c, 500, "Failed to start break")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Break started", attendanceBreak)
+}
+
+// EndBreak godoc
+// @Summary End break
+// @Description End the running break of the open attendance session. Minutes over the tenant
+// @Description break limits are returned as exceeded_minutes.
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /attendance/breaks/end [post]
+func EndBreak(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	attendanceBreak, err := getBreakService().EndBreak(tenantID, userID, time.Now())
+	if err != nil {
+		if code := breakErrorCode(err); code != 500 {
+			utils.GinErrorResponse(c, code, err.Error())
+			return
+		}
+		utils.GinErrorResponse(c, 500, "Failed to end break")
+		return
+	}
+
+	message := "Break ended"
+	if attendanceBreak.ExceededMinutes > 0 {
+		message = "Break ended, exceeding the break limit"
+	}
+	utils.GinSuccessResponse(c, 200, message, attendanceBreak)
+}
+
+// GetBreakPolicy godoc
+// @Summary Get break policy
+// @Description Get the tenant limits on break length and number of breaks (admin/HR only)
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/breaks [get]
+func GetBreakPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	policy, err := getBreakService().GetPolicy(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch break policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Break policy retrieved successfully", policy)
+}
+
+// UpdateBreakPolicy godoc
+// @Summary Update break policy
+// @Description Set the maximum minutes per break, total break minutes and number of breaks per
+// @Description attendance session. 0 means no limit (admin/HR only).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BreakPolicy true "Break policy"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/breaks [put]
+func UpdateBreakPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.BreakPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err := getBreakService().UpdatePolicy(tenantID, req)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update break policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Break policy updated successfully", req)
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	attendanceCorrectionService service.AttendanceCorrectionService
+	attendanceCorrectionOnce    sync.Once
+)
+
+// getAttendanceCorrectionService returns lazily initialized attendance correction service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getAttendanceCorrectionService() service.AttendanceCorrectionService {
+	attendanceCorrectionOnce.Do(func() {
+		db := database.GetDB()
+		attendanceCorrectionService = service.NewAttendanceCorrectionService(
+			repository.NewAttendanceCorrectionRepository(db),
+			repository.NewBreakRepository(db),
+			repository.NewTenantSettingsRepository(db),
+			repository.NewNotificationRepository(db),
+			repository.NewScheduleRepository(db),
+			repository.NewRosterRepository(db),
+			repository.NewCalendarRepository(db),
+			repository.NewOvertimeRepository(db),
+		)
+	})
+	return attendanceCorrectionService
+}
+
+// GetAttendanceCorrections godoc
+// @Summary Get my attendance corrections
+// @Description Get the attendance correction requests of the logged-in employee
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/corrections [get]
+func GetAttendanceCorrections(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	requests, err := getAttendanceCorrectionService().GetCorrectionRequests(tenantID, employeeID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch attendance corrections")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Attendance corrections retrieved successfully", requests)
+}
+
+// CreateAttendanceCorrection godoc
+// @Summary Request attendance correction
+// @Description Propose new clock in and/or clock out times for one of your attendances, e.g. after a
+// @Description forgotten clock out or a failed GPS fix. On approval the attendance is updated, total_hours
+// @Description is recomputed and the original values are kept in the audit trail.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attendance ID"
+// @Param request body models.CreateAttendanceCorrectionRequest true "Proposed times"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/{id}/corrections [post]
+func CreateAttendanceCorrection(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid attendance ID")
+		return
+	}
+
+	var req models.CreateAttendanceCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	request := &models.AttendanceCorrectionRequest{
+		TenantID:             tenantID,
+		AttendanceID:         attendanceID,
+		EmployeeID:           employeeID,
+		UserID:               userID,
+		ProposedCheckInTime:  req.CheckInTime,
+		ProposedCheckOutTime: req.CheckOutTime,
+		Reason:               req.Reason,
+	}
+	if err := getAttendanceCorrectionService().SubmitCorrection(request); err != nil {
+		code := correctionSubmitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to submit attendance correction"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Attendance correction submitted successfully", request)
+}
+
+// CancelAttendanceCorrection godoc
+// @Summary Cancel attendance correction
+// @Description Withdraw one of your own pending attendance corrections
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Correction Request ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/corrections/{id}/cancel [post]
+func CancelAttendanceCorrection(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid correction request ID")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	err = getAttendanceCorrectionService().CancelCorrectionRequest(tenantID, employeeID, requestID)
+	if err == repository.ErrCorrectionNotFound {
+		utils.GinErrorResponse(c, 404, "Attendance correction not found")
+		return
+	}
+	if err == repository.ErrCorrectionNotPending {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to cancel attendance correction")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Attendance correction cancelled successfully", nil)
+}
+
+// GetPendingAttendanceCorrections godoc
+// @Summary Get pending attendance corrections
+// @Description Get attendance corrections waiting for a decision. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/corrections/approvals [get]
+func GetPendingAttendanceCorrections(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	requests, err := getAttendanceCorrectionService().GetPendingCorrectionRequests(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending attendance corrections")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending attendance corrections retrieved successfully", requests)
+}
+
+// ApproveAttendanceCorrection godoc
+// @Summary Approve attendance correction
+// @Description Approve a pending correction of a direct report. The attendance gets the proposed times,
+// @Description total_hours is recomputed and the original values are kept in the audit trail.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Correction Request ID"
+// @Param request body models.AttendanceCorrectionDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/corrections/{id}/approve [post]
+func ApproveAttendanceCorrection(c *gin.Context) {
+	decideAttendanceCorrection(c, true)
+}
+
+// RejectAttendanceCorrection godoc
+// @Summary Reject attendance correction
+// @Description Reject a pending correction of a direct report. A reason is required.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Correction Request ID"
+// @Param request body models.AttendanceCorrectionDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /attendance/corrections/{id}/reject [post]
+func RejectAttendanceCorrection(c *gin.Context) {
+	decideAttendanceCorrection(c, false)
+}
+
+func decideAttendanceCorrection(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid correction request ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.AttendanceCorrectionDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getAttendanceCorrectionService().DecideCorrection(tenantID, approver, requestID, approve, req.Reason)
+	if err != nil {
+		code := correctionDecisionErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to process attendance correction decision"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	result := gin.H{"correction_request_id": requestID, "status": status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "Attendance correction approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "Attendance correction rejected successfully", result)
+}
+
+// GetAttendanceAuditLog godoc
+// @Summary Get attendance audit trail
+// @Description Get the original and corrected values of an attendance changed after the fact (admin/HR only)
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attendance ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /attendance/{id}/audit-log [get]
+func GetAttendanceAuditLog(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid attendance ID")
+		return
+	}
+
+	logs, err := getAttendanceCorrectionService().GetAuditLogs(tenantID, attendanceID)
+	if err == repository.ErrAttendanceNotFound {
+		utils.GinErrorResponse(c, 404, "Attendance not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch attendance audit trail")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Attendance audit trail retrieved successfully", logs)
+}
+
+func correctionSubmitErrorCode(err error) int {
+	switch err {
+	case service.ErrCorrectionTimesRequired, service.ErrCorrectionUnchanged, utils.ErrInvalidSessionTimes:
+		return 400
+	case repository.ErrAttendanceNotFound:
+		return 404
+	case repository.ErrCorrectionExists:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func correctionDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfCorrectionApproval:
+		return 403
+	case repository.ErrCorrectionNotFound, repository.ErrAttendanceNotFound:
+		return 404
+	case repository.ErrCorrectionNotPending, repository.ErrAttendanceChanged, utils.ErrInvalidSessionTimes:
+		return 409
+	default:
+		return 500
+	}
+}
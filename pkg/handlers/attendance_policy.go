@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	attendancePolicyService service.AttendancePolicyService
+	attendancePolicyOnce    sync.Once
+)
+
+// getAttendancePolicyService returns lazily initialized attendance policy service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getAttendancePolicyService() service.AttendancePolicyService {
+	attendancePolicyOnce.Do(func() {
+		attendancePolicyService = service.NewAttendancePolicyService(repository.NewTenantSettingsRepository(database.GetDB()))
+	})
+	return attendancePolicyService
+}
+
+// tenantAttendancePolicy mengembalikan attendance policy tenant. Jika setting gagal dibaca,
+// clock in/out tetap berjalan dengan policy default.
+func tenantAttendancePolicy(tenantID uuid.UUID) models.AttendancePolicy {
+	policy, err := getAttendancePolicyService().GetPolicy(tenantID)
+	if err != nil && config.IsDevelopment() {
+		fmt.Printf("⚠️ Failed to load attendance policy, using defaults: %v\n", err)
+	}
+	return policy
+}
+
+// GetAttendancePolicy godoc
+// @Summary Get attendance policy
+// @Description Get the tenant clock in/out rules: location check, selfie, force, auto-approve margin, GPS accuracy multipliers and minimum shift length (admin/HR only)
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/policy [get]
+func GetAttendancePolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	policy, err := getAttendancePolicyService().GetPolicy(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch attendance policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Attendance policy retrieved successfully", policy)
+}
+
+// UpdateAttendancePolicy godoc
+// @Summary Update attendance policy
+// @Description Set the tenant clock in/out rules. The policy replaces ENABLE_LOCATION_CHECK and the
+// @Description hardcoded GPS tolerance for this tenant (admin/HR only). Fields missing from the body keep their current value.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AttendancePolicy true "Attendance policy"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/policy [put]
+func UpdateAttendancePolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	// Request di-bind ke policy saat ini, field yang tidak dikirim (misalnya location_check_enabled)
+	// tetap seperti sebelumnya dan tidak menjadi false
+	req, err := getAttendancePolicyService().GetPolicy(tenantID)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch attendance policy")
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err = getAttendancePolicyService().UpdatePolicy(tenantID, req)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update attendance policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Attendance policy updated successfully", req)
+}
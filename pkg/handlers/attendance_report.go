@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var attendanceRecapHeaders = []string{
+	"Employee ID", "Employee Name", "Department", "Working Days", "Days Present", "Days Absent",
+	"Late Count", "Total Late Minutes", "Total Hours", "Total Overtime Minutes", "Forced Count", "Pending Count",
+}
+
+// GetAttendanceRecap godoc
+// @Summary Attendance recap report
+// @Description Aggregate attendance per employee for a period (admin/HR only). Defaults to the current month.
+// @Description Use format=csv or format=xlsx to download the report.
+// @Tags attendance
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param department_id query string false "Filter by department ID"
+// @Param format query string false "json (default), csv or xlsx"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /attendance/reports/recap [get]
+func GetAttendanceRecap(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	// Bulan berjalan mengikuti timezone tenant
+	now := time.Now().In(getTimezoneService().TenantLocation(tenantID))
+	filter := models.AttendanceRecapFilter{
+		StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+	}
+	filter.EndDate = filter.StartDate.AddDate(0, 1, -1)
+
+	if v := c.Query("start_date"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid start_date, use YYYY-MM-DD")
+			return
+		}
+		filter.StartDate = date
+	}
+	if v := c.Query("end_date"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid end_date, use YYYY-MM-DD")
+			return
+		}
+		filter.EndDate = date
+	}
+	if v := c.Query("department_id"); v != "" {
+		departmentID, err := uuid.Parse(v)
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid department ID")
+			return
+		}
+		filter.DepartmentID = &departmentID
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "xlsx" {
+		utils.GinErrorResponse(c, 400, "Invalid format, use json, csv or xlsx")
+		return
+	}
+
+	recaps, err := getAttendanceService().GetAttendanceRecap(tenantID, filter)
+	if err == service.ErrInvalidReportPeriod {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to generate attendance recap")
+		return
+	}
+
+	filename := fmt.Sprintf("attendance-recap_%s_%s", filter.StartDate.Format("2006-01-02"), filter.EndDate.Format("2006-01-02"))
+
+	switch format {
+	case "csv":
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write(attendanceRecapHeaders)
+		for _, r := range recaps {
+			row := attendanceRecapRow(r)
+			record := make([]string, len(row))
+			for i, v := range row {
+				switch val := v.(type) {
+				case float64:
+					record[i] = strconv.FormatFloat(val, 'f', 2, 64)
+				default:
+					record[i] = fmt.Sprint(val)
+				}
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			utils.GinErrorResponse(c, 500, "Failed to generate CSV")
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+		c.Data(200, "text/csv; charset=utf-8", buf.Bytes())
+
+	case "xlsx":
+		rows := make([][]interface{}, 0, len(recaps))
+		for _, r := range recaps {
+			rows = append(rows, attendanceRecapRow(r))
+		}
+
+		var buf bytes.Buffer
+		if err := utils.WriteXLSX(&buf, "Attendance Recap", attendanceRecapHeaders, rows); err != nil {
+			utils.GinErrorResponse(c, 500, "Failed to generate XLSX")
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+		c.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+
+	default:
+		utils.GinSuccessResponse(c, 200, "Attendance recap retrieved successfully", gin.H{
+			"start_date": filter.StartDate.Format("2006-01-02"),
+			"end_date":   filter.EndDate.Format("2006-01-02"),
+			"employees":  recaps,
+		})
+	}
+}
+
+func attendanceRecapRow(r models.AttendanceRecap) []interface{} {
+	return []interface{}{
+		r.EmployeeCode,
+		r.EmployeeName,
+		r.Department,
+		r.WorkingDays,
+		r.DaysPresent,
+		r.DaysAbsent,
+		r.LateCount,
+		r.TotalLateMinutes,
+		r.TotalHours,
+		r.TotalOvertimeMinutes,
+		r.ForcedCount,
+		r.PendingCount,
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	attendanceSyncService service.AttendanceSyncService
+	attendanceSyncOnce    sync.Once
+)
+
+// getAttendanceSyncService returns lazily initialized attendance sync service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getAttendanceSyncService() service.AttendanceSyncService {
+	attendanceSyncOnce.Do(func() {
+		attendanceSyncService = service.NewAttendanceSyncService(repository.NewAttendanceSyncRepository(database.GetDB()))
+	})
+	return attendanceSyncService
+}
+
+// OfflineClockEvent adalah clock in/out yang direkam device saat offline.
+// kiosk_code tidak diterima karena QR code diverifikasi terhadap waktu event dari device.
+type OfflineClockEvent struct {
+	// ClientEventID adalah idempotency key yang dibuat device, event yang sama tidak diproses dua kali
+	ClientEventID string    `json:"client_event_id" binding:"required,max=100" example:"6f1c2a9e-3b5d-4e2f-9a7b-1c2d3e4f5a6b"`
+	Type          string    `json:"type" binding:"required,oneof=clock_in clock_out" example:"clock_in"`
+	RecordedAt    time.Time `json:"recorded_at" binding:"required" example:"2026-01-05T08:01:00+07:00"` // device clock
+	ClockInRequest
+}
+
+// AttendanceSyncRequest adalah batch event offline beserta jam device saat sync
+type AttendanceSyncRequest struct {
+	// DeviceTime adalah jam device saat request dikirim, dipakai untuk mengukur selisih jam device vs server
+	DeviceTime time.Time           `json:"device_time" binding:"required" example:"2026-01-05T12:30:00+07:00"`
+	Events     []OfflineClockEvent `json:"events" binding:"required,min=1,max=50,dive"`
+}
+
+// OfflineClockEventResult adalah hasil sync satu event
+type OfflineClockEventResult struct {
+	ClientEventID string              `json:"client_event_id"`
+	Type          string              `json:"type"`
+	Result        string              `json:"result"` // accepted, rejected, duplicate, failed
+	Message       string              `json:"message,omitempty"`
+	AttendanceID  *uuid.UUID          `json:"attendance_id,omitempty"`
+	RecordedAt    *time.Time          `json:"recorded_at,omitempty"` // event time in server clock
+	Attendance    *AttendanceResponse `json:"attendance,omitempty"`
+}
+
+// AttendanceSyncResponse adalah ringkasan sync batch offline
+type AttendanceSyncResponse struct {
+	ClockDriftSeconds int                       `json:"clock_drift_seconds"`
+	ClockDriftFlagged bool                      `json:"clock_drift_flagged"`
+	Accepted          int                       `json:"accepted"`
+	Rejected          int                       `json:"rejected"`
+	Duplicates        int                       `json:"duplicates"`
+	Failed            int                       `json:"failed"` // server errors, safe to retry
+	Results           []OfflineClockEventResult `json:"results"`
+}
+
+// syncResultFailed tidak disimpan: klaim event dilepas supaya device bisa mengirim ulang
+const syncResultFailed = "failed"
+
+// offlineKioskCodeMessage dipakai untuk event offline yang mengirim kiosk_code
+const offlineKioskCodeMessage = "QR code kiosk tidak bisa dipakai untuk Clock In/Out offline, gunakan GPS atau scan ulang saat online"
+
+// SyncOfflineAttendance godoc
+// @Summary Sync offline clock events
+// @Description Sync a batch of clock in/out events captured while the device was offline. Each event is validated against the geofence at its original time (kiosk QR codes are rejected), events already synced (same client_event_id) are reported as duplicate, and events from a device whose clock drifts too far from the server are flagged for review.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AttendanceSyncRequest true "Offline clock events"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/sync [post]
+func SyncOfflineAttendance(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req AttendanceSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	serverNow := time.Now()
+	drift := utils.ClockDrift(req.DeviceTime, serverNow)
+
+	// Proses sesuai urutan kejadian di device, clock out harus setelah clock in-nya
+	events := req.Events
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].RecordedAt.Before(events[j].RecordedAt)
+	})
+
+	response := AttendanceSyncResponse{
+		ClockDriftSeconds: int(drift.Seconds()),
+		ClockDriftFlagged: utils.ClockDriftExceeded(drift),
+		Results:           make([]OfflineClockEventResult, 0, len(events)),
+	}
+	for _, ev := range events {
+		result := syncOfflineClockEvent(c, tenantID, userID, ev, drift, serverNow)
+		switch result.Result {
+		case models.SyncResultAccepted:
+			response.Accepted++
+		case models.SyncResultRejected:
+			response.Rejected++
+		case models.SyncResultDuplicate:
+			response.Duplicates++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	utils.GinSuccessResponse(c, http.StatusOK, "Offline attendance synced", response)
+}
+
+// syncOfflineClockEvent mencatat satu event offline pada waktu aslinya (jam device dikoreksi drift)
+func syncOfflineClockEvent(c *gin.Context, tenantID, userID uuid.UUID, ev OfflineClockEvent, drift time.Duration, serverNow time.Time) OfflineClockEventResult {
+	result := OfflineClockEventResult{ClientEventID: ev.ClientEventID, Type: ev.Type}
+
+	event := &models.AttendanceSyncEvent{
+		TenantID:          tenantID,
+		UserID:            userID,
+		ClientEventID:     ev.ClientEventID,
+		EventType:         ev.Type,
+		DeviceRecordedAt:  ev.RecordedAt,
+		ClockDriftSeconds: int(drift.Seconds()),
+	}
+	existing, err := getAttendanceSyncService().ClaimEvent(event)
+	if err != nil {
+		result.Result = syncResultFailed
+		result.Message = "Failed to sync clock event"
+		return result
+	}
+	if existing != nil {
+		result.Result = models.SyncResultDuplicate
+		result.Message = existing.Message
+		result.AttendanceID = existing.AttendanceID
+		result.RecordedAt = existing.RecordedAt
+		if existing.Result == models.SyncResultProcessing {
+			result.Message = "Event sedang diproses"
+		}
+		return result
+	}
+
+	at, err := utils.OfflineEventTime(ev.RecordedAt, drift, serverNow)
+	if err != nil {
+		result.Result = models.SyncResultRejected
+		result.Message = err.Error()
+		getAttendanceSyncService().CompleteEvent(event, nil, result.Result, result.Message, nil)
+		return result
+	}
+
+	// Waktu event offline ditentukan jam device, sehingga QR code kiosk yang sudah kedaluwarsa
+	// (misalnya foto QR) bisa diputar ulang. QR code hanya diterima pada Clock In/Out online.
+	if ev.KioskCode != "" {
+		result.Result = models.SyncResultRejected
+		result.Message = offlineKioskCodeMessage
+		getAttendanceSyncService().CompleteEvent(event, nil, result.Result, result.Message, nil)
+		return result
+	}
+
+	clock := clockEvent{At: at, Offline: true, ClockDrift: drift}
+	var attendance *AttendanceResponse
+	var message string
+	var clockErr *clockError
+	if ev.Type == models.SyncEventClockIn {
+		attendance, message, clockErr = recordClockIn(c.Request.Context(), tenantID, userID, ev.ClockInRequest, clock)
+	} else {
+		attendance, message, clockErr = recordClockOut(c.Request.Context(), tenantID, userID, ClockOutRequest(ev.ClockInRequest), clock)
+	}
+
+	if clockErr != nil && clockErr.Status >= http.StatusInternalServerError {
+		getAttendanceSyncService().ReleaseEvent(event)
+		result.Result = syncResultFailed
+		result.Message = clockErr.Message
+		return result
+	}
+
+	result.RecordedAt = &at
+	if clockErr != nil {
+		result.Result = models.SyncResultRejected
+		result.Message = clockErr.Message
+	} else {
+		result.Result = models.SyncResultAccepted
+		result.Message = message
+		result.AttendanceID = &attendance.ID
+		result.Attendance = attendance
+	}
+	if err := getAttendanceSyncService().CompleteEvent(event, result.RecordedAt, result.Result, result.Message, result.AttendanceID); err != nil {
+		if config.IsDevelopment() {
+			fmt.Printf("⚠️ Failed to store offline clock event result %s: %v\n", ev.ClientEventID, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	tenantSettingsRepo  repository.TenantSettingsRepository
+	autoClockOutService service.AutoClockOutService
+	autoClockOutOnce    sync.Once
+
+	cronSecret     string
+	cronSecretOnce sync.Once
+)
+
+// getAutoClockOutService returns lazily initialized auto clock-out service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getAutoClockOutService() service.AutoClockOutService {
+	autoClockOutOnce.Do(func() {
+		db := database.GetDB()
+		tenantSettingsRepo = repository.NewTenantSettingsRepository(db)
+		autoClockOutService = service.NewAutoClockOutService(repository.NewAttendanceRepository(db),
+			tenantSettingsRepo, repository.NewNotificationRepository(db), repository.NewBreakRepository(db),
+			repository.NewOvertimeRepository(db))
+	})
+	return autoClockOutService
+}
+
+// StartAutoClockOutScheduler runs the auto clock-out job in the background every interval.
+// Only for the long-running server; serverless deployments call RunAutoClockOutJob from a cron instead.
+func StartAutoClockOutScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			closed, err := getAutoClockOutService().CloseForgottenSessions(time.Now())
+			if err != nil {
+				log.Printf("❌ Auto clock-out job failed: %v", err)
+				continue
+			}
+			if closed > 0 {
+				log.Printf("⏰ Auto clock-out closed %d forgotten session(s)", closed)
+			}
+		}
+	}()
+}
+
+// RunAutoClockOutJob godoc
+// @Summary Run auto clock-out job
+// @Description Close attendance sessions whose auto clock-out cut-off has passed. Called by Vercel Cron or
+// @Description another scheduler with "Authorization: Bearer <CRON_SECRET>"; disabled when CRON_SECRET is not set.
+// @Tags jobs
+// @Produce json
+// @Param Authorization header string true "Bearer <CRON_SECRET>"
+// @Success 200 {object} utils.GinResponse
+// @Failure 401 {object} utils.GinResponse
+// @Router /jobs/auto-clock-out [get]
+func RunAutoClockOutJob(c *gin.Context) {
+	if !authorizeCronRequest(c) {
+		return
+	}
+
+	closed, err := getAutoClockOutService().CloseForgottenSessions(time.Now())
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to run auto clock-out")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Auto clock-out completed", gin.H{"closed": closed})
+}
+
+// authorizeCronRequest checks the cron secret bearer token and writes 401 when it is missing or wrong
+func authorizeCronRequest(c *gin.Context) bool {
+	cronSecretOnce.Do(func() {
+		cronSecret = config.Load().CronSecret
+	})
+
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if cronSecret == "" || !found || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(cronSecret)) != 1 {
+		utils.GinErrorResponse(c, 401, "Invalid cron secret")
+		return false
+	}
+	return true
+}
+
+// GetAutoClockOutPolicy godoc
+// @Summary Get auto clock-out policy
+// @Description Get when forgotten attendance sessions of the tenant are closed automatically (admin/HR only)
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/auto-clock-out [get]
+func GetAutoClockOutPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	policy, err := getAutoClockOutService().GetPolicy(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch auto clock-out policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Auto clock-out policy retrieved successfully", policy)
+}
+
+// UpdateAutoClockOutPolicy godoc
+// @Summary Update auto clock-out policy
+// @Description Set the grace hours after the scheduled end (or after default_session_hours without schedule)
+// @Description before an open session is closed automatically (admin/HR only)
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AutoClockOutPolicy true "Auto clock-out policy"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/auto-clock-out [put]
+func UpdateAutoClockOutPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.AutoClockOutPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err := getAutoClockOutService().UpdatePolicy(tenantID, req)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update auto clock-out policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Auto clock-out policy updated successfully", req)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	calendarRepo    repository.CalendarRepository
+	calendarService service.CalendarService
+	calendarOnce    sync.Once
+)
+
+// getCalendarService returns lazily initialized calendar service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getCalendarService() service.CalendarService {
+	calendarOnce.Do(func() {
+		calendarRepo = repository.NewCalendarRepository(database.GetDB())
+		calendarService = service.NewCalendarService(calendarRepo)
+	})
+	return calendarService
+}
+
+// GetCalendar godoc
+// @Summary Get working-day calendar
+// @Description Get the tenant working weekdays and the holidays / company days off of a year
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param year query int false "Year (default: current year)"
+// @Success 200 {object} utils.GinResponse
+// @Router /calendar [get]
+func GetCalendar(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 2000 || parsed > 2100 {
+			utils.GinErrorResponse(c, 400, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	calendar, err := getCalendarService().GetCalendar(tenantID, year)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch calendar")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Calendar retrieved successfully", calendar)
+}
+
+// UpdateCalendar godoc
+// @Summary Update working weekdays
+// @Description Set the tenant working weekdays (admin/HR only). Used by schedules that do not set their own working days.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TenantCalendarRequest true "Working weekdays bitmask, bit 0 = Sunday ... bit 6 = Saturday"
+// @Success 200 {object} utils.GinResponse
+// @Router /calendar [put]
+func UpdateCalendar(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.TenantCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	if err := getCalendarService().UpdateWorkingDays(tenantID, req.WorkingDays); err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update calendar")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Calendar updated successfully", gin.H{"working_days": req.WorkingDays})
+}
+
+// CreateHoliday godoc
+// @Summary Add holiday
+// @Description Add a holiday or company-specific day off to the tenant calendar (admin/HR only)
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.HolidayRequest true "Holiday data"
+// @Success 201 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /calendar/holidays [post]
+func CreateHoliday(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	holiday := &models.Holiday{
+		TenantID: tenantID,
+		Date:     req.Date,
+		Name:     req.Name,
+		Type:     req.Type,
+	}
+
+	err := getCalendarService().CreateHoliday(holiday)
+	if err == service.ErrInvalidHolidayDate {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == repository.ErrHolidayExists {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to create holiday")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Holiday created successfully", holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday
+// @Description Remove a holiday or company day off from the tenant calendar (admin/HR only)
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Holiday ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /calendar/holidays/{id} [delete]
+func DeleteHoliday(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	holidayID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid holiday ID")
+		return
+	}
+
+	err = getCalendarService().DeleteHoliday(tenantID, holidayID)
+	if err == repository.ErrHolidayNotFound {
+		utils.GinErrorResponse(c, 404, "Holiday not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete holiday")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Holiday deleted successfully", nil)
+}
+
+// ImportNationalHolidays godoc
+// @Summary Import Indonesian national holidays
+// @Description Import the bundled Indonesian national holidays (libur nasional) of a year into the tenant calendar (admin/HR only).
+// @Description Dates that already have a holiday are left untouched, so the import is safe to repeat.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.HolidayImportRequest true "Year to import"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /calendar/holidays/import [post]
+func ImportNationalHolidays(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.HolidayImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	imported, err := getCalendarService().ImportNationalHolidays(tenantID, req.Year)
+	if err == service.ErrHolidaySetMissing {
+		utils.GinErrorResponse(c, 404, fmt.Sprintf("%s, available years: %v", err.Error(), utils.IndonesianHolidayYears()))
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to import national holidays")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "National holidays imported successfully", gin.H{
+		"year":     req.Year,
+		"imported": imported,
+	})
+}
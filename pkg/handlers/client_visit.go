@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	clientVisitService service.ClientVisitService
+	clientVisitOnce    sync.Once
+)
+
+// getClientVisitService returns lazily initialized client visit service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getClientVisitService() service.ClientVisitService {
+	clientVisitOnce.Do(func() {
+		db := database.GetDB()
+		clientVisitService = service.NewClientVisitService(
+			repository.NewClientVisitRepository(db),
+			repository.NewCRMRepository(db),
+			repository.NewTenantSettingsRepository(db),
+		)
+	})
+	return clientVisitService
+}
+
+// withVisitPhotoURLs mengganti storage key foto kunjungan dengan signed URL
+func withVisitPhotoURLs(visit *models.ClientVisit) {
+	visit.CheckInPhotoURL = signedFileURL(visit.CheckInPhoto)
+	visit.CheckOutPhotoURL = signedFileURL(visit.CheckOutPhoto)
+}
+
+// CheckInClientVisit godoc
+// @Summary Check in at a client
+// @Description Start a geotagged visit to the client of a CRM deal with location, photo and notes.
+// @Description This is separate from office attendance; only one visit can be in progress at a time.
+// @Tags crm
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.VisitCheckInRequest true "Visit check-in data"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /crm/visits/check-in [post]
+func CheckInClientVisit(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.VisitCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	photoKey, err := storeVisitPhoto(c.Request.Context(), tenantID, employeeID, "checkin", req.PhotoSelfie, now)
+	if err != nil {
+		photoErrorResponse(c, err)
+		return
+	}
+
+	visit := &models.ClientVisit{
+		TenantID:         tenantID,
+		EmployeeID:       employeeID,
+		UserID:           userID,
+		ProjectID:        req.CRMProjectID,
+		Client:           req.Client,
+		CheckInTime:      now,
+		CheckInLatitude:  req.Latitude,
+		CheckInLongitude: req.Longitude,
+		CheckInAccuracy:  req.Accuracy,
+		CheckInPhoto:     photoKey,
+		CheckInNotes:     req.Notes,
+	}
+	if err := getClientVisitService().CheckIn(visit); err != nil {
+		deleteAttendancePhoto(photoKey)
+		code := clientVisitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to check in client visit"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	withVisitPhotoURLs(visit)
+	utils.GinSuccessResponse(c, 201, "Client visit checked in successfully", visit)
+}
+
+// CheckOutClientVisit godoc
+// @Summary Check out from a client
+// @Description End one of your ongoing client visits. The visit duration and the distance from the
+// @Description check-in location are recorded.
+// @Tags crm
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Client visit ID"
+// @Param request body models.VisitCheckOutRequest true "Visit check-out data"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /crm/visits/{id}/check-out [post]
+func CheckOutClientVisit(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid client visit ID")
+		return
+	}
+
+	var req models.VisitCheckOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	photoKey, err := storeVisitPhoto(c.Request.Context(), tenantID, employeeID, "checkout", req.PhotoSelfie, now)
+	if err != nil {
+		photoErrorResponse(c, err)
+		return
+	}
+
+	visit, err := getClientVisitService().CheckOut(tenantID, employeeID, visitID, models.ClientVisit{
+		CheckOutTime:      &now,
+		CheckOutLatitude:  &req.Latitude,
+		CheckOutLongitude: &req.Longitude,
+		CheckOutAccuracy:  &req.Accuracy,
+		CheckOutPhoto:     photoKey,
+		CheckOutNotes:     req.Notes,
+	})
+	if err != nil {
+		deleteAttendancePhoto(photoKey)
+		code := clientVisitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to check out client visit"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	withVisitPhotoURLs(visit)
+	utils.GinSuccessResponse(c, 200, "Client visit checked out successfully", visit)
+}
+
+// GetCurrentClientVisit godoc
+// @Summary Get current client visit
+// @Description Get your client visit that has not been checked out yet, null when there is none
+// @Tags crm
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /crm/visits/current [get]
+func GetCurrentClientVisit(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	visit, err := getClientVisitService().GetOpenVisit(tenantID, employeeID)
+	if err == repository.ErrClientVisitNotFound {
+		utils.GinSuccessResponse(c, 200, "No client visit in progress", nil)
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch current client visit")
+		return
+	}
+
+	withVisitPhotoURLs(visit)
+	utils.GinSuccessResponse(c, 200, "Current client visit retrieved successfully", visit)
+}
+
+// GetCRMProjectVisits godoc
+// @Summary Get visit history of a CRM deal
+// @Description Get all client visits of a CRM deal, newest first. Available to admin/HR, the deal
+// @Description manager and employees who visited the deal.
+// @Tags crm
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "CRM Project ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /crm/projects/{id}/visits [get]
+func GetCRMProjectVisits(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid project ID")
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve employee")
+		return
+	}
+
+	visits, err := getClientVisitService().GetProjectVisits(tenantID, approver, projectID)
+	if err != nil {
+		code := clientVisitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to fetch CRM project visits"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	for i := range visits {
+		withVisitPhotoURLs(&visits[i])
+	}
+	utils.GinSuccessResponse(c, 200, "CRM project visits retrieved successfully", visits)
+}
+
+// GetDailyVisitReport godoc
+// @Summary Get daily client visit report
+// @Description Get the client visits of a salesperson on one day with visit durations. Defaults to your
+// @Description own visits today; supervisors may pass a direct report's employee_id, admin/HR anyone's.
+// @Tags crm
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Date (YYYY-MM-DD), defaults to today in the tenant timezone"
+// @Param employee_id query string false "Employee ID, defaults to yourself"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Router /crm/visits/report [get]
+func GetDailyVisitReport(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve employee")
+		return
+	}
+
+	var employeeID uuid.UUID
+	if value := c.Query("employee_id"); value != "" {
+		employeeID, err = uuid.Parse(value)
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid employee ID")
+			return
+		}
+	} else if approver.EmployeeID != nil {
+		employeeID = *approver.EmployeeID
+	} else {
+		utils.GinErrorResponse(c, 403, "User is not registered as an employee")
+		return
+	}
+
+	report, err := getClientVisitService().GetDailyReport(tenantID, approver, employeeID, c.Query("date"), time.Now())
+	if err != nil {
+		code := clientVisitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to fetch daily visit report"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	for i := range report.Visits {
+		withVisitPhotoURLs(&report.Visits[i])
+	}
+	utils.GinSuccessResponse(c, 200, "Daily visit report retrieved successfully", report)
+}
+
+func clientVisitErrorCode(err error) int {
+	switch err {
+	case service.ErrVisitClientRequired, service.ErrInvalidVisitDate:
+		return 400
+	case service.ErrVisitForbidden:
+		return 403
+	case service.ErrVisitProjectNotFound, repository.ErrClientVisitNotFound:
+		return 404
+	case repository.ErrClientVisitInProgress, repository.ErrClientVisitClosed:
+		return 409
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// Role yang boleh mengelola data attendance seluruh tenant
+var attendanceAdminRoles = []string{"admin", "hr"}
+
+// getAuthContext mengambil tenant ID dan user ID yang di-set oleh GinAuthMiddleware.
+// Jika gagal, error response sudah ditulis dan ok bernilai false.
+func getAuthContext(c *gin.Context) (tenantID uuid.UUID, userID uuid.UUID, ok bool) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		utils.GinErrorResponse(c, 401, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID, err := uuid.Parse(c.GetString("tenant_id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 401, "Invalid tenant ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	switch v := userIDVal.(type) {
+	case uuid.UUID:
+		userID = v
+	case string:
+		userID, err = uuid.Parse(v)
+		if err != nil {
+			utils.GinErrorResponse(c, 500, "Invalid user ID format")
+			return uuid.Nil, uuid.Nil, false
+		}
+	default:
+		utils.GinErrorResponse(c, 500, "Invalid user ID type")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, userID, true
+}
+
+// getEmployeeID mengambil employee ID milik user di tenant tersebut.
+// Mengembalikan sql.ErrNoRows jika user belum terdaftar sebagai employee.
+func getEmployeeID(tenantID, userID uuid.UUID) (uuid.UUID, error) {
+	var employeeID uuid.UUID
+	err := database.DB.QueryRow(
+		`SELECT id FROM godplan.employees WHERE user_id = $1 AND tenant_id = $2`,
+		userID, tenantID,
+	).Scan(&employeeID)
+	return employeeID, err
+}
+
+// requireEmployeeID mengambil employee ID user yang login. Jika user belum terdaftar
+// sebagai employee, error response sudah ditulis dan ok bernilai false.
+func requireEmployeeID(c *gin.Context, tenantID, userID uuid.UUID) (uuid.UUID, bool) {
+	employeeID, err := getEmployeeID(tenantID, userID)
+	if err == sql.ErrNoRows {
+		utils.GinErrorResponse(c, 403, "User is not registered as an employee")
+		return uuid.Nil, false
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve employee")
+		return uuid.Nil, false
+	}
+	return employeeID, true
+}
+
+// hasAnyRole mengecek role user yang di-set oleh GinAuthMiddleware
+func hasAnyRole(c *gin.Context, roles ...string) bool {
+	role := c.GetString("role")
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// getAttendanceApprover menyusun approver dari user yang sedang login.
+// Admin/HR boleh memutuskan semua attendance, selain itu hanya bawahan langsung.
+func getAttendanceApprover(c *gin.Context, tenantID, userID uuid.UUID) (models.AttendanceApprover, error) {
+	approver := models.AttendanceApprover{
+		UserID:        userID,
+		CanApproveAll: hasAnyRole(c, attendanceAdminRoles...),
+	}
+
+	employeeID, err := getEmployeeID(tenantID, userID)
+	if err == sql.ErrNoRows {
+		return approver, nil
+	}
+	if err != nil {
+		return approver, err
+	}
+	approver.EmployeeID = &employeeID
+	return approver, nil
+}
@@ -65,8 +65,8 @@ func GetHomeDashboard(c *gin.Context) {
 	// Get team members
 	teamMembers := getTeamMembers(tenantID, userID)
 
-	// Get greeting based on current time
-	greeting := getGreeting()
+	// Get greeting based on current time in the tenant timezone
+	greeting := utils.Greeting(time.Now().In(getTimezoneService().TenantLocation(tenantID)))
 
 	response := models.HomeDashboardResponse{
 		Stats:       stats,
@@ -176,6 +176,9 @@ func getDashboardStats(tenantID uuid.UUID, userID uuid.UUID) models.DashboardSta
 		return stats
 	}
 
+	// "Hari ini" mengikuti timezone tenant, bukan timezone server/database
+	today := time.Now().In(getTimezoneService().TenantLocation(tenantID)).Format("2006-01-02")
+
 	// Execute remaining queries in parallel
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -214,15 +217,28 @@ func getDashboardStats(tenantID uuid.UUID, userID uuid.UUID) models.DashboardSta
 		mu.Unlock()
 	}()
 
-	// 3. Check today's attendance
+	// 3. Check today's attendance (hari libur dan hari non-kerja tidak dihitung absent)
 	go func() {
 		defer wg.Done()
 		var attendanceStatus string
 		err := database.DB.QueryRow(`
 			SELECT CASE 
-				WHEN EXISTS (SELECT 1 FROM godplan.attendances WHERE user_id = $1 AND tenant_id = $2 AND attendance_date = CURRENT_DATE) 
-				THEN 'present' ELSE 'absent' END
-		`, userID, tenantID).Scan(&attendanceStatus)
+				WHEN EXISTS (SELECT 1 FROM godplan.attendances WHERE user_id = $1 AND tenant_id = $2 AND attendance_date = $4::date) 
+				THEN 'present'
+				WHEN EXISTS (SELECT 1 FROM godplan.leave_requests WHERE employee_id = $3 AND tenant_id = $2 AND status = 'approved' AND $4::date BETWEEN start_date AND end_date)
+				THEN 'on_leave'
+				WHEN EXISTS (SELECT 1 FROM godplan.tenant_holidays WHERE tenant_id = $2 AND holiday_date = $4::date)
+				THEN 'holiday'
+				WHEN (COALESCE(
+					(SELECT NULLIF(s.working_days, 0) FROM godplan.employees e
+						JOIN godplan.attendance_schedules s ON s.id = e.schedule_id AND COALESCE(s.is_active, true) = true
+						WHERE e.id = $3),
+					(SELECT NULLIF(working_days, 0) FROM godplan.attendance_schedules
+						WHERE tenant_id = $2 AND is_default = true AND COALESCE(is_active, true) = true LIMIT 1),
+					(SELECT working_days FROM godplan.tenant_calendars WHERE tenant_id = $2),
+					62) & (1 << EXTRACT(DOW FROM $4::date)::int)) = 0
+				THEN 'day_off' ELSE 'absent' END
+		`, userID, tenantID, employeeID, today).Scan(&attendanceStatus)
 		if err != nil {
 			attendanceStatus = "absent"
 		}
@@ -295,15 +311,3 @@ func getTeamMembers(tenantID uuid.UUID, userID uuid.UUID) []models.TeamMember {
 	// Return whatever we got from database (could be empty)
 	return members
 }
-
-func getGreeting() string {
-	hour := time.Now().Hour()
-	switch {
-	case hour < 12:
-		return "Selamat Pagi"
-	case hour < 18:
-		return "Selamat Siang"
-	default:
-		return "Selamat Malam"
-	}
-}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	geofenceSimulationService service.GeofenceSimulationService
+	geofenceSimulationOnce    sync.Once
+)
+
+// getGeofenceSimulationService returns lazily initialized geofence simulation service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getGeofenceSimulationService() service.GeofenceSimulationService {
+	geofenceSimulationOnce.Do(func() {
+		db := database.GetDB()
+		geofenceSimulationService = service.NewGeofenceSimulationService(
+			repository.NewAttendanceRepository(db),
+			repository.NewOfficeLocationRepository(db),
+			repository.NewTenantSettingsRepository(db),
+		)
+	})
+	return geofenceSimulationService
+}
+
+// SimulateGeofence godoc
+// @Summary Simulate a geofence change
+// @Description Dry run: replay the GPS clock ins of a period (max 92 days) against proposed office radii and attendance policy.
+// @Description Reports how many clock ins would flip between approved, auto_approved and needs_force, per office and per GPS quality.
+// @Description Clock ins before GPS accuracy was recorded are replayed with unknown accuracy (admin/HR only).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GeofenceSimulationRequest true "Proposed geofence configuration"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/geofence-simulation [post]
+func SimulateGeofence(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.GeofenceSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	report, err := getGeofenceSimulationService().Simulate(tenantID, req)
+	if err == service.ErrInvalidSimulationPeriod || err == service.ErrUnknownSimulationOffice {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to simulate geofence")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Geofence simulation completed successfully", report)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	kioskRepo    repository.KioskRepository
+	kioskService service.KioskService
+	kioskOnce    sync.Once
+)
+
+// getKioskService returns lazily initialized kiosk service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getKioskService() service.KioskService {
+	kioskOnce.Do(func() {
+		kioskRepo = repository.NewKioskRepository(database.GetDB())
+		kioskService = service.NewKioskService(kioskRepo, repository.NewOfficeLocationRepository(database.GetDB()))
+	})
+	return kioskService
+}
+
+// GetKiosks godoc
+// @Summary Get attendance kiosks
+// @Description Get all QR code attendance kiosks of the current tenant (admin/HR only)
+// @Tags kiosks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /kiosks [get]
+func GetKiosks(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	kiosks, err := getKioskService().GetKiosks(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch kiosks")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Kiosks retrieved successfully", kiosks)
+}
+
+// RegisterKiosk godoc
+// @Summary Register attendance kiosk
+// @Description Register a reception tablet that shows a rotating QR code for an office (admin/HR only).
+// @Description The returned token is shown only once; the kiosk sends it as "Authorization: Kiosk <token>".
+// @Tags kiosks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AttendanceKioskRequest true "Kiosk data"
+// @Success 201 {object} utils.GinResponse
+// @Router /kiosks [post]
+func RegisterKiosk(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.AttendanceKioskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	registration, err := getKioskService().RegisterKiosk(&models.AttendanceKiosk{
+		TenantID:         tenantID,
+		OfficeLocationID: req.OfficeLocationID,
+		Name:             req.Name,
+	})
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to register kiosk")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Kiosk registered successfully", registration)
+}
+
+// RotateKioskToken godoc
+// @Summary Rotate kiosk token
+// @Description Issue a new token and QR signing secret for a kiosk, e.g. when the tablet is lost (admin/HR only)
+// @Tags kiosks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Kiosk ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /kiosks/{id}/rotate-token [post]
+func RotateKioskToken(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	kioskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid kiosk ID")
+		return
+	}
+
+	registration, err := getKioskService().RotateKioskToken(tenantID, kioskID)
+	if err == repository.ErrKioskNotFound {
+		utils.GinErrorResponse(c, 404, "Kiosk not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to rotate kiosk token")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Kiosk token rotated successfully", registration)
+}
+
+// DeleteKiosk godoc
+// @Summary Delete attendance kiosk
+// @Description Delete a kiosk; its token and QR codes stop working immediately (admin/HR only)
+// @Tags kiosks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Kiosk ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /kiosks/{id} [delete]
+func DeleteKiosk(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	kioskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid kiosk ID")
+		return
+	}
+
+	err = getKioskService().DeleteKiosk(tenantID, kioskID)
+	if err == repository.ErrKioskNotFound {
+		utils.GinErrorResponse(c, 404, "Kiosk not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete kiosk")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Kiosk deleted successfully", nil)
+}
+
+// GetKioskCode godoc
+// @Summary Get current kiosk QR code
+// @Description Called by the kiosk tablet to get the QR code to display. The code changes every 30 seconds;
+// @Description employees scan it and send it as kiosk_code when clocking in or out.
+// @Tags kiosks
+// @Produce json
+// @Param Authorization header string true "Kiosk <token>"
+// @Success 200 {object} utils.GinResponse
+// @Failure 401 {object} utils.GinResponse
+// @Router /kiosk/code [get]
+func GetKioskCode(c *gin.Context) {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Kiosk ")
+	if !found {
+		utils.GinErrorResponse(c, 401, "Kiosk token required")
+		return
+	}
+
+	kiosk, err := getKioskService().AuthenticateKiosk(strings.TrimSpace(token))
+	if err == repository.ErrKioskNotFound {
+		utils.GinErrorResponse(c, 401, "Invalid kiosk token")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to authenticate kiosk")
+		return
+	}
+
+	// QR code tidak boleh di-cache agar selalu mengikuti periode terbaru
+	c.Header("Cache-Control", "no-store")
+	utils.GinSuccessResponse(c, 200, "Kiosk code issued", getKioskService().IssueCode(kiosk, time.Now()))
+}
@@ -0,0 +1,572 @@
+package handlers
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	leaveRepo    repository.LeaveRepository
+	leaveService service.LeaveService
+	leaveOnce    sync.Once
+)
+
+// getLeaveService returns lazily initialized leave service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getLeaveService() service.LeaveService {
+	leaveOnce.Do(func() {
+		leaveRepo = repository.NewLeaveRepository(database.GetDB())
+		leaveService = service.NewLeaveService(leaveRepo,
+			repository.NewScheduleRepository(database.GetDB()), repository.NewCalendarRepository(database.GetDB()))
+	})
+	return leaveService
+}
+
+// GetLeaveTypes godoc
+// @Summary Get leave types
+// @Description Get the leave types (cuti, izin, sakit, ...) of the current tenant
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/types [get]
+func GetLeaveTypes(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	leaveTypes, err := getLeaveService().GetLeaveTypes(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch leave types")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave types retrieved successfully", leaveTypes)
+}
+
+// CreateLeaveType godoc
+// @Summary Create leave type
+// @Description Create a tenant leave type (admin/HR only)
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.LeaveTypeRequest true "Leave type data"
+// @Success 201 {object} utils.GinResponse
+// @Router /leave/types [post]
+func CreateLeaveType(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	leaveType := &models.LeaveType{TenantID: tenantID, IsActive: true}
+	applyLeaveTypeRequest(leaveType, req)
+
+	err := getLeaveService().CreateLeaveType(leaveType)
+	if err == repository.ErrLeaveTypeExists {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to create leave type")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Leave type created successfully", leaveType)
+}
+
+// UpdateLeaveType godoc
+// @Summary Update leave type
+// @Description Update a tenant leave type (admin/HR only). Existing balances keep their entitlement.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave Type ID"
+// @Param request body models.LeaveTypeRequest true "Leave type data"
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/types/{id} [put]
+func UpdateLeaveType(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	leaveTypeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid leave type ID")
+		return
+	}
+
+	var req models.LeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	leaveType, err := getLeaveService().GetLeaveTypeByID(tenantID, leaveTypeID)
+	if err == repository.ErrLeaveTypeNotFound {
+		utils.GinErrorResponse(c, 404, "Leave type not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch leave type")
+		return
+	}
+
+	applyLeaveTypeRequest(leaveType, req)
+
+	err = getLeaveService().UpdateLeaveType(leaveType)
+	if err == repository.ErrLeaveTypeExists {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update leave type")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave type updated successfully", leaveType)
+}
+
+func applyLeaveTypeRequest(leaveType *models.LeaveType, req models.LeaveTypeRequest) {
+	leaveType.Code = req.Code
+	leaveType.Name = req.Name
+	leaveType.IsPaid = req.IsPaid
+	leaveType.TracksBalance = req.TracksBalance
+	leaveType.AnnualQuota = req.AnnualQuota
+	leaveType.MaxCarryOver = req.MaxCarryOver
+	leaveType.RequiresAttachment = req.RequiresAttachment
+	if req.IsActive != nil {
+		leaveType.IsActive = *req.IsActive
+	}
+}
+
+// DeleteLeaveType godoc
+// @Summary Delete leave type
+// @Description Delete a leave type that has never been requested (admin/HR only). Used types should be deactivated instead.
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave Type ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/types/{id} [delete]
+func DeleteLeaveType(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	leaveTypeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid leave type ID")
+		return
+	}
+
+	err = getLeaveService().DeleteLeaveType(tenantID, leaveTypeID)
+	if err == repository.ErrLeaveTypeNotFound {
+		utils.GinErrorResponse(c, 404, "Leave type not found")
+		return
+	}
+	if err == repository.ErrLeaveTypeInUse {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete leave type")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave type deleted successfully", nil)
+}
+
+// GetLeaveBalances godoc
+// @Summary Get leave balances
+// @Description Get the yearly leave balances of the logged-in employee. Admin/HR may pass employee_id to see another employee.
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param year query int false "Year (default: current year)"
+// @Param employee_id query string false "Employee ID (admin/HR only)"
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/balances [get]
+func GetLeaveBalances(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 2000 || parsed > 2100 {
+			utils.GinErrorResponse(c, 400, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	var employeeID uuid.UUID
+	if value := c.Query("employee_id"); value != "" {
+		if !hasAnyRole(c, attendanceAdminRoles...) {
+			utils.GinErrorResponse(c, 403, "Only admin or HR can view other employees' balances")
+			return
+		}
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid employee ID")
+			return
+		}
+		employeeID = parsed
+	} else {
+		if employeeID, ok = requireEmployeeID(c, tenantID, userID); !ok {
+			return
+		}
+	}
+
+	balances, err := getLeaveService().GetLeaveBalances(tenantID, employeeID, year)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch leave balances")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave balances retrieved successfully", balances)
+}
+
+// AccrueLeaveBalances godoc
+// @Summary Accrue yearly leave balances
+// @Description Create the balances of a year for every employee (admin/HR only). The quota is prorated in the join year
+// @Description and unused days are carried over up to the leave type limit. Existing balances are not changed.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.LeaveAccrualRequest true "Year to accrue"
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/balances/accrue [post]
+func AccrueLeaveBalances(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeaveAccrualRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	created, err := getLeaveService().AccrueBalances(tenantID, req.Year, nil)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to accrue leave balances")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave balances accrued successfully", gin.H{
+		"year":    req.Year,
+		"created": created,
+	})
+}
+
+// GetLeaveRequests godoc
+// @Summary Get my leave requests
+// @Description Get the leave requests of the logged-in employee
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/requests [get]
+func GetLeaveRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	requests, err := getLeaveService().GetLeaveRequests(tenantID, employeeID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch leave requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave requests retrieved successfully", withAttachmentURLs(requests))
+}
+
+// CreateLeaveRequest godoc
+// @Summary Request leave
+// @Description Submit a cuti, izin or sakit request for supervisor approval. Days are counted on the employee's working days.
+// @Description Leave types that require an attachment (e.g. sick leave) need a base64 JPEG, PNG or PDF document.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateLeaveRequest true "Leave request"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /leave/requests [post]
+func CreateLeaveRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	attachmentKey, err := storeLeaveAttachment(c.Request.Context(), tenantID, employeeID, req.Attachment, time.Now())
+	if err != nil {
+		photoErrorResponse(c, err)
+		return
+	}
+
+	request := &models.LeaveRequest{
+		TenantID:      tenantID,
+		EmployeeID:    employeeID,
+		UserID:        userID,
+		LeaveTypeID:   req.LeaveTypeID,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		Reason:        req.Reason,
+		AttachmentKey: attachmentKey,
+	}
+
+	if err := getLeaveService().SubmitLeaveRequest(request); err != nil {
+		// Dokumen yang sudah ter-upload dihapus agar tidak menjadi file yatim
+		deleteAttendancePhoto(attachmentKey)
+
+		code := leaveSubmitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to submit leave request"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	request.AttachmentURL = signedFileURL(request.AttachmentKey)
+	utils.GinSuccessResponse(c, 201, "Leave request submitted successfully", request)
+}
+
+// CancelLeaveRequest godoc
+// @Summary Cancel leave request
+// @Description Withdraw one of your own pending leave requests
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave Request ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /leave/requests/{id}/cancel [post]
+func CancelLeaveRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid leave request ID")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	err = getLeaveService().CancelLeaveRequest(tenantID, employeeID, requestID)
+	if err == repository.ErrLeaveRequestNotFound {
+		utils.GinErrorResponse(c, 404, "Leave request not found")
+		return
+	}
+	if err == repository.ErrLeaveRequestNotPending {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to cancel leave request")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Leave request cancelled successfully", nil)
+}
+
+// GetPendingLeaveRequests godoc
+// @Summary Get pending leave approvals
+// @Description Get leave requests waiting for a decision. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /leave/approvals [get]
+func GetPendingLeaveRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	requests, err := getLeaveService().GetPendingLeaveRequests(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending leave requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending leave requests retrieved successfully", withAttachmentURLs(requests))
+}
+
+// ApproveLeaveRequest godoc
+// @Summary Approve leave request
+// @Description Approve a pending leave request of a direct report. Balance-tracked leave is deducted from the yearly balance.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave Request ID"
+// @Param request body models.LeaveDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /leave/requests/{id}/approve [post]
+func ApproveLeaveRequest(c *gin.Context) {
+	decideLeaveRequest(c, true)
+}
+
+// RejectLeaveRequest godoc
+// @Summary Reject leave request
+// @Description Reject a pending leave request of a direct report. A reason is required.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave Request ID"
+// @Param request body models.LeaveDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /leave/requests/{id}/reject [post]
+func RejectLeaveRequest(c *gin.Context) {
+	decideLeaveRequest(c, false)
+}
+
+func decideLeaveRequest(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid leave request ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.LeaveDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getLeaveService().DecideLeaveRequest(tenantID, approver, requestID, approve, req.Reason)
+	if err != nil {
+		code := leaveDecisionErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to process leave decision"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	result := gin.H{"leave_request_id": requestID, "status": status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "Leave request approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "Leave request rejected successfully", result)
+}
+
+func leaveSubmitErrorCode(err error) int {
+	switch err {
+	case service.ErrInvalidLeavePeriod, service.ErrLeaveCrossesYear, service.ErrNoLeaveWorkingDays, service.ErrLeaveAttachmentRequired,
+		service.ErrLeaveTypeInactive, service.ErrInsufficientLeaveBalance:
+		return 400
+	case repository.ErrLeaveTypeNotFound:
+		return 404
+	case service.ErrLeaveOverlap:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func leaveDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired, service.ErrInsufficientLeaveBalance:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfLeaveApproval:
+		return 403
+	case repository.ErrLeaveRequestNotFound:
+		return 404
+	case repository.ErrLeaveRequestNotPending:
+		return 409
+	default:
+		return 500
+	}
+}
+
+// withAttachmentURLs mengisi signed URL dokumen pendukung setiap leave request
+func withAttachmentURLs(requests []models.LeaveRequest) []models.LeaveRequest {
+	for i := range requests {
+		requests[i].AttachmentURL = signedFileURL(requests[i].AttachmentKey)
+	}
+	return requests
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	notificationRepo    repository.NotificationRepository
+	notificationService service.NotificationService
+	notificationOnce    sync.Once
+)
+
+// getNotificationService returns lazily initialized notification service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getNotificationService() service.NotificationService {
+	notificationOnce.Do(func() {
+		notificationRepo = repository.NewNotificationRepository(database.GetDB())
+		notificationService = service.NewNotificationService(notificationRepo)
+	})
+	return notificationService
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the newest in-app notifications of the current user, e.g. sessions closed by auto clock-out
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Maximum number of notifications (default 50, max 200)"
+// @Success 200 {object} utils.GinResponse
+// @Router /notifications [get]
+func GetNotifications(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 200 {
+			utils.GinErrorResponse(c, 400, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	notifications, unread, err := getNotificationService().GetNotifications(tenantID, userID, unreadOnly, limit)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch notifications")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Notifications retrieved successfully", gin.H{
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark one notification of the current user as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /notifications/{id}/read [post]
+func MarkNotificationRead(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid notification ID")
+		return
+	}
+
+	err = getNotificationService().MarkRead(tenantID, userID, id)
+	if err == repository.ErrNotificationNotFound {
+		utils.GinErrorResponse(c, 404, "Notification not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update notification")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Notification marked as read", nil)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /notifications/read-all [post]
+func MarkAllNotificationsRead(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	updated, err := getNotificationService().MarkAllRead(tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update notifications")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Notifications marked as read", gin.H{"updated": updated})
+}
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	officeLocationRepo    repository.OfficeLocationRepository
+	officeLocationService service.OfficeLocationService
+	officeLocationOnce    sync.Once
+)
+
+// getOfficeLocationService returns lazily initialized office location service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getOfficeLocationService() service.OfficeLocationService {
+	officeLocationOnce.Do(func() {
+		officeLocationRepo = repository.NewOfficeLocationRepository(database.GetDB())
+		officeLocationService = service.NewOfficeLocationService(officeLocationRepo)
+	})
+	return officeLocationService
+}
+
+// GetOfficeLocations godoc
+// @Summary Get office locations
+// @Description Get all office, branch and client-site geofences for the current tenant
+// @Tags office-locations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations [get]
+func GetOfficeLocations(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locations, err := getOfficeLocationService().GetLocations(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch office locations")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Office locations retrieved successfully", locations)
+}
+
+// GetOfficeLocation godoc
+// @Summary Get office location by ID
+// @Description Get a specific office location by ID
+// @Tags office-locations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations/{id} [get]
+func GetOfficeLocation(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	location, err := getOfficeLocationService().GetLocationByID(tenantID, locationID)
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch office location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Office location retrieved successfully", location)
+}
+
+// CreateOfficeLocation godoc
+// @Summary Create office location
+// @Description Register a new office, branch or client-site geofence (admin/HR only).
+// @Description Provide either a radius in meters or a GeoJSON Polygon boundary (with optional holes).
+// @Tags office-locations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.OfficeLocationRequest true "Office location data"
+// @Success 201 {object} utils.GinResponse
+// @Router /office-locations [post]
+func CreateOfficeLocation(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.OfficeLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	location := &models.OfficeLocation{
+		TenantID:  tenantID,
+		Name:      req.Name,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Radius:    req.Radius,
+		Boundary:  req.Boundary,
+		Address:   req.Address,
+		Timezone:  req.Timezone,
+		IsActive:  true,
+	}
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+
+	err := getOfficeLocationService().CreateLocation(location)
+	if err == service.ErrInvalidGeofence || err == utils.ErrInvalidTimezone {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to create office location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Office location created successfully", location)
+}
+
+// UpdateOfficeLocation godoc
+// @Summary Update office location
+// @Description Update an existing office location (admin/HR only)
+// @Tags office-locations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Param request body models.OfficeLocationRequest true "Office location data"
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations/{id} [put]
+func UpdateOfficeLocation(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	var req models.OfficeLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	location, err := getOfficeLocationService().GetLocationByID(tenantID, locationID)
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch office location")
+		return
+	}
+
+	location.Name = req.Name
+	location.Latitude = req.Latitude
+	location.Longitude = req.Longitude
+	location.Radius = req.Radius
+	location.Boundary = req.Boundary
+	location.Address = req.Address
+	location.Timezone = req.Timezone
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+
+	err = getOfficeLocationService().UpdateLocation(location)
+	if err == service.ErrInvalidGeofence || err == utils.ErrInvalidTimezone {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update office location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Office location updated successfully", location)
+}
+
+// DeleteOfficeLocation godoc
+// @Summary Delete office location
+// @Description Delete an office location (admin/HR only). Past attendances keep their history with a NULL office.
+// @Tags office-locations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations/{id} [delete]
+func DeleteOfficeLocation(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	err = getOfficeLocationService().DeleteLocation(tenantID, locationID)
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete office location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Office location deleted successfully", nil)
+}
+
+// GetOfficePresenceSignals godoc
+// @Summary Get office presence signals
+// @Description Get the Wi-Fi BSSIDs and BLE beacons registered as proof of presence for an office
+// @Tags office-locations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations/{id}/signals [get]
+func GetOfficePresenceSignals(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	signals, err := getOfficeLocationService().GetSignals(tenantID, locationID)
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch presence signals")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Presence signals retrieved successfully", signals)
+}
+
+// CreateOfficePresenceSignal godoc
+// @Summary Register office presence signal
+// @Description Register a Wi-Fi BSSID or BLE beacon ID of an office (admin/HR only).
+// @Description Clock in/out requests reporting this signal are treated as in range even when GPS is inaccurate.
+// @Tags office-locations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Param request body models.OfficePresenceSignalRequest true "Presence signal data"
+// @Success 201 {object} utils.GinResponse
+// @Router /office-locations/{id}/signals [post]
+func CreateOfficePresenceSignal(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	var req models.OfficePresenceSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	signal := &models.OfficePresenceSignal{
+		TenantID:         tenantID,
+		OfficeLocationID: locationID,
+		SignalType:       req.SignalType,
+		Identifier:       req.Identifier,
+		Label:            req.Label,
+		IsActive:         true,
+	}
+	if req.IsActive != nil {
+		signal.IsActive = *req.IsActive
+	}
+
+	err = getOfficeLocationService().CreateSignal(signal)
+	if err == utils.ErrInvalidSignalIdentifier {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == repository.ErrOfficeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Office location not found")
+		return
+	}
+	if err == repository.ErrPresenceSignalDuplicate {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to register presence signal")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Presence signal registered successfully", signal)
+}
+
+// DeleteOfficePresenceSignal godoc
+// @Summary Delete office presence signal
+// @Description Remove a Wi-Fi BSSID or BLE beacon from an office (admin/HR only)
+// @Tags office-locations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Office Location ID"
+// @Param signalId path string true "Presence Signal ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /office-locations/{id}/signals/{signalId} [delete]
+func DeleteOfficePresenceSignal(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid office location ID")
+		return
+	}
+
+	signalID, err := uuid.Parse(c.Param("signalId"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid presence signal ID")
+		return
+	}
+
+	err = getOfficeLocationService().DeleteSignal(tenantID, locationID, signalID)
+	if err == repository.ErrPresenceSignalNotFound {
+		utils.GinErrorResponse(c, 404, "Presence signal not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete presence signal")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Presence signal deleted successfully", nil)
+}
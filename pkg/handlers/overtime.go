@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	overtimeService service.OvertimeService
+	overtimeOnce    sync.Once
+)
+
+// getOvertimeService returns lazily initialized overtime service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getOvertimeService() service.OvertimeService {
+	overtimeOnce.Do(func() {
+		db := database.GetDB()
+		overtimeService = service.NewOvertimeService(
+			repository.NewOvertimeRepository(db),
+			repository.NewCalendarRepository(db),
+			repository.NewScheduleRepository(db),
+			repository.NewTenantSettingsRepository(db),
+			repository.NewNotificationRepository(db),
+		)
+	})
+	return overtimeService
+}
+
+// GetOvertimeRequests godoc
+// @Summary Get my overtime requests
+// @Description Get the overtime requests of the logged-in employee with their reconciled minutes and compensable hours
+// @Tags overtime
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /overtime/requests [get]
+func GetOvertimeRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	requests, err := getOvertimeService().GetOvertimeRequests(tenantID, employeeID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch overtime requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Overtime requests retrieved successfully", requests)
+}
+
+// CreateOvertimeRequest godoc
+// @Summary Request overtime
+// @Description Request overtime before it starts. After approval the time actually clocked inside the
+// @Description window is reconciled on clock out and converted to compensable hours with the tenant multipliers.
+// @Tags overtime
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateOvertimeRequest true "Overtime window"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /overtime/requests [post]
+func CreateOvertimeRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	request := &models.OvertimeRequest{
+		TenantID:   tenantID,
+		EmployeeID: employeeID,
+		UserID:     userID,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Reason:     req.Reason,
+	}
+	err := getOvertimeService().SubmitOvertime(request, time.Now())
+	if err == utils.ErrInvalidOvertimeWindow || err == utils.ErrOvertimeNotAhead {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == service.ErrOvertimeOverlap {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to submit overtime request")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Overtime request submitted successfully", request)
+}
+
+// CancelOvertimeRequest godoc
+// @Summary Cancel overtime request
+// @Description Withdraw one of your own pending overtime requests
+// @Tags overtime
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Overtime Request ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /overtime/requests/{id}/cancel [post]
+func CancelOvertimeRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid overtime request ID")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	err = getOvertimeService().CancelOvertimeRequest(tenantID, employeeID, requestID)
+	if err == repository.ErrOvertimeNotFound {
+		utils.GinErrorResponse(c, 404, "Overtime request not found")
+		return
+	}
+	if err == repository.ErrOvertimeNotPending {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to cancel overtime request")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Overtime request cancelled successfully", nil)
+}
+
+// GetPendingOvertimeRequests godoc
+// @Summary Get pending overtime requests
+// @Description Get overtime requests waiting for a decision. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags overtime
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /overtime/approvals [get]
+func GetPendingOvertimeRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	requests, err := getOvertimeService().GetPendingOvertimeRequests(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending overtime requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending overtime requests retrieved successfully", requests)
+}
+
+// ApproveOvertimeRequest godoc
+// @Summary Approve overtime request
+// @Description Approve a pending overtime request of a direct report. The day type (weekday, weekend,
+// @Description holiday) is taken from the tenant calendar and decides the compensation multiplier.
+// @Tags overtime
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Overtime Request ID"
+// @Param request body models.OvertimeDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /overtime/requests/{id}/approve [post]
+func ApproveOvertimeRequest(c *gin.Context) {
+	decideOvertimeRequest(c, true)
+}
+
+// RejectOvertimeRequest godoc
+// @Summary Reject overtime request
+// @Description Reject a pending overtime request of a direct report. A reason is required.
+// @Tags overtime
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Overtime Request ID"
+// @Param request body models.OvertimeDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /overtime/requests/{id}/reject [post]
+func RejectOvertimeRequest(c *gin.Context) {
+	decideOvertimeRequest(c, false)
+}
+
+func decideOvertimeRequest(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid overtime request ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.OvertimeDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getOvertimeService().DecideOvertime(tenantID, approver, requestID, approve, req.Reason)
+	if err != nil {
+		code := overtimeDecisionErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to process overtime decision"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	result := gin.H{"overtime_request_id": requestID, "status": status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "Overtime request approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "Overtime request rejected successfully", result)
+}
+
+// GetOvertimeReport godoc
+// @Summary Get overtime compensation report
+// @Description Sum approved overtime per employee with the minutes actually worked per day type and the
+// @Description compensable hours. Admin/HR see the whole tenant, others themselves and their direct reports.
+// @Tags overtime
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the first day of this month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to the last day of this month"
+// @Param employee_id query string false "Filter by employee ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Router /overtime/report [get]
+func GetOvertimeReport(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	// Bulan berjalan mengikuti timezone tenant
+	now := time.Now().In(getTimezoneService().TenantLocation(tenantID))
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 1, -1)
+
+	if v := c.Query("start_date"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid start_date, use YYYY-MM-DD")
+			return
+		}
+		start = date
+	}
+	if v := c.Query("end_date"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid end_date, use YYYY-MM-DD")
+			return
+		}
+		end = date
+	}
+
+	var employeeID *uuid.UUID
+	if v := c.Query("employee_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid employee ID")
+			return
+		}
+		employeeID = &id
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	report, err := getOvertimeService().GetOvertimeReport(tenantID, approver, employeeID, start, end)
+	if err == service.ErrInvalidReportPeriod {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == service.ErrOvertimeReportForbidden {
+		utils.GinErrorResponse(c, 403, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to generate overtime report")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Overtime report retrieved successfully", report)
+}
+
+// GetOvertimePolicy godoc
+// @Summary Get overtime policy
+// @Description Get the tenant multipliers for weekday, weekend and holiday overtime (admin/HR only)
+// @Tags overtime
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/overtime [get]
+func GetOvertimePolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	policy, err := getOvertimeService().GetPolicy(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch overtime policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Overtime policy retrieved successfully", policy)
+}
+
+// UpdateOvertimePolicy godoc
+// @Summary Update overtime policy
+// @Description Set the multipliers that convert approved overtime minutes into compensable hours.
+// @Description Requests reconciled earlier keep the multiplier they were reconciled with (admin/HR only).
+// @Tags overtime
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.OvertimePolicy true "Overtime policy"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/overtime [put]
+func UpdateOvertimePolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.OvertimePolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err := getOvertimeService().UpdatePolicy(tenantID, req)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update overtime policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Overtime policy updated successfully", req)
+}
+
+func overtimeDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfOvertimeApproval:
+		return 403
+	case repository.ErrOvertimeNotFound:
+		return 404
+	case repository.ErrOvertimeNotPending:
+		return 409
+	default:
+		return 500
+	}
+}
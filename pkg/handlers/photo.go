@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/storage"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	photoStorage     storage.Storage
+	photoStorageErr  error
+	photoURLExpiry   time.Duration
+	photoStorageOnce sync.Once
+)
+
+// getPhotoStorage returns lazily initialized blob storage for attendance photos
+func getPhotoStorage() (storage.Storage, error) {
+	photoStorageOnce.Do(func() {
+		cfg := config.Load()
+		photoStorage, photoStorageErr = storage.New(cfg)
+		photoURLExpiry = time.Duration(cfg.StorageURLExpiryMinutes) * time.Minute
+		if photoURLExpiry <= 0 {
+			photoURLExpiry = 15 * time.Minute
+		}
+		if photoStorageErr != nil && config.IsDevelopment() {
+			fmt.Printf("🔴 Photo storage init error: %v\n", photoStorageErr)
+		}
+	})
+	return photoStorage, photoStorageErr
+}
+
+// attendanceSelfie is a decoded clock in/out selfie with its fingerprint for reuse detection
+type attendanceSelfie struct {
+	Data        []byte
+	ContentType string
+	Hash        *int64     // perceptual hash, nil when the image could not be hashed
+	TakenAt     *time.Time // EXIF capture time, nil when the photo has none
+}
+
+// decodeAttendanceSelfie decodes a base64 selfie and fingerprints it. EXIF times without an
+// offset are read in loc, the timezone of the clock event. An empty photo returns nil.
+func decodeAttendanceSelfie(photo string, loc *time.Location) (*attendanceSelfie, error) {
+	if photo == "" {
+		return nil, nil
+	}
+
+	data, contentType, err := utils.DecodeBase64Image(photo)
+	if err != nil {
+		return nil, err
+	}
+
+	selfie := &attendanceSelfie{Data: data, ContentType: contentType}
+	if hash, err := utils.PerceptualHash(data); err == nil {
+		// Disimpan sebagai BIGINT, bit pattern uint64 tetap utuh
+		signed := int64(hash)
+		selfie.Hash = &signed
+	}
+	if takenAt, ok := utils.PhotoCaptureTime(data, loc); ok {
+		selfie.TakenAt = &takenAt
+	}
+	return selfie, nil
+}
+
+// storeAttendancePhoto uploads a decoded selfie and returns the object key.
+// A nil selfie returns an empty key.
+func storeAttendancePhoto(ctx context.Context, tenantID, userID uuid.UUID, kind string, selfie *attendanceSelfie, takenAt time.Time) (string, error) {
+	if selfie == nil {
+		return "", nil
+	}
+
+	store, err := getPhotoStorage()
+	if err != nil {
+		return "", err
+	}
+
+	key := storage.AttendancePhotoKey(tenantID, userID, kind, takenAt, utils.ImageExtension(selfie.ContentType))
+	if err := store.Put(ctx, key, selfie.Data, selfie.ContentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// selfieHash and selfieTakenAt return the fingerprint columns of a selfie, NULL without a photo
+func selfieHash(selfie *attendanceSelfie) *int64 {
+	if selfie == nil {
+		return nil
+	}
+	return selfie.Hash
+}
+
+func selfieTakenAt(selfie *attendanceSelfie) *time.Time {
+	if selfie == nil || selfie.TakenAt == nil {
+		return nil
+	}
+	// Kolom TIMESTAMP tanpa timezone ditulis dalam waktu server, sama seperti check_in_time
+	local := selfie.TakenAt.Local()
+	return &local
+}
+
+// photoMatchID returns the attendance whose selfie matched, nil when the selfie is new
+func photoMatchID(match *models.PhotoMatch) *uuid.UUID {
+	if match == nil {
+		return nil
+	}
+	return &match.AttendanceID
+}
+
+// deleteAttendancePhoto removes an uploaded photo, used when the attendance write fails
+func deleteAttendancePhoto(key string) {
+	if key == "" {
+		return
+	}
+	if store, err := getPhotoStorage(); err == nil {
+		if err := store.Delete(context.Background(), key); err != nil && config.IsDevelopment() {
+			fmt.Printf("⚠️ Failed to delete orphan photo %s: %v\n", key, err)
+		}
+	}
+}
+
+// attendancePhotoURL returns a short-lived signed URL for a stored photo key.
+// Legacy inline base64 values are not returned to keep list responses small.
+func attendancePhotoURL(value string) string {
+	if !storage.IsAttendancePhotoKey(value) {
+		return ""
+	}
+	return signedFileURL(value)
+}
+
+// storeLeaveAttachment decodes a base64 document (e.g. a sick note), uploads it and
+// returns the object key. An empty attachment returns an empty key.
+func storeLeaveAttachment(ctx context.Context, tenantID, employeeID uuid.UUID, attachment string, uploadedAt time.Time) (string, error) {
+	if attachment == "" {
+		return "", nil
+	}
+
+	data, contentType, err := utils.DecodeBase64Attachment(attachment)
+	if err != nil {
+		return "", err
+	}
+
+	store, err := getPhotoStorage()
+	if err != nil {
+		return "", err
+	}
+
+	key := storage.LeaveAttachmentKey(tenantID, employeeID, uploadedAt, utils.AttachmentExtension(contentType))
+	if err := store.Put(ctx, key, data, contentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// storeVisitPhoto decodes a base64 client visit photo, uploads it and returns the object key.
+// An empty photo returns an empty key.
+func storeVisitPhoto(ctx context.Context, tenantID, employeeID uuid.UUID, kind, photo string, takenAt time.Time) (string, error) {
+	if photo == "" {
+		return "", nil
+	}
+
+	data, contentType, err := utils.DecodeBase64Image(photo)
+	if err != nil {
+		return "", err
+	}
+
+	store, err := getPhotoStorage()
+	if err != nil {
+		return "", err
+	}
+
+	key := storage.ClientVisitPhotoKey(tenantID, employeeID, kind, takenAt, utils.ImageExtension(contentType))
+	if err := store.Put(ctx, key, data, contentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// signedFileURL returns a short-lived signed URL for any stored object key
+func signedFileURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	store, err := getPhotoStorage()
+	if err != nil {
+		return ""
+	}
+	signedURL, err := store.SignedURL(key, photoURLExpiry)
+	if err != nil {
+		return ""
+	}
+	return signedURL
+}
+
+// photoErrorResponse writes the error response for a failed photo or attachment upload
+func photoErrorResponse(c *gin.Context, err error) {
+	clockErr := photoError(err)
+	utils.GinErrorResponse(c, clockErr.Status, clockErr.Message)
+}
+
+// photoError maps a failed photo or attachment upload to its HTTP status and message
+func photoError(err error) *clockError {
+	if err == utils.ErrInvalidImage || err == utils.ErrImageTooLarge ||
+		err == utils.ErrInvalidAttachment || err == utils.ErrAttachmentTooLarge {
+		return &clockError{http.StatusBadRequest, err.Error()}
+	}
+	if config.IsDevelopment() {
+		fmt.Printf("🔴 Photo upload error: %v\n", err)
+	}
+	return &clockError{http.StatusInternalServerError, "Failed to store photo"}
+}
+
+// ServeFile godoc
+// @Summary Download a stored file
+// @Description Serve a file from local storage using a signed URL returned by the API (e.g. photo_url). No bearer token required.
+// @Tags files
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce application/pdf
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /files/{key} [get]
+func ServeFile(c *gin.Context) {
+	store, err := getPhotoStorage()
+	if err != nil {
+		utils.GinErrorResponse(c, http.StatusNotFound, "File not found")
+		return
+	}
+
+	// Storage S3 memakai presigned URL langsung ke bucket
+	local, ok := store.(*storage.LocalStorage)
+	if !ok {
+		utils.GinErrorResponse(c, http.StatusNotFound, "File not found")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		utils.GinErrorResponse(c, http.StatusForbidden, "Invalid or expired file URL")
+		return
+	}
+
+	path, err := local.Path(key)
+	if err != nil {
+		utils.GinErrorResponse(c, http.StatusNotFound, "File not found")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.File(path)
+}
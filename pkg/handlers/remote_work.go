@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	remoteWorkRepo    repository.RemoteWorkRepository
+	remoteWorkService service.RemoteWorkService
+	remoteWorkOnce    sync.Once
+)
+
+// getRemoteWorkService returns lazily initialized remote work (WFH) service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getRemoteWorkService() service.RemoteWorkService {
+	remoteWorkOnce.Do(func() {
+		remoteWorkRepo = repository.NewRemoteWorkRepository(database.GetDB())
+		remoteWorkService = service.NewRemoteWorkService(remoteWorkRepo, repository.NewTenantSettingsRepository(database.GetDB()))
+	})
+	return remoteWorkService
+}
+
+// GetHomeLocation godoc
+// @Summary Get my home location
+// @Description Get the registered home location used as geofence on approved WFH days
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /wfh/home-location [get]
+func GetHomeLocation(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	home, err := getRemoteWorkService().GetHomeLocation(tenantID, employeeID)
+	if err == repository.ErrHomeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Home location not registered")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch home location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Home location retrieved successfully", home)
+}
+
+// SaveHomeLocation godoc
+// @Summary Register my home location
+// @Description Register or move the home location used as geofence on approved WFH days
+// @Tags wfh
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.HomeLocationRequest true "Home location"
+// @Success 200 {object} utils.GinResponse
+// @Router /wfh/home-location [put]
+func SaveHomeLocation(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.HomeLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	home := &models.HomeLocation{
+		TenantID:   tenantID,
+		EmployeeID: employeeID,
+		Name:       req.Name,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+		Radius:     req.Radius,
+	}
+	if err := getRemoteWorkService().SaveHomeLocation(home); err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to save home location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Home location saved successfully", home)
+}
+
+// GetHomeLocations godoc
+// @Summary Get employee home locations
+// @Description Get the registered home locations of all employees (admin/HR only)
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /wfh/home-locations [get]
+func GetHomeLocations(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	homes, err := getRemoteWorkService().GetHomeLocations(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch home locations")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Home locations retrieved successfully", homes)
+}
+
+// DeleteHomeLocation godoc
+// @Summary Delete employee home location
+// @Description Remove the registered home location of an employee, e.g. after moving house (admin/HR only)
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Param employeeId path string true "Employee ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Router /wfh/home-locations/{employeeId} [delete]
+func DeleteHomeLocation(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, err := uuid.Parse(c.Param("employeeId"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid employee ID")
+		return
+	}
+
+	err = getRemoteWorkService().DeleteHomeLocation(tenantID, employeeID)
+	if err == repository.ErrHomeLocationNotFound {
+		utils.GinErrorResponse(c, 404, "Home location not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete home location")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Home location deleted successfully", nil)
+}
+
+// GetWFHRequests godoc
+// @Summary Get my WFH requests
+// @Description Get the WFH requests of the logged-in employee
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /wfh/requests [get]
+func GetWFHRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	requests, err := getRemoteWorkService().GetWFHRequests(tenantID, employeeID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch WFH requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "WFH requests retrieved successfully", requests)
+}
+
+// CreateWFHRequest godoc
+// @Summary Request WFH
+// @Description Request to work from home for today or upcoming days (max 31 days). On approved days
+// @Description clock in/out is validated against the home location instead of the office.
+// @Tags wfh
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateWFHRequest true "WFH period"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /wfh/requests [post]
+func CreateWFHRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateWFHRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	request := &models.WFHRequest{
+		TenantID:   tenantID,
+		EmployeeID: employeeID,
+		UserID:     userID,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Reason:     req.Reason,
+	}
+	if err := getRemoteWorkService().SubmitWFHRequest(request); err != nil {
+		code := wfhSubmitErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to submit WFH request"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "WFH request submitted successfully", request)
+}
+
+// CancelWFHRequest godoc
+// @Summary Cancel WFH request
+// @Description Withdraw one of your own pending WFH requests
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "WFH Request ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /wfh/requests/{id}/cancel [post]
+func CancelWFHRequest(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid WFH request ID")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	err = getRemoteWorkService().CancelWFHRequest(tenantID, employeeID, requestID)
+	if err == repository.ErrWFHRequestNotFound {
+		utils.GinErrorResponse(c, 404, "WFH request not found")
+		return
+	}
+	if err == repository.ErrWFHRequestNotPending {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to cancel WFH request")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "WFH request cancelled successfully", nil)
+}
+
+// GetPendingWFHRequests godoc
+// @Summary Get pending WFH approvals
+// @Description Get WFH requests waiting for a decision. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /wfh/approvals [get]
+func GetPendingWFHRequests(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	requests, err := getRemoteWorkService().GetPendingWFHRequests(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending WFH requests")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending WFH requests retrieved successfully", requests)
+}
+
+// ApproveWFHRequest godoc
+// @Summary Approve WFH request
+// @Description Approve a pending WFH request of a direct report
+// @Tags wfh
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "WFH Request ID"
+// @Param request body models.WFHDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /wfh/requests/{id}/approve [post]
+func ApproveWFHRequest(c *gin.Context) {
+	decideWFHRequest(c, true)
+}
+
+// RejectWFHRequest godoc
+// @Summary Reject WFH request
+// @Description Reject a pending WFH request of a direct report. A reason is required.
+// @Tags wfh
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "WFH Request ID"
+// @Param request body models.WFHDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /wfh/requests/{id}/reject [post]
+func RejectWFHRequest(c *gin.Context) {
+	decideWFHRequest(c, false)
+}
+
+func decideWFHRequest(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid WFH request ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.WFHDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getRemoteWorkService().DecideWFHRequest(tenantID, approver, requestID, approve, req.Reason)
+	if err != nil {
+		code := wfhDecisionErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to process WFH decision"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	result := gin.H{"wfh_request_id": requestID, "status": status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "WFH request approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "WFH request rejected successfully", result)
+}
+
+// GetRemoteWorkPolicy godoc
+// @Summary Get remote work policy
+// @Description Get how WFH attendances are validated (admin/HR only)
+// @Tags wfh
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/remote-work [get]
+func GetRemoteWorkPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	policy, err := getRemoteWorkService().GetPolicy(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch remote work policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Remote work policy retrieved successfully", policy)
+}
+
+// UpdateRemoteWorkPolicy godoc
+// @Summary Update remote work policy
+// @Description Choose whether WFH clock ins require a registered home location or skip the geofence
+// @Description for employees without one (admin/HR only)
+// @Tags wfh
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RemoteWorkPolicy true "Remote work policy"
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/settings/remote-work [put]
+func UpdateRemoteWorkPolicy(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.RemoteWorkPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err := getRemoteWorkService().UpdatePolicy(tenantID, req)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update remote work policy")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Remote work policy updated successfully", req)
+}
+
+func wfhSubmitErrorCode(err error) int {
+	switch err {
+	case service.ErrInvalidWFHPeriod, service.ErrWFHInPast:
+		return 400
+	case service.ErrWFHOverlap:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func wfhDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfWFHApproval:
+		return 403
+	case repository.ErrWFHRequestNotFound:
+		return 404
+	case repository.ErrWFHRequestNotPending:
+		return 409
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	rosterRepo    repository.RosterRepository
+	rosterService service.RosterService
+	rosterOnce    sync.Once
+)
+
+// getRosterService returns lazily initialized roster service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getRosterService() service.RosterService {
+	rosterOnce.Do(func() {
+		rosterRepo = repository.NewRosterRepository(database.GetDB())
+		rosterService = service.NewRosterService(rosterRepo, repository.NewTenantSettingsRepository(database.GetDB()))
+	})
+	return rosterService
+}
+
+// GetRoster godoc
+// @Summary Get shift roster
+// @Description Get rostered shifts of a period (default: the current Monday-Sunday week). Employees see their own roster,
+// @Description admin/HR see the whole tenant or one employee via employee_id.
+// @Tags rosters
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param employee_id query string false "Employee ID (admin/HR only)"
+// @Success 200 {object} utils.GinResponse
+// @Router /rosters [get]
+func GetRoster(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	// Minggu berjalan mengikuti timezone tenant
+	now := time.Now().In(getTimezoneService().TenantLocation(tenantID))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	end := start.AddDate(0, 0, 6)
+
+	var err error
+	if value := c.Query("start_date"); value != "" {
+		if start, err = time.ParseInLocation("2006-01-02", value, now.Location()); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid start_date, use YYYY-MM-DD")
+			return
+		}
+		end = start.AddDate(0, 0, 6)
+	}
+	if value := c.Query("end_date"); value != "" {
+		if end, err = time.ParseInLocation("2006-01-02", value, now.Location()); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid end_date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	var employeeID *uuid.UUID
+	if hasAnyRole(c, attendanceAdminRoles...) {
+		if value := c.Query("employee_id"); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				utils.GinErrorResponse(c, 400, "Invalid employee ID")
+				return
+			}
+			employeeID = &parsed
+		}
+	} else {
+		ownID, ok := requireEmployeeID(c, tenantID, userID)
+		if !ok {
+			return
+		}
+		employeeID = &ownID
+	}
+
+	roster, err := getRosterService().GetRoster(tenantID, start, end, employeeID)
+	if err == service.ErrInvalidRosterPeriod {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch roster")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Roster retrieved successfully", gin.H{
+		"start_date": start.Format("2006-01-02"),
+		"end_date":   end.Format("2006-01-02"),
+		"entries":    roster,
+	})
+}
+
+// SaveRoster godoc
+// @Summary Save shift roster
+// @Description Set the shift of employees per day, e.g. a full week at once (admin/HR only). Any work schedule can be used
+// @Description as shift, including night shifts whose end_time is before start_time. schedule_id null marks a day off.
+// @Tags rosters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RosterRequest true "Roster entries"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /rosters [put]
+func SaveRoster(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.RosterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	saved, err := getRosterService().SaveRoster(tenantID, userID, req.Entries)
+	if err == service.ErrInvalidRosterDate || err == repository.ErrRosterInvalidReference {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to save roster")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Roster saved successfully", gin.H{"saved": saved})
+}
+
+// DeleteRosterEntry godoc
+// @Summary Delete roster entry
+// @Description Remove a rostered shift or day off (admin/HR only). The employee falls back to their assigned schedule on that day.
+// @Tags rosters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Roster Entry ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /rosters/{id} [delete]
+func DeleteRosterEntry(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	rosterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid roster entry ID")
+		return
+	}
+
+	err = getRosterService().DeleteRosterEntry(tenantID, rosterID)
+	if err == repository.ErrRosterEntryNotFound {
+		utils.GinErrorResponse(c, 404, "Roster entry not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete roster entry")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Roster entry deleted successfully", nil)
+}
+
+// GetShiftSwaps godoc
+// @Summary Get my shift swaps
+// @Description Get the shift swaps the logged-in employee requested or is the target of
+// @Tags rosters
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /shift-swaps [get]
+func GetShiftSwaps(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	swaps, err := getRosterService().GetShiftSwaps(tenantID, employeeID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch shift swaps")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Shift swaps retrieved successfully", swaps)
+}
+
+// CreateShiftSwap godoc
+// @Summary Request shift swap
+// @Description Ask to exchange your rostered shift with another employee. With a different target_date both employees
+// @Description also exchange their roster on that day. The swap is applied once your supervisor approves it.
+// @Tags rosters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateShiftSwapRequest true "Shift swap request"
+// @Success 201 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Router /shift-swaps [post]
+func CreateShiftSwap(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateShiftSwapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	swap := &models.ShiftSwapRequest{
+		TenantID:         tenantID,
+		RequesterID:      employeeID,
+		RequesterDate:    req.RequesterDate,
+		TargetEmployeeID: req.TargetEmployeeID,
+		TargetDate:       req.TargetDate,
+		Reason:           req.Reason,
+	}
+
+	err := getRosterService().RequestShiftSwap(swap)
+	if err == service.ErrInvalidRosterDate || err == service.ErrShiftSwapWithSelf || err == service.ErrShiftSwapInPast ||
+		err == service.ErrNoShiftToSwap {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == service.ErrShiftSwapTargetMissing {
+		utils.GinErrorResponse(c, 404, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to request shift swap")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Shift swap requested successfully", swap)
+}
+
+// CancelShiftSwap godoc
+// @Summary Cancel shift swap
+// @Description Withdraw one of your own pending shift swap requests
+// @Tags rosters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift Swap ID"
+// @Success 200 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /shift-swaps/{id}/cancel [post]
+func CancelShiftSwap(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	swapID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid shift swap ID")
+		return
+	}
+
+	employeeID, ok := requireEmployeeID(c, tenantID, userID)
+	if !ok {
+		return
+	}
+
+	err = getRosterService().CancelShiftSwap(tenantID, employeeID, swapID)
+	if err == repository.ErrShiftSwapNotFound {
+		utils.GinErrorResponse(c, 404, "Shift swap not found")
+		return
+	}
+	if err == repository.ErrShiftSwapNotPending {
+		utils.GinErrorResponse(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to cancel shift swap")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Shift swap cancelled successfully", nil)
+}
+
+// GetPendingShiftSwaps godoc
+// @Summary Get pending shift swap approvals
+// @Description Get shift swaps waiting for a decision. Supervisors see swaps requested by their direct reports, admin/HR see the whole tenant.
+// @Tags rosters
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /shift-swaps/approvals [get]
+func GetPendingShiftSwaps(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	swaps, err := getRosterService().GetPendingShiftSwaps(tenantID, approver)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch pending shift swaps")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Pending shift swaps retrieved successfully", swaps)
+}
+
+// ApproveShiftSwap godoc
+// @Summary Approve shift swap
+// @Description Approve a pending shift swap of a direct report and apply it to the roster
+// @Tags rosters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift Swap ID"
+// @Param request body models.ShiftSwapDecisionRequest false "Optional note"
+// @Success 200 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /shift-swaps/{id}/approve [post]
+func ApproveShiftSwap(c *gin.Context) {
+	decideShiftSwap(c, true)
+}
+
+// RejectShiftSwap godoc
+// @Summary Reject shift swap
+// @Description Reject a pending shift swap of a direct report. A reason is required.
+// @Tags rosters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift Swap ID"
+// @Param request body models.ShiftSwapDecisionRequest true "Rejection reason"
+// @Success 200 {object} utils.GinResponse
+// @Failure 400 {object} utils.GinResponse
+// @Failure 403 {object} utils.GinResponse
+// @Failure 404 {object} utils.GinResponse
+// @Failure 409 {object} utils.GinResponse
+// @Router /shift-swaps/{id}/reject [post]
+func RejectShiftSwap(c *gin.Context) {
+	decideShiftSwap(c, false)
+}
+
+func decideShiftSwap(c *gin.Context, approve bool) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	swapID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid shift swap ID")
+		return
+	}
+
+	// Body boleh kosong saat approve
+	var req models.ShiftSwapDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.GinErrorResponse(c, 400, "Invalid request data")
+			return
+		}
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	status, err := getRosterService().DecideShiftSwap(tenantID, approver, swapID, approve, req.Reason)
+	if err != nil {
+		code := shiftSwapDecisionErrorCode(err)
+		message := err.Error()
+		if code == 500 {
+			message = "Failed to process shift swap decision"
+		}
+		utils.GinErrorResponse(c, code, message)
+		return
+	}
+
+	result := gin.H{"shift_swap_id": swapID, "status": status}
+	if approve {
+		utils.GinSuccessResponse(c, 200, "Shift swap approved successfully", result)
+		return
+	}
+	utils.GinSuccessResponse(c, 200, "Shift swap rejected successfully", result)
+}
+
+func shiftSwapDecisionErrorCode(err error) int {
+	switch err {
+	case service.ErrRejectionReasonRequired, service.ErrShiftSwapInPast:
+		return 400
+	case service.ErrNotSupervisor, service.ErrSelfShiftSwapApproval:
+		return 403
+	case repository.ErrShiftSwapNotFound:
+		return 404
+	case repository.ErrShiftSwapNotPending:
+		return 409
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	scheduleRepo    repository.ScheduleRepository
+	scheduleService service.ScheduleService
+	scheduleOnce    sync.Once
+)
+
+// getScheduleService returns lazily initialized schedule service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getScheduleService() service.ScheduleService {
+	scheduleOnce.Do(func() {
+		db := database.GetDB()
+		scheduleRepo = repository.NewScheduleRepository(db)
+		scheduleService = service.NewScheduleService(scheduleRepo, repository.NewRosterRepository(db), repository.NewCalendarRepository(db))
+	})
+	return scheduleService
+}
+
+// GetSchedules godoc
+// @Summary Get work schedules
+// @Description Get all attendance work schedules for the current tenant
+// @Tags schedules
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /schedules [get]
+func GetSchedules(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := getScheduleService().GetSchedules(tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch schedules")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Schedules retrieved successfully", schedules)
+}
+
+// GetSchedule godoc
+// @Summary Get work schedule by ID
+// @Description Get a specific work schedule by ID
+// @Tags schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /schedules/{id} [get]
+func GetSchedule(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid schedule ID")
+		return
+	}
+
+	schedule, err := getScheduleService().GetScheduleByID(tenantID, scheduleID)
+	if err == repository.ErrScheduleNotFound {
+		utils.GinErrorResponse(c, 404, "Schedule not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch schedule")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Schedule retrieved successfully", schedule)
+}
+
+// CreateSchedule godoc
+// @Summary Create work schedule
+// @Description Create a work schedule (admin/HR only). working_days is a weekday bitmask (bit 0 = Sunday), 0 means Monday-Friday.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AttendanceScheduleRequest true "Schedule data"
+// @Success 201 {object} utils.GinResponse
+// @Router /schedules [post]
+func CreateSchedule(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.AttendanceScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	schedule := &models.AttendanceSchedule{
+		TenantID:       tenantID,
+		Name:           req.Name,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		WorkingDays:    req.WorkingDays,
+		ToleranceLate:  req.ToleranceLate,
+		ToleranceEarly: req.ToleranceEarly,
+		IsDefault:      req.IsDefault,
+		IsActive:       true,
+	}
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+
+	err := getScheduleService().CreateSchedule(schedule)
+	if err == service.ErrInvalidSchedule {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to create schedule")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 201, "Schedule created successfully", schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update work schedule
+// @Description Update an existing work schedule (admin/HR only). Past attendances keep their computed minutes.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Param request body models.AttendanceScheduleRequest true "Schedule data"
+// @Success 200 {object} utils.GinResponse
+// @Router /schedules/{id} [put]
+func UpdateSchedule(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid schedule ID")
+		return
+	}
+
+	var req models.AttendanceScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	schedule, err := getScheduleService().GetScheduleByID(tenantID, scheduleID)
+	if err == repository.ErrScheduleNotFound {
+		utils.GinErrorResponse(c, 404, "Schedule not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch schedule")
+		return
+	}
+
+	schedule.Name = req.Name
+	schedule.StartTime = req.StartTime
+	schedule.EndTime = req.EndTime
+	schedule.WorkingDays = req.WorkingDays
+	schedule.ToleranceLate = req.ToleranceLate
+	schedule.ToleranceEarly = req.ToleranceEarly
+	schedule.IsDefault = req.IsDefault
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+
+	err = getScheduleService().UpdateSchedule(schedule)
+	if err == service.ErrInvalidSchedule {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == repository.ErrScheduleNotFound {
+		utils.GinErrorResponse(c, 404, "Schedule not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update schedule")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Schedule updated successfully", schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete work schedule
+// @Description Delete a work schedule (admin/HR only). Assigned employees fall back to the tenant default schedule.
+// @Tags schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} utils.GinResponse
+// @Router /schedules/{id} [delete]
+func DeleteSchedule(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid schedule ID")
+		return
+	}
+
+	err = getScheduleService().DeleteSchedule(tenantID, scheduleID)
+	if err == repository.ErrScheduleNotFound {
+		utils.GinErrorResponse(c, 404, "Schedule not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to delete schedule")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Schedule deleted successfully", nil)
+}
+
+// AssignSchedule godoc
+// @Summary Assign work schedule to employees
+// @Description Assign a work schedule to one or more employees (admin/HR only)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Param request body models.AssignScheduleRequest true "Employee IDs"
+// @Success 200 {object} utils.GinResponse
+// @Router /schedules/{id}/assign [post]
+func AssignSchedule(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid schedule ID")
+		return
+	}
+
+	var req models.AssignScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	assigned, err := getScheduleService().AssignSchedule(tenantID, scheduleID, req.EmployeeIDs)
+	if err == repository.ErrScheduleNotFound {
+		utils.GinErrorResponse(c, 404, "Schedule not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to assign schedule")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Schedule assigned successfully", gin.H{
+		"schedule_id": scheduleID,
+		"assigned":    assigned,
+	})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+const (
+	// teamPresenceRefresh is how often the stream rebuilds the board
+	teamPresenceRefresh = 15 * time.Second
+	// teamPresenceStreamMax ends the stream before the serverless function timeout,
+	// clients reconnect after the retry delay (EventSource with a new stream token)
+	teamPresenceStreamMax = 5 * time.Minute
+	teamPresenceRetryMs   = 3000
+)
+
+var (
+	teamPresenceService service.TeamPresenceService
+	teamPresenceOnce    sync.Once
+)
+
+// getTeamPresenceService returns lazily initialized team presence service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getTeamPresenceService() service.TeamPresenceService {
+	teamPresenceOnce.Do(func() {
+		teamPresenceService = service.NewTeamPresenceService(
+			repository.NewTeamPresenceRepository(database.GetDB()),
+			repository.NewTenantSettingsRepository(database.GetDB()),
+		)
+	})
+	return teamPresenceService
+}
+
+// GetTeamPresence godoc
+// @Summary Get team presence board
+// @Description Get who on your team is clocked in, on break, late, on leave or not arrived yet today, with
+// @Description their last check-in office. Supervisors see their direct reports, admin/HR see the whole tenant.
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/team-presence [get]
+func GetTeamPresence(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	board, err := getTeamPresenceService().GetTeamBoard(tenantID, approver, time.Now())
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch team presence")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Team presence retrieved successfully", board)
+}
+
+// CreateTeamPresenceStreamToken godoc
+// @Summary Create team presence stream token
+// @Description Create a short-lived token for the team presence stream. Browser EventSource cannot send the
+// @Description Authorization header, so open the stream with ?token=<token>. The token is valid for 1 minute
+// @Description and only for the stream; request a new one before reconnecting after the stream closes or errors.
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /attendance/team-presence/stream-token [post]
+func CreateTeamPresenceStreamToken(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	token, err := jwtUtil.GenerateStreamToken(userID, c.GetString("role"), tenantID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to create stream token")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Stream token created successfully", gin.H{
+		"token":      token,
+		"expires_in": int(utils.StreamTokenTTL.Seconds()),
+	})
+}
+
+// StreamTeamPresence godoc
+// @Summary Stream team presence board
+// @Description Server-sent events stream of the team presence board. A "presence" event with the full board
+// @Description is sent on connect and whenever the board changes (checked every 15 seconds), with keep-alive
+// @Description comments in between. The stream closes after 5 minutes and the client reconnects.
+// @Description Authenticate with "Authorization: Bearer <access token>" (fetch streaming) or, from EventSource,
+// @Description with ?token= from POST /attendance/team-presence/stream-token.
+// @Tags attendance
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param token query string false "Stream token for EventSource clients"
+// @Success 200 {string} string "event: presence"
+// @Router /attendance/team-presence/stream [get]
+func StreamTeamPresence(c *gin.Context) {
+	tenantID, userID, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	approver, err := getAttendanceApprover(c, tenantID, userID)
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to resolve approver")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+	c.Writer.WriteString("retry: " + strconv.Itoa(teamPresenceRetryMs) + "\n\n")
+
+	// Board dibangun ulang dari database di setiap tick, bukan dari event in-memory,
+	// karena clock in bisa diproses instance server lain
+	var last []byte
+	push := func(w io.Writer) {
+		board, err := getTeamPresenceService().GetTeamBoard(tenantID, approver, time.Now())
+		if err != nil {
+			c.SSEvent("error", `{"message":"Failed to fetch team presence"}`)
+			return
+		}
+		payload, err := json.Marshal(board)
+		if err != nil {
+			return
+		}
+		if bytes.Equal(payload, last) {
+			io.WriteString(w, ": keep-alive\n\n")
+			return
+		}
+		last = payload
+		c.SSEvent("presence", string(payload))
+	}
+
+	push(c.Writer)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(teamPresenceRefresh)
+	defer ticker.Stop()
+	deadline := time.NewTimer(teamPresenceStreamMax)
+	defer deadline.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+			push(w)
+			return true
+		}
+	})
+}
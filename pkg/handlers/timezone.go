@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/database"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/service"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	timezoneService service.TimezoneService
+	timezoneOnce    sync.Once
+)
+
+// getTimezoneService returns lazily initialized timezone service
+// This prevents nil pointer panic when database is not yet connected at package init time
+func getTimezoneService() service.TimezoneService {
+	timezoneOnce.Do(func() {
+		timezoneService = service.NewTimezoneService(repository.NewTenantSettingsRepository(database.GetDB()))
+	})
+	return timezoneService
+}
+
+// attendanceTimezone menentukan timezone clock in dari kantor hasil validasi lokasi (atau timezone
+// tenant), sehingga tanggal attendance dan keterlambatan mengikuti jam lokal kantor, bukan jam server
+func attendanceTimezone(tenantID uuid.UUID, office attendanceOffice) *time.Location {
+	return getTimezoneService().OfficeLocation(tenantID, office.Office)
+}
+
+// GetTenantTimezone godoc
+// @Summary Get tenant timezone
+// @Description Get the IANA timezone used for attendance dates of offices without their own timezone, greetings and dashboards
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.GinResponse
+// @Router /settings/timezone [get]
+func GetTenantTimezone(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	timezone, err := getTimezoneService().GetTenantTimezone(tenantID)
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to fetch tenant timezone")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Tenant timezone retrieved successfully", models.TenantTimezoneRequest{Timezone: timezone})
+}
+
+// UpdateTenantTimezone godoc
+// @Summary Update tenant timezone
+// @Description Set the IANA timezone of the tenant, e.g. Asia/Jakarta, Asia/Makassar or Asia/Jayapura (admin/HR only)
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TenantTimezoneRequest true "Tenant timezone"
+// @Success 200 {object} utils.GinResponse
+// @Router /settings/timezone [put]
+func UpdateTenantTimezone(c *gin.Context) {
+	tenantID, _, ok := getAuthContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.TenantTimezoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.GinErrorResponse(c, 400, "Invalid request data")
+		return
+	}
+
+	err := getTimezoneService().UpdateTenantTimezone(tenantID, req.Timezone)
+	if err == utils.ErrInvalidTimezone {
+		utils.GinErrorResponse(c, 400, err.Error())
+		return
+	}
+	if err == repository.ErrTenantNotFound {
+		utils.GinErrorResponse(c, 404, "Tenant not found")
+		return
+	}
+	if err != nil {
+		utils.GinErrorResponse(c, 500, "Failed to update tenant timezone")
+		return
+	}
+
+	utils.GinSuccessResponse(c, 200, "Tenant timezone updated successfully", req)
+}
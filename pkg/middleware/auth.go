@@ -51,9 +51,34 @@ func GinAuthMiddleware() gin.HandlerFunc {
 		// Set userID in Gin context
 		c.Set("userID", claims.UserID)
 		c.Set("tenant_id", claims.TenantID.String())
+		c.Set("role", claims.Role)
 
 		// Token valid, continue to next handler
 		c.Next()
 	}
 }
 
+// GinStreamAuthMiddleware untuk endpoint server-sent events. Browser EventSource tidak bisa
+// mengirim header Authorization, sehingga selain "Bearer <access token>" diterima juga
+// stream token berumur pendek di query string (?token=...).
+func GinStreamAuthMiddleware() gin.HandlerFunc {
+	auth := GinAuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			auth(c)
+			return
+		}
+
+		claims, err := jwtUtil.ValidateStreamToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid stream token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("tenant_id", claims.TenantID.String())
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
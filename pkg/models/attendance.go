@@ -6,16 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// Attendance status values stored in godplan.attendances.status
+const (
+	AttendanceStatusApproved      = "approved"
+	AttendanceStatusPending       = "pending"
+	AttendanceStatusPendingForced = "pending_forced"
+	AttendanceStatusRejected      = "rejected"
+)
+
 // Attendance represents attendance data aligned with godplan.attendances schema
 type Attendance struct {
-	ID              uuid.UUID  `json:"id"`
-	TenantID        uuid.UUID  `json:"tenant_id"`
-	UserID          uuid.UUID  `json:"user_id"`
-	EmployeeID      *uuid.UUID `json:"employee_id,omitempty"`
-	ScheduleID      *uuid.UUID `json:"schedule_id,omitempty"`
-	AttendanceDate  string     `json:"attendance_date"`
-	Type            string     `json:"type"`   // CheckIn, CheckOut
-	Status          string     `json:"status"` // approved, pending, pending_forced, rejected
+	ID               uuid.UUID  `json:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	EmployeeID       *uuid.UUID `json:"employee_id,omitempty"`
+	ScheduleID       *uuid.UUID `json:"schedule_id,omitempty"`
+	OfficeLocationID *uuid.UUID `json:"office_location_id,omitempty"`
+	AttendanceDate   string     `json:"attendance_date"`
+	Type             string     `json:"type"`   // CheckIn, CheckOut
+	Status           string     `json:"status"` // approved, pending, pending_forced, rejected
 
 	// Check In Data
 	CheckInTime  *time.Time `json:"check_in_time,omitempty"`
@@ -29,9 +38,16 @@ type Attendance struct {
 	CheckOutLng   float64    `json:"check_out_lng,omitempty"`
 	CheckOutPhoto string     `json:"check_out_photo,omitempty"`
 
-	TotalHours      float64 `json:"total_hours,omitempty"`
-	LateMinutes     float64 `json:"late_minutes,omitempty"`
-	OvertimeMinutes float64 `json:"overtime_minutes,omitempty"`
+	CheckOutOfficeLocationID *uuid.UUID `json:"check_out_office_location_id,omitempty"`
+
+	PresenceMethod         string `json:"presence_method,omitempty"` // gps, wifi, ble, qr, remote, force
+	CheckOutPresenceMethod string `json:"check_out_presence_method,omitempty"`
+	WorkMode               string `json:"work_mode,omitempty"` // office, wfh
+
+	TotalHours        float64 `json:"total_hours,omitempty"`
+	LateMinutes       float64 `json:"late_minutes,omitempty"`
+	EarlyLeaveMinutes float64 `json:"early_leave_minutes,omitempty"`
+	OvertimeMinutes   float64 `json:"overtime_minutes,omitempty"`
 
 	InRange         bool   `json:"in_range"`
 	ForceAttendance bool   `json:"force_attendance"`
@@ -45,9 +61,10 @@ type Attendance struct {
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 
 	// Legacy fields for backward compatibility with API responses
-	Latitude    float64 `json:"latitude,omitempty"`
-	Longitude   float64 `json:"longitude,omitempty"`
-	PhotoSelfie string  `json:"photo_selfie,omitempty"`
+	Latitude     float64 `json:"latitude,omitempty"`
+	Longitude    float64 `json:"longitude,omitempty"`
+	PhotoSelfie  string  `json:"photo_selfie,omitempty"`
+	LocationName string  `json:"location_name,omitempty"`
 }
 
 type ClockRequest struct {
@@ -56,3 +73,123 @@ type ClockRequest struct {
 	PhotoSelfie string  `json:"photo_selfie"`
 	Force       bool    `json:"force"`
 }
+
+// PendingAttendance is an attendance awaiting a supervisor decision
+type PendingAttendance struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	EmployeeName    string     `json:"employee_name"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	AttendanceDate  string     `json:"attendance_date"`
+	CheckInTime     *time.Time `json:"check_in_time,omitempty"`
+	CheckOutTime    *time.Time `json:"check_out_time,omitempty"`
+	Latitude        float64    `json:"latitude"`
+	Longitude       float64    `json:"longitude"`
+	InRange         bool       `json:"in_range"`
+	ForceAttendance bool       `json:"force_attendance"`
+	LocationName    string     `json:"location_name"`
+	FraudScore      int        `json:"fraud_score"`
+	FraudFlags      []string   `json:"fraud_flags"`
+	CreatedAt       time.Time  `json:"created_at"`
+
+	// PhotoReuseAttendanceID is the earlier attendance whose selfie matched this one (reused_photo / shared_photo)
+	PhotoReuseAttendanceID *uuid.UUID `json:"photo_reuse_attendance_id,omitempty"`
+}
+
+// AttendanceApprover describes who is deciding on attendances and their scope
+type AttendanceApprover struct {
+	UserID        uuid.UUID
+	EmployeeID    *uuid.UUID // nil when the user has no employee record
+	CanApproveAll bool       // admin/HR may decide on any attendance in the tenant
+}
+
+// AttendanceDecisionRequest is used to approve or reject a single attendance
+type AttendanceDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// BulkAttendanceDecisionRequest is used to approve or reject several attendances at once
+type BulkAttendanceDecisionRequest struct {
+	AttendanceIDs []uuid.UUID `json:"attendance_ids" binding:"required,min=1,max=100"`
+	Action        string      `json:"action" binding:"required,oneof=approve reject"`
+	Reason        string      `json:"reason" binding:"max=500"`
+}
+
+// AttendanceDecisionResult reports the outcome for one attendance in a bulk decision
+type AttendanceDecisionResult struct {
+	AttendanceID uuid.UUID `json:"attendance_id"`
+	Success      bool      `json:"success"`
+	Status       string    `json:"status,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// AttendanceRecapFilter selects the period and scope of an attendance recap
+type AttendanceRecapFilter struct {
+	StartDate    time.Time
+	EndDate      time.Time
+	DepartmentID *uuid.UUID
+}
+
+// AttendanceRecap aggregates one employee's attendance over a period
+type AttendanceRecap struct {
+	EmployeeID           uuid.UUID `json:"employee_id"`
+	UserID               uuid.UUID `json:"user_id"`
+	EmployeeCode         string    `json:"employee_code"`
+	EmployeeName         string    `json:"employee_name"`
+	Department           string    `json:"department"`
+	WorkingDays          int       `json:"working_days"`
+	DaysPresent          int       `json:"days_present"`
+	DaysAbsent           int       `json:"days_absent"`
+	LateCount            int       `json:"late_count"`
+	TotalLateMinutes     float64   `json:"total_late_minutes"`
+	TotalHours           float64   `json:"total_hours"`
+	TotalOvertimeMinutes float64   `json:"total_overtime_minutes"`
+	ForcedCount          int       `json:"forced_count"`
+	PendingCount         int       `json:"pending_count"`
+
+	// Used to derive WorkingDays and DaysAbsent, not part of the report
+	WorkingDaysMask      int        `json:"-"`
+	PresentOnWorkingDays int        `json:"-"`
+	JoinDate             *time.Time `json:"-"`
+}
+
+// ClockEvent is a single clock in or clock out position of a user
+type ClockEvent struct {
+	Latitude  float64
+	Longitude float64
+	Time      time.Time
+}
+
+// FraudAssessment is the GPS spoofing risk evaluation of one clock event
+type FraudAssessment struct {
+	Score      int      `json:"score"`
+	Flags      []string `json:"flags"`
+	Suspicious bool     `json:"suspicious"`
+}
+
+// PhotoMatch is an earlier clock in/out selfie whose perceptual hash is close to a new selfie
+type PhotoMatch struct {
+	AttendanceID uuid.UUID
+	UserID       uuid.UUID
+	Distance     int // Hamming distance between the two perceptual hashes
+}
+
+// AutoClockOutPolicy is the per-tenant cut-off for forgotten clock outs, stored under
+// the "auto_clock_out" key of tenants.settings
+type AutoClockOutPolicy struct {
+	Enabled             bool    `json:"enabled"`
+	GraceHours          float64 `json:"grace_hours" binding:"min=0,max=24" example:"4"`           // hours after the scheduled end before the session is closed
+	DefaultSessionHours float64 `json:"default_session_hours" binding:"min=1,max=24" example:"8"` // planned length of sessions without a schedule
+}
+
+// OpenAttendanceSession is a clocked-in attendance without clock out
+type OpenAttendanceSession struct {
+	ID             uuid.UUID
+	TenantID       uuid.UUID
+	UserID         uuid.UUID
+	CheckInTime    time.Time
+	AttendanceDate string              // YYYY-MM-DD, the shift start date for rostered attendances
+	Schedule       *AttendanceSchedule // nil when the attendance has no schedule
+	Timezone       string              // IANA timezone of AttendanceDate (attendance, else tenant)
+}
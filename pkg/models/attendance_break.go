@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Break types stored in godplan.attendance_breaks.break_type
+const (
+	BreakTypeLunch  = "lunch"
+	BreakTypePrayer = "prayer"
+	BreakTypeOther  = "other"
+)
+
+// AttendanceBreak is a break interval inside an attendance session
+type AttendanceBreak struct {
+	ID              uuid.UUID  `json:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id"`
+	AttendanceID    uuid.UUID  `json:"attendance_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	BreakType       string     `json:"break_type"` // lunch, prayer, other
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationMinutes float64    `json:"duration_minutes"`
+	ExceededMinutes float64    `json:"exceeded_minutes"` // minutes over the tenant break limits
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// StartBreakRequest is the body of a start-break request
+type StartBreakRequest struct {
+	Type string `json:"type" binding:"omitempty,oneof=lunch prayer other" example:"lunch"` // default other
+}
+
+// BreakPolicy is the per-tenant break limit, stored under the "breaks" key of tenants.settings.
+// A limit of 0 means no limit.
+type BreakPolicy struct {
+	MaxBreakMinutes     int `json:"max_break_minutes" binding:"min=0,max=480" example:"60"` // per break
+	MaxTotalMinutes     int `json:"max_total_minutes" binding:"min=0,max=720" example:"90"` // per attendance session
+	MaxBreaksPerSession int `json:"max_breaks_per_session" binding:"min=0,max=20" example:"3"`
+}
+
+// AttendanceBreakSummary is the break state of the open attendance session
+type AttendanceBreakSummary struct {
+	AttendanceID     uuid.UUID         `json:"attendance_id"`
+	OnBreak          bool              `json:"on_break"`
+	Breaks           []AttendanceBreak `json:"breaks"`
+	UsedMinutes      float64           `json:"used_minutes"`
+	RemainingMinutes *float64          `json:"remaining_minutes,omitempty"` // omitted without total limit
+	Policy           BreakPolicy       `json:"policy"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attendance correction status values stored in godplan.attendance_correction_requests.status
+const (
+	CorrectionStatusPending   = "pending"
+	CorrectionStatusApproved  = "approved"
+	CorrectionStatusRejected  = "rejected"
+	CorrectionStatusCancelled = "cancelled"
+)
+
+// Sources of attendance changes stored in godplan.attendance_audit_logs.source
+const (
+	AttendanceChangeCorrection = "correction"
+)
+
+// AttendanceCorrectionRequest is an employee request to fix the clock in/out of an attendance
+type AttendanceCorrectionRequest struct {
+	ID                   uuid.UUID  `json:"id"`
+	TenantID             uuid.UUID  `json:"tenant_id"`
+	AttendanceID         uuid.UUID  `json:"attendance_id"`
+	EmployeeID           uuid.UUID  `json:"employee_id"`
+	UserID               uuid.UUID  `json:"user_id"`
+	EmployeeName         string     `json:"employee_name,omitempty"`
+	AttendanceDate       string     `json:"attendance_date,omitempty"` // YYYY-MM-DD
+	CurrentCheckInTime   *time.Time `json:"current_check_in_time,omitempty"`
+	CurrentCheckOutTime  *time.Time `json:"current_check_out_time,omitempty"`
+	ProposedCheckInTime  *time.Time `json:"proposed_check_in_time,omitempty"`
+	ProposedCheckOutTime *time.Time `json:"proposed_check_out_time,omitempty"`
+	Reason               string     `json:"reason"`
+	Status               string     `json:"status"`
+	ApprovedBy           *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedAt           *time.Time `json:"approved_at,omitempty"`
+	RejectionReason      string     `json:"rejection_reason,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// CreateAttendanceCorrectionRequest is the body of a new correction. At least one of the
+// times is required, an omitted time keeps the recorded value.
+type CreateAttendanceCorrectionRequest struct {
+	CheckInTime  *time.Time `json:"check_in_time" example:"2026-01-05T08:00:00+07:00"`
+	CheckOutTime *time.Time `json:"check_out_time" example:"2026-01-05T17:05:00+07:00"`
+	Reason       string     `json:"reason" binding:"required,max=1000" example:"Lupa Clock Out, pulang jam 17:05"`
+}
+
+// AttendanceCorrectionDecisionRequest is used to approve or reject a correction request
+type AttendanceCorrectionDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// AttendanceSession is the part of an attendance a correction changes
+type AttendanceSession struct {
+	ID             uuid.UUID
+	TenantID       uuid.UUID
+	UserID         uuid.UUID
+	AttendanceDate string
+	CheckInTime    time.Time
+	CheckOutTime   *time.Time
+	TotalHours     float64
+	BreakMinutes   float64
+	AutoClosed     bool
+
+	// Jadwal dan timezone saat Clock In, dipakai menghitung ulang menit terlambat/pulang cepat/lembur
+	RosterID          uuid.NullUUID
+	ScheduleID        uuid.NullUUID
+	Timezone          string
+	LateMinutes       float64
+	EarlyLeaveMinutes float64
+	OvertimeMinutes   float64
+}
+
+// AttendanceAuditLog keeps the original and new values of a changed attendance
+type AttendanceAuditLog struct {
+	ID                  uuid.UUID  `json:"id"`
+	TenantID            uuid.UUID  `json:"tenant_id"`
+	AttendanceID        uuid.UUID  `json:"attendance_id"`
+	CorrectionRequestID *uuid.UUID `json:"correction_request_id,omitempty"`
+	ChangedBy           *uuid.UUID `json:"changed_by,omitempty"`
+	ChangedByName       string     `json:"changed_by_name,omitempty"`
+	Source              string     `json:"source"`
+	OldCheckInTime      *time.Time `json:"old_check_in_time,omitempty"`
+	OldCheckOutTime     *time.Time `json:"old_check_out_time,omitempty"`
+	OldTotalHours       float64    `json:"old_total_hours"`
+	OldAutoClosed       bool       `json:"old_auto_closed"`
+	NewCheckInTime      *time.Time `json:"new_check_in_time,omitempty"`
+	NewCheckOutTime     *time.Time `json:"new_check_out_time,omitempty"`
+	NewTotalHours       float64    `json:"new_total_hours"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// AccuracyMultipliers scale the reported GPS accuracy into extra geofence tolerance per accuracy band
+type AccuracyMultipliers struct {
+	Excellent float64 `json:"excellent" binding:"min=0,max=10" example:"0.5"` // accuracy < 10m
+	Good      float64 `json:"good" binding:"min=0,max=10" example:"1"`        // accuracy < 25m
+	Fair      float64 `json:"fair" binding:"min=0,max=10" example:"1.5"`      // accuracy < 50m
+	Poor      float64 `json:"poor" binding:"min=0,max=10" example:"2"`        // accuracy >= 50m
+}
+
+// AttendancePolicy holds the tenant clock in/out rules stored in tenants.settings
+type AttendancePolicy struct {
+	LocationCheckEnabled bool `json:"location_check_enabled" example:"true"`
+	RequireSelfie        bool `json:"require_selfie" example:"false"`
+	AllowForce           bool `json:"allow_force" example:"true"`
+
+	// AutoApproveMarginPercent auto-approves clock ins and outs that exceed the geofence by less than this percentage
+	AutoApproveMarginPercent float64             `json:"auto_approve_margin_percent" binding:"min=0,max=100" example:"10"`
+	AccuracyMultipliers      AccuracyMultipliers `json:"accuracy_multipliers"`
+
+	// MaxThresholdFactor caps the adaptive geofence at this multiple of the office radius
+	MaxThresholdFactor float64 `json:"max_threshold_factor" binding:"min=1,max=10" example:"3"`
+
+	// MinShiftMinutes is the minimum time between clock in and clock out, 0 disables the check
+	MinShiftMinutes int `json:"min_shift_minutes" binding:"min=0,max=1440" example:"0"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offline clock event types stored in godplan.attendance_sync_events.event_type
+const (
+	SyncEventClockIn  = "clock_in"
+	SyncEventClockOut = "clock_out"
+)
+
+// Sync results of an offline clock event. duplicate is only returned to the client,
+// the stored row keeps the result of the first attempt.
+const (
+	SyncResultProcessing = "processing"
+	SyncResultAccepted   = "accepted"
+	SyncResultRejected   = "rejected"
+	SyncResultDuplicate  = "duplicate"
+)
+
+// AttendanceSyncEvent is an offline clock event identified by its client idempotency key
+type AttendanceSyncEvent struct {
+	ID                uuid.UUID  `json:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	ClientEventID     string     `json:"client_event_id"`
+	EventType         string     `json:"event_type"`         // clock_in, clock_out
+	DeviceRecordedAt  time.Time  `json:"device_recorded_at"` // device clock
+	RecordedAt        *time.Time `json:"recorded_at,omitempty"`
+	ClockDriftSeconds int        `json:"clock_drift_seconds"`
+	Result            string     `json:"result"`
+	Message           string     `json:"message,omitempty"`
+	AttendanceID      *uuid.UUID `json:"attendance_id,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
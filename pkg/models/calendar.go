@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Holiday types stored in godplan.tenant_holidays.holiday_type
+const (
+	HolidayTypeNational = "national"
+	HolidayTypeCompany  = "company"
+)
+
+// TenantCalendar is the working-day calendar of a tenant for one year
+type TenantCalendar struct {
+	TenantID    uuid.UUID `json:"tenant_id"`
+	Year        int       `json:"year"`
+	WorkingDays int       `json:"working_days"` // bitmask, bit 0 = Sunday ... bit 6 = Saturday
+	Holidays    []Holiday `json:"holidays"`
+}
+
+// Holiday is a non-working date of a tenant
+type Holiday struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Date      string    `json:"date"` // YYYY-MM-DD
+	Name      string    `json:"name"`
+	Type      string    `json:"type"` // national, company
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TenantCalendarRequest updates the working weekdays of a tenant
+type TenantCalendarRequest struct {
+	WorkingDays int `json:"working_days" binding:"required,min=1,max=127" example:"62"`
+}
+
+// HolidayRequest adds a holiday or company day off
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required" example:"2026-12-24"`
+	Name string `json:"name" binding:"required,max=255" example:"Cuti Bersama Natal"`
+	Type string `json:"type" binding:"omitempty,oneof=national company"`
+}
+
+// HolidayImportRequest imports the bundled national holidays of a year
+type HolidayImportRequest struct {
+	Year int `json:"year" binding:"required,min=2000,max=2100" example:"2026"`
+}
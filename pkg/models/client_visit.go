@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client visit states derived from check_out_time
+const (
+	VisitStatusOngoing   = "ongoing"
+	VisitStatusCompleted = "completed"
+)
+
+// ClientVisit is a sales visit to a client, checked in and out at the client location
+type ClientVisit struct {
+	ID                uuid.UUID  `json:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id"`
+	EmployeeID        uuid.UUID  `json:"employee_id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	EmployeeName      string     `json:"employee_name,omitempty"`
+	ProjectID         uuid.UUID  `json:"crm_project_id"`
+	ProjectTitle      string     `json:"crm_project_title,omitempty"`
+	Client            string     `json:"client"`
+	VisitDate         string     `json:"visit_date"` // YYYY-MM-DD in the tenant timezone
+	Status            string     `json:"status"`     // ongoing, completed
+	CheckInTime       time.Time  `json:"check_in_time"`
+	CheckInLatitude   float64    `json:"check_in_latitude"`
+	CheckInLongitude  float64    `json:"check_in_longitude"`
+	CheckInAccuracy   float64    `json:"check_in_accuracy,omitempty"`
+	CheckInPhoto      string     `json:"-"` // storage key
+	CheckInPhotoURL   string     `json:"check_in_photo_url,omitempty"`
+	CheckInNotes      string     `json:"check_in_notes,omitempty"`
+	CheckOutTime      *time.Time `json:"check_out_time,omitempty"`
+	CheckOutLatitude  *float64   `json:"check_out_latitude,omitempty"`
+	CheckOutLongitude *float64   `json:"check_out_longitude,omitempty"`
+	CheckOutAccuracy  *float64   `json:"check_out_accuracy,omitempty"`
+	CheckOutPhoto     string     `json:"-"` // storage key
+	CheckOutPhotoURL  string     `json:"check_out_photo_url,omitempty"`
+	CheckOutNotes     string     `json:"check_out_notes,omitempty"`
+	CheckOutDistance  *float64   `json:"check_out_distance,omitempty"` // meters from the check-in location
+	DurationMinutes   float64    `json:"duration_minutes"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// VisitCheckInRequest starts a visit to the client of a CRM deal
+type VisitCheckInRequest struct {
+	CRMProjectID uuid.UUID `json:"crm_project_id" binding:"required"`
+	Client       string    `json:"client" binding:"max=200" example:"PT Maju Jaya"` // defaults to the client of the deal
+	Latitude     float64   `json:"latitude" binding:"min=-90,max=90" example:"-6.2088"`
+	Longitude    float64   `json:"longitude" binding:"min=-180,max=180" example:"106.8456"`
+	Accuracy     float64   `json:"accuracy" binding:"min=0" example:"12.5"` // GPS accuracy in meters
+	PhotoSelfie  string    `json:"photo_selfie" example:"base64_encoded_image"`
+	Notes        string    `json:"notes" binding:"max=2000" example:"Presentasi penawaran"`
+}
+
+// VisitCheckOutRequest ends the visit
+type VisitCheckOutRequest struct {
+	Latitude    float64 `json:"latitude" binding:"min=-90,max=90" example:"-6.2088"`
+	Longitude   float64 `json:"longitude" binding:"min=-180,max=180" example:"106.8456"`
+	Accuracy    float64 `json:"accuracy" binding:"min=0" example:"12.5"`
+	PhotoSelfie string  `json:"photo_selfie" example:"base64_encoded_image"`
+	Notes       string  `json:"notes" binding:"max=2000" example:"Klien minta revisi harga"`
+}
+
+// DailyVisitReport lists the visits of a salesperson on one day with their durations
+type DailyVisitReport struct {
+	Date                 string        `json:"date"`
+	EmployeeID           uuid.UUID     `json:"employee_id"`
+	TotalVisits          int           `json:"total_visits"`
+	CompletedVisits      int           `json:"completed_visits"`
+	TotalDurationMinutes float64       `json:"total_duration_minutes"`
+	Clients              int           `json:"clients"` // distinct clients visited
+	Visits               []ClientVisit `json:"visits"`
+}
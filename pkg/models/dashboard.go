@@ -5,7 +5,7 @@ import "github.com/google/uuid"
 type DashboardStats struct {
 	ActiveProjects   int    `json:"active_projects"`
 	PendingTasks     int    `json:"pending_tasks"`
-	AttendanceStatus string `json:"attendance_status"`
+	AttendanceStatus string `json:"attendance_status"` // present, on_leave, holiday, day_off, absent
 	CompletionRate   int    `json:"completion_rate"`
 }
 
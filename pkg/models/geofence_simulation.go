@@ -0,0 +1,79 @@
+package models
+
+import "github.com/google/uuid"
+
+// Clock in outcomes compared by the geofence simulator
+const (
+	GeofenceOutcomeApproved     = "approved"      // in range of the adaptive geofence
+	GeofenceOutcomeAutoApproved = "auto_approved" // just outside, within the auto-approve margin
+	GeofenceOutcomeNeedsForce   = "needs_force"   // outside, the employee had to clock in with force
+)
+
+// GeofenceReplaySample is a stored GPS clock in replayed by the geofence simulator
+type GeofenceReplaySample struct {
+	AttendanceID uuid.UUID
+	Latitude     float64
+	Longitude    float64
+	Accuracy     float64 // 0 when the clock in predates accuracy recording
+}
+
+// GeofenceRadiusOverride proposes a new base radius for one circle office
+type GeofenceRadiusOverride struct {
+	OfficeLocationID uuid.UUID `json:"office_location_id" binding:"required"`
+	Radius           int       `json:"radius" binding:"min=1,max=100000" example:"150"`
+}
+
+// GeofenceSimulationRequest is a proposed geofence configuration replayed against past clock ins
+type GeofenceSimulationRequest struct {
+	StartDate string `json:"start_date" binding:"required" example:"2026-01-01"`
+	EndDate   string `json:"end_date" binding:"required" example:"2026-01-31"`
+
+	// Radius replaces the base radius of every circle office without its own override, 0 keeps the current radius
+	Radius      int                      `json:"radius" binding:"min=0,max=100000" example:"0"`
+	OfficeRadii []GeofenceRadiusOverride `json:"office_radii" binding:"max=100,dive"`
+	// Policy is the proposed attendance policy (accuracy multipliers, threshold cap, auto-approve margin), nil keeps the current one
+	Policy *AttendancePolicy `json:"policy"`
+}
+
+// GeofenceOutcomeCounts counts replayed clock ins per outcome
+type GeofenceOutcomeCounts struct {
+	Approved     int `json:"approved"`
+	AutoApproved int `json:"auto_approved"`
+	NeedsForce   int `json:"needs_force"`
+}
+
+// GeofenceTransition counts clock ins whose outcome flips from one outcome to another
+type GeofenceTransition struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+// GeofenceSimulationGroup is the simulation result of one office or one GPS quality bucket
+type GeofenceSimulationGroup struct {
+	Key         string                `json:"key"` // office ID (empty for the env var office) or GPS quality
+	Name        string                `json:"name"`
+	Total       int                   `json:"total"`
+	Changed     int                   `json:"changed"`
+	Current     GeofenceOutcomeCounts `json:"current"`
+	Proposed    GeofenceOutcomeCounts `json:"proposed"`
+	Transitions []GeofenceTransition  `json:"transitions"`
+}
+
+// GeofenceSimulationReport compares the current and proposed geofence outcome of past GPS clock ins
+type GeofenceSimulationReport struct {
+	StartDate    string                    `json:"start_date"`
+	EndDate      string                    `json:"end_date"`
+	Total        int                       `json:"total"`
+	Changed      int                       `json:"changed"`
+	Current      GeofenceOutcomeCounts     `json:"current"`
+	Proposed     GeofenceOutcomeCounts     `json:"proposed"`
+	Transitions  []GeofenceTransition      `json:"transitions"`
+	ByOffice     []GeofenceSimulationGroup `json:"by_office"`
+	ByGPSQuality []GeofenceSimulationGroup `json:"by_gps_quality"`
+
+	// MissingAccuracy counts clock ins without recorded GPS accuracy, replayed without GPS tolerance;
+	// MissingAccuracyChanged is how many of the changed clock ins they account for
+	MissingAccuracy        int `json:"missing_accuracy"`
+	MissingAccuracyChanged int `json:"missing_accuracy_changed"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceKiosk is a reception tablet that shows a rotating QR code for one office
+type AttendanceKiosk struct {
+	ID               uuid.UUID  `json:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	OfficeLocationID uuid.UUID  `json:"office_location_id"`
+	OfficeName       string     `json:"office_name,omitempty"`
+	Name             string     `json:"name"`
+	LastSeenAt       *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Secret signs the QR codes and is never exposed through the API
+	Secret string `json:"-"`
+}
+
+// AttendanceKioskRequest is used to register a kiosk
+type AttendanceKioskRequest struct {
+	OfficeLocationID uuid.UUID `json:"office_location_id" binding:"required"`
+	Name             string    `json:"name" binding:"required,max=100" example:"Tablet Resepsionis Lobby"`
+}
+
+// AttendanceKioskRegistration is returned once when a kiosk is registered or its token rotated.
+// The token authenticates the kiosk device with "Authorization: Kiosk <token>".
+type AttendanceKioskRegistration struct {
+	Kiosk *AttendanceKiosk `json:"kiosk"`
+	Token string           `json:"token"`
+}
+
+// KioskCode is the QR code currently displayed by a kiosk
+type KioskCode struct {
+	KioskID    uuid.UUID `json:"kiosk_id"`
+	OfficeName string    `json:"office_name"`
+	Code       string    `json:"code"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Period     int       `json:"period"` // seconds
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Leave request status values stored in godplan.leave_requests.status
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// LeaveType is a tenant leave category such as cuti tahunan, izin or sakit
+type LeaveType struct {
+	ID                 uuid.UUID `json:"id"`
+	TenantID           uuid.UUID `json:"tenant_id"`
+	Code               string    `json:"code"`
+	Name               string    `json:"name"`
+	IsPaid             bool      `json:"is_paid"`
+	TracksBalance      bool      `json:"tracks_balance"`
+	AnnualQuota        int       `json:"annual_quota"`   // days per year
+	MaxCarryOver       int       `json:"max_carry_over"` // days
+	RequiresAttachment bool      `json:"requires_attachment"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// LeaveTypeRequest is used to create or update a leave type
+type LeaveTypeRequest struct {
+	Code               string `json:"code" binding:"required,max=30" example:"annual"`
+	Name               string `json:"name" binding:"required,max=100" example:"Cuti Tahunan"`
+	IsPaid             bool   `json:"is_paid"`
+	TracksBalance      bool   `json:"tracks_balance"`
+	AnnualQuota        int    `json:"annual_quota" binding:"min=0,max=366" example:"12"`
+	MaxCarryOver       int    `json:"max_carry_over" binding:"min=0,max=366"`
+	RequiresAttachment bool   `json:"requires_attachment"`
+	IsActive           *bool  `json:"is_active"`
+}
+
+// LeaveBalance is an employee's yearly balance for one leave type
+type LeaveBalance struct {
+	EmployeeID    uuid.UUID `json:"employee_id"`
+	LeaveTypeID   uuid.UUID `json:"leave_type_id"`
+	LeaveTypeCode string    `json:"leave_type_code"`
+	LeaveTypeName string    `json:"leave_type_name"`
+	Year          int       `json:"year"`
+	Entitled      int       `json:"entitled"`
+	CarriedOver   int       `json:"carried_over"`
+	Used          int       `json:"used"`
+	Pending       int       `json:"pending"`   // days in requests waiting for approval
+	Remaining     int       `json:"remaining"` // entitled + carried_over - used - pending
+}
+
+// LeaveAccrualCandidate is an employee and balance-tracked leave type that may need a balance for a year
+type LeaveAccrualCandidate struct {
+	EmployeeID        uuid.UUID
+	LeaveTypeID       uuid.UUID
+	AnnualQuota       int
+	MaxCarryOver      int
+	JoinDate          *time.Time
+	PreviousRemaining int // unused days of the previous year, 0 when there is no balance
+}
+
+// LeaveAccrualRequest generates the balances of a year for all employees
+type LeaveAccrualRequest struct {
+	Year int `json:"year" binding:"required,min=2000,max=2100" example:"2026"`
+}
+
+// LeaveRequest is an employee request for cuti, izin or sakit
+type LeaveRequest struct {
+	ID              uuid.UUID  `json:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id"`
+	EmployeeID      uuid.UUID  `json:"employee_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	EmployeeName    string     `json:"employee_name,omitempty"`
+	LeaveTypeID     uuid.UUID  `json:"leave_type_id"`
+	LeaveTypeCode   string     `json:"leave_type_code,omitempty"`
+	LeaveTypeName   string     `json:"leave_type_name,omitempty"`
+	StartDate       string     `json:"start_date"` // YYYY-MM-DD
+	EndDate         string     `json:"end_date"`   // YYYY-MM-DD
+	Days            int        `json:"days"`       // working days
+	Reason          string     `json:"reason,omitempty"`
+	AttachmentKey   string     `json:"-"`
+	AttachmentURL   string     `json:"attachment_url,omitempty"`
+	Status          string     `json:"status"`
+	ApprovedBy      *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateLeaveRequest is the body of a new leave request
+type CreateLeaveRequest struct {
+	LeaveTypeID uuid.UUID `json:"leave_type_id" binding:"required"`
+	StartDate   string    `json:"start_date" binding:"required" example:"2026-01-05"`
+	EndDate     string    `json:"end_date" binding:"required" example:"2026-01-07"`
+	Reason      string    `json:"reason" binding:"max=1000"`
+	Attachment  string    `json:"attachment" example:"base64_encoded_file"` // JPEG, PNG or PDF, e.g. surat dokter
+}
+
+// LeaveDecisionRequest is used to approve or reject a leave request
+type LeaveDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
@@ -1,14 +1,95 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// GeoJSONPolygon is a GeoJSON Polygon geometry. The first ring is the outer boundary,
+// any following rings are holes. Positions are [longitude, latitude].
+type GeoJSONPolygon struct {
+	Type        string        `json:"type" example:"Polygon"`
+	Coordinates [][][]float64 `json:"coordinates"`
+}
+
+// OfficeLocation represents a tenant geofence (head office, branch or client site).
+// When Boundary is set the polygon is used instead of the circular Radius.
 type OfficeLocation struct {
-	ID        uuid.UUID `json:"id"`
-	TenantID  uuid.UUID `json:"tenant_id"`
-	Name      string    `json:"name"`
-	Latitude  float64   `json:"latitude"`
-	Longitude float64   `json:"longitude"`
-	Radius    int       `json:"radius"`
-	Address   string    `json:"address"`
-	IsActive  bool      `json:"is_active"`
+	ID        uuid.UUID       `json:"id"`
+	TenantID  uuid.UUID       `json:"tenant_id"`
+	Name      string          `json:"name"`
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	Radius    int             `json:"radius"`
+	Boundary  *GeoJSONPolygon `json:"boundary,omitempty"`
+	Address   string          `json:"address"`
+	Timezone  string          `json:"timezone,omitempty"` // IANA name, empty = tenant timezone
+	IsActive  bool            `json:"is_active"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// OfficeLocationRequest is used for create/update operations
+type OfficeLocationRequest struct {
+	Name      string          `json:"name" binding:"required,max=200"`
+	Latitude  float64         `json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64         `json:"longitude" binding:"min=-180,max=180"`
+	Radius    int             `json:"radius" binding:"min=0"`
+	Boundary  *GeoJSONPolygon `json:"boundary"`
+	Address   string          `json:"address"`
+	Timezone  string          `json:"timezone" binding:"max=64" example:"Asia/Makassar"`
+	IsActive  *bool           `json:"is_active"`
+}
+
+// TenantTimezoneRequest sets the IANA timezone of the tenant
+type TenantTimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required,max=64" example:"Asia/Jakarta"`
+}
+
+// Presence proof methods recorded on attendances
+const (
+	PresenceMethodGPS   = "gps"
+	PresenceMethodWiFi  = "wifi"
+	PresenceMethodBLE   = "ble"
+	PresenceMethodQR    = "qr"
+	PresenceMethodForce = "force"
+
+	// PresenceMethodRemote dipakai pada hari WFH tanpa lokasi rumah terdaftar (geofence dilewati)
+	PresenceMethodRemote = "remote"
+)
+
+// OfficePresenceSignal is a Wi-Fi access point or BLE beacon installed at an office.
+// Seeing one of these proves presence even when indoor GPS is inaccurate.
+type OfficePresenceSignal struct {
+	ID               uuid.UUID `json:"id"`
+	TenantID         uuid.UUID `json:"tenant_id"`
+	OfficeLocationID uuid.UUID `json:"office_location_id"`
+	SignalType       string    `json:"signal_type"` // wifi or ble
+	Identifier       string    `json:"identifier"`  // BSSID or beacon ID
+	Label            string    `json:"label"`
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// OfficePresenceSignalRequest is used to register a presence signal for an office
+type OfficePresenceSignalRequest struct {
+	SignalType string `json:"signal_type" binding:"required,oneof=wifi ble" example:"wifi"`
+	Identifier string `json:"identifier" binding:"required,max=100" example:"a4:2b:b0:7c:11:90"`
+	Label      string `json:"label" binding:"max=200" example:"AP Lantai 3"`
+	IsActive   *bool  `json:"is_active"`
+}
+
+// PresenceProof carries the Wi-Fi and BLE signals seen by the device during a clock event
+type PresenceProof struct {
+	WifiBSSIDs []string `json:"wifi_bssids,omitempty" binding:"omitempty,max=50" example:"a4:2b:b0:7c:11:90"`
+	BeaconIDs  []string `json:"beacon_ids,omitempty" binding:"omitempty,max=50" example:"f7826da6-4fa2-4e98-8024-bc5b71e0893e:100:1"`
+}
+
+// PresenceMatch is a registered signal matched by a PresenceProof
+type PresenceMatch struct {
+	OfficeLocationID uuid.UUID
+	Method           string // wifi or ble
+	Identifier       string
 }
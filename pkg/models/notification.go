@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification types stored in godplan.notifications.type
+const (
+	NotificationTypeAutoClockOut         = "auto_clock_out"
+	NotificationTypeAttendanceCorrection = "attendance_correction"
+	NotificationTypeOvertime             = "overtime"
+)
+
+// Notification is an in-app message for a user
+type Notification struct {
+	ID          uuid.UUID  `json:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Type        string     `json:"type"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	ReferenceID *uuid.UUID `json:"reference_id,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Overtime request status values stored in godplan.overtime_requests.status
+const (
+	OvertimeStatusPending   = "pending"
+	OvertimeStatusApproved  = "approved"
+	OvertimeStatusRejected  = "rejected"
+	OvertimeStatusCancelled = "cancelled"
+)
+
+// Overtime day types, each with its own tenant multiplier
+const (
+	OvertimeDayWeekday = "weekday"
+	OvertimeDayWeekend = "weekend"
+	OvertimeDayHoliday = "holiday"
+)
+
+// OvertimePolicy holds the tenant multipliers that convert approved overtime into compensable hours
+type OvertimePolicy struct {
+	WeekdayMultiplier float64 `json:"weekday_multiplier" binding:"min=1,max=10" example:"1.5"`
+	WeekendMultiplier float64 `json:"weekend_multiplier" binding:"min=1,max=10" example:"2"`
+	HolidayMultiplier float64 `json:"holiday_multiplier" binding:"min=1,max=10" example:"3"`
+}
+
+// OvertimeRequest is overtime requested ahead of time. After approval it is reconciled with
+// the clocked attendance sessions on clock out.
+type OvertimeRequest struct {
+	ID               uuid.UUID  `json:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	EmployeeID       uuid.UUID  `json:"employee_id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	EmployeeName     string     `json:"employee_name,omitempty"`
+	OvertimeDate     string     `json:"overtime_date"` // YYYY-MM-DD in the tenant timezone
+	StartTime        time.Time  `json:"start_time"`
+	EndTime          time.Time  `json:"end_time"`
+	PlannedMinutes   float64    `json:"planned_minutes"`
+	Reason           string     `json:"reason"`
+	Status           string     `json:"status"`
+	ApprovedBy       *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedAt       *time.Time `json:"approved_at,omitempty"`
+	RejectionReason  string     `json:"rejection_reason,omitempty"`
+	DayType          string     `json:"day_type,omitempty"` // weekday, weekend, holiday
+	AttendanceID     *uuid.UUID `json:"attendance_id,omitempty"`
+	ActualMinutes    float64    `json:"actual_minutes"`
+	Multiplier       float64    `json:"multiplier,omitempty"`
+	CompensableHours float64    `json:"compensable_hours"`
+	ReconciledAt     *time.Time `json:"reconciled_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CreateOvertimeRequest is the body of a new overtime request
+type CreateOvertimeRequest struct {
+	StartTime time.Time `json:"start_time" binding:"required" example:"2026-01-05T17:00:00+07:00"`
+	EndTime   time.Time `json:"end_time" binding:"required" example:"2026-01-05T20:00:00+07:00"`
+	Reason    string    `json:"reason" binding:"required,max=1000" example:"Deploy rilis aplikasi klien"`
+}
+
+// OvertimeDecisionRequest is used to approve or reject an overtime request
+type OvertimeDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// OvertimeReportRow sums the reconciled overtime of one employee
+type OvertimeReportRow struct {
+	EmployeeID       uuid.UUID `json:"employee_id"`
+	EmployeeName     string    `json:"employee_name"`
+	ApprovedRequests int       `json:"approved_requests"`
+	PlannedMinutes   float64   `json:"planned_minutes"`
+	ActualMinutes    float64   `json:"actual_minutes"`
+	WeekdayMinutes   float64   `json:"weekday_minutes"`
+	WeekendMinutes   float64   `json:"weekend_minutes"`
+	HolidayMinutes   float64   `json:"holiday_minutes"`
+	CompensableHours float64   `json:"compensable_hours"`
+}
+
+// OvertimeReport is the compensable overtime of a period
+type OvertimeReport struct {
+	StartDate             string              `json:"start_date"`
+	EndDate               string              `json:"end_date"`
+	Policy                OvertimePolicy      `json:"policy"`
+	Employees             []OvertimeReportRow `json:"employees"`
+	TotalCompensableHours float64             `json:"total_compensable_hours"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Work modes stored in godplan.attendances.work_mode
+const (
+	WorkModeOffice = "office"
+	WorkModeWFH    = "wfh"
+)
+
+// WFHLocationName adalah nama lokasi attendance WFH tanpa lokasi rumah terdaftar
+const WFHLocationName = "WFH"
+
+// WFH request status values stored in godplan.wfh_requests.status
+const (
+	WFHStatusPending   = "pending"
+	WFHStatusApproved  = "approved"
+	WFHStatusRejected  = "rejected"
+	WFHStatusCancelled = "cancelled"
+)
+
+// HomeLocation is the registered home geofence of an employee for WFH days
+type HomeLocation struct {
+	ID           uuid.UUID `json:"id"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	EmployeeName string    `json:"employee_name,omitempty"`
+	Name         string    `json:"name"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	Radius       int       `json:"radius"` // meters
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HomeLocationRequest registers or moves the employee home location
+type HomeLocationRequest struct {
+	Name      string  `json:"name" binding:"max=200" example:"Rumah"`
+	Latitude  float64 `json:"latitude" binding:"min=-90,max=90" example:"-6.2088"`
+	Longitude float64 `json:"longitude" binding:"min=-180,max=180" example:"106.8456"`
+	Radius    int     `json:"radius" binding:"omitempty,min=50,max=1000" example:"150"` // default 150
+}
+
+// WFHRequest is an employee request to work from home on one or more days
+type WFHRequest struct {
+	ID              uuid.UUID  `json:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id"`
+	EmployeeID      uuid.UUID  `json:"employee_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	EmployeeName    string     `json:"employee_name,omitempty"`
+	StartDate       string     `json:"start_date"` // YYYY-MM-DD
+	EndDate         string     `json:"end_date"`   // YYYY-MM-DD
+	Reason          string     `json:"reason,omitempty"`
+	Status          string     `json:"status"`
+	ApprovedBy      *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateWFHRequest is the body of a new WFH request
+type CreateWFHRequest struct {
+	StartDate string `json:"start_date" binding:"required" example:"2026-01-05"`
+	EndDate   string `json:"end_date" binding:"required" example:"2026-01-06"`
+	Reason    string `json:"reason" binding:"max=1000"`
+}
+
+// WFHDecisionRequest is used to approve or reject a WFH request
+type WFHDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// RemoteWorkPolicy is the per-tenant WFH attendance rule, stored under the "remote_work"
+// key of tenants.settings
+type RemoteWorkPolicy struct {
+	// RequireHomeLocation menolak clock in WFH tanpa lokasi rumah terdaftar.
+	// Jika false, geofence dilewati untuk karyawan yang belum mendaftarkan lokasi rumah.
+	RequireHomeLocation bool `json:"require_home_location"`
+}
+
+// RemoteWorkDay is an approved WFH day of an employee and the geofence to validate against
+type RemoteWorkDay struct {
+	Request             WFHRequest
+	Home                *HomeLocation // nil when the employee has no registered home location
+	RequireHomeLocation bool
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shift swap status values stored in godplan.shift_swap_requests.status
+const (
+	ShiftSwapStatusPending   = "pending"
+	ShiftSwapStatusApproved  = "approved"
+	ShiftSwapStatusRejected  = "rejected"
+	ShiftSwapStatusCancelled = "cancelled"
+)
+
+// ShiftRoster is the rostered shift of an employee on one day. Shift definitions are
+// work schedules, so a night shift is a schedule whose end_time is before its start_time.
+type ShiftRoster struct {
+	ID           uuid.UUID  `json:"id"`
+	TenantID     uuid.UUID  `json:"tenant_id"`
+	EmployeeID   uuid.UUID  `json:"employee_id"`
+	EmployeeName string     `json:"employee_name"`
+	ShiftDate    string     `json:"shift_date"` // YYYY-MM-DD, the date the shift starts
+	ScheduleID   *uuid.UUID `json:"schedule_id,omitempty"`
+	ScheduleName string     `json:"schedule_name,omitempty"`
+	StartTime    string     `json:"start_time,omitempty"` // HH:MM
+	EndTime      string     `json:"end_time,omitempty"`   // HH:MM
+	IsDayOff     bool       `json:"is_day_off"`
+	Notes        string     `json:"notes,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// RosterEntryRequest sets the shift of one employee on one day
+type RosterEntryRequest struct {
+	EmployeeID uuid.UUID  `json:"employee_id" binding:"required"`
+	Date       string     `json:"date" binding:"required" example:"2026-01-05"`
+	ScheduleID *uuid.UUID `json:"schedule_id"` // null marks a day off
+	Notes      string     `json:"notes" binding:"max=255"`
+}
+
+// RosterRequest saves several roster entries at once, e.g. a full week
+type RosterRequest struct {
+	Entries []RosterEntryRequest `json:"entries" binding:"required,min=1,max=500,dive"`
+}
+
+// RosteredShift is a working shift instance used to tie an attendance to its shift
+type RosteredShift struct {
+	RosterID   uuid.UUID
+	EmployeeID uuid.UUID
+	ShiftDate  string // YYYY-MM-DD
+	Schedule   AttendanceSchedule
+}
+
+// ShiftSwapRequest asks to exchange the roster of two employees on the given dates
+type ShiftSwapRequest struct {
+	ID               uuid.UUID  `json:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	RequesterID      uuid.UUID  `json:"requester_id"`
+	RequesterName    string     `json:"requester_name,omitempty"`
+	RequesterDate    string     `json:"requester_date"` // YYYY-MM-DD
+	TargetEmployeeID uuid.UUID  `json:"target_employee_id"`
+	TargetName       string     `json:"target_name,omitempty"`
+	TargetDate       string     `json:"target_date"` // YYYY-MM-DD
+	Reason           string     `json:"reason,omitempty"`
+	Status           string     `json:"status"`
+	ApprovedBy       *uuid.UUID `json:"approved_by,omitempty"`
+	ApprovedAt       *time.Time `json:"approved_at,omitempty"`
+	RejectionReason  string     `json:"rejection_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CreateShiftSwapRequest is the body of a new shift swap request
+type CreateShiftSwapRequest struct {
+	RequesterDate    string    `json:"requester_date" binding:"required" example:"2026-01-05"`
+	TargetEmployeeID uuid.UUID `json:"target_employee_id" binding:"required"`
+	TargetDate       string    `json:"target_date" example:"2026-01-06"` // defaults to requester_date
+	Reason           string    `json:"reason" binding:"max=500"`
+}
+
+// ShiftSwapDecisionRequest is used to approve or reject a shift swap
+type ShiftSwapDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceSchedule represents a tenant work schedule used for lateness and overtime
+type AttendanceSchedule struct {
+	ID             uuid.UUID `json:"id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	Name           string    `json:"name"`
+	StartTime      string    `json:"start_time"`   // HH:MM
+	EndTime        string    `json:"end_time"`     // HH:MM, earlier than start_time for overnight schedules
+	WorkingDays    int       `json:"working_days"` // bitmask, bit 0 = Sunday ... bit 6 = Saturday
+	ToleranceLate  int       `json:"tolerance_late"`
+	ToleranceEarly int       `json:"tolerance_early"`
+	IsDefault      bool      `json:"is_default"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// AttendanceScheduleRequest is used to create or update a schedule
+type AttendanceScheduleRequest struct {
+	Name           string `json:"name" binding:"required,max=255"`
+	StartTime      string `json:"start_time" binding:"required"`
+	EndTime        string `json:"end_time" binding:"required"`
+	WorkingDays    int    `json:"working_days" binding:"min=0,max=127"`
+	ToleranceLate  int    `json:"tolerance_late" binding:"min=0,max=720"`
+	ToleranceEarly int    `json:"tolerance_early" binding:"min=0,max=720"`
+	IsDefault      bool   `json:"is_default"`
+	IsActive       *bool  `json:"is_active"`
+}
+
+// AssignScheduleRequest assigns a schedule to one or more employees
+type AssignScheduleRequest struct {
+	EmployeeIDs []uuid.UUID `json:"employee_ids" binding:"required,min=1"`
+}
+
+// AttendanceTimeStats holds schedule-based minutes computed for one attendance
+type AttendanceTimeStats struct {
+	LateMinutes       float64 `json:"late_minutes"`
+	EarlyLeaveMinutes float64 `json:"early_leave_minutes"`
+	OvertimeMinutes   float64 `json:"overtime_minutes"`
+}
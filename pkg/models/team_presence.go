@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Presence states of an employee on the team presence board
+const (
+	PresenceClockedIn  = "clocked_in"
+	PresenceOnBreak    = "on_break"
+	PresenceClockedOut = "clocked_out"
+	PresenceOnLeave    = "on_leave"
+	PresenceHoliday    = "holiday"
+	PresenceDayOff     = "day_off"
+	PresenceNotArrived = "not_arrived"
+)
+
+// TeamMemberPresence is the live attendance state of one team member
+type TeamMemberPresence struct {
+	EmployeeID      uuid.UUID  `json:"employee_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	Name            string     `json:"name"`
+	AvatarURL       string     `json:"avatar_url"`
+	Position        string     `json:"position"`
+	Status          string     `json:"status"` // clocked_in, on_break, clocked_out, on_leave, holiday, day_off, not_arrived
+	Late            bool       `json:"late"`
+	LateMinutes     float64    `json:"late_minutes,omitempty"`
+	WorkMode        string     `json:"work_mode,omitempty"` // office, wfh
+	CheckInTime     *time.Time `json:"check_in_time,omitempty"`
+	CheckOutTime    *time.Time `json:"check_out_time,omitempty"`
+	BreakType       string     `json:"break_type,omitempty"`
+	BreakStartedAt  *time.Time `json:"break_started_at,omitempty"`
+	LeaveType       string     `json:"leave_type,omitempty"`
+	LastCheckInAt   *time.Time `json:"last_check_in_at,omitempty"`
+	LastCheckInDate string     `json:"last_check_in_date,omitempty"` // YYYY-MM-DD
+	LastOfficeName  string     `json:"last_office_name,omitempty"`   // office of the last clock in
+}
+
+// TeamPresenceRecord is the latest attendance, running break and leave of a team member on
+// a date, before the presence state is resolved
+type TeamPresenceRecord struct {
+	TeamMemberPresence
+	LastCheckOutAt *time.Time // check out of the latest attendance
+	DayType        string     // holiday, day_off or empty on a working day
+}
+
+// TeamPresenceSummary counts team members per presence state
+type TeamPresenceSummary struct {
+	Total      int `json:"total"`
+	ClockedIn  int `json:"clocked_in"`
+	OnBreak    int `json:"on_break"`
+	ClockedOut int `json:"clocked_out"`
+	OnLeave    int `json:"on_leave"`
+	Off        int `json:"off"` // holiday or day off
+	NotArrived int `json:"not_arrived"`
+	Late       int `json:"late"`
+}
+
+// TeamPresenceBoard is the presence board of a manager's team on a date
+type TeamPresenceBoard struct {
+	Date     string               `json:"date"` // YYYY-MM-DD in the tenant timezone
+	Timezone string               `json:"timezone"`
+	Summary  TeamPresenceSummary  `json:"summary"`
+	Members  []TeamMemberPresence `json:"members"`
+}
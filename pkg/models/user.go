@@ -58,6 +58,7 @@ type Employee struct {
 	JoinDate       string     `json:"join_date" db:"join_date"`
 	EmploymentType string     `json:"employment_type" db:"employment_type"`
 	WorkSchedule   string     `json:"work_schedule" db:"work_schedule"`
+	ScheduleID     *uuid.UUID `json:"schedule_id,omitempty" db:"schedule_id"`
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
 }
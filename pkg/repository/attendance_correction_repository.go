@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrCorrectionNotFound   = errors.New("attendance correction request not found")
+	ErrCorrectionNotPending = errors.New("attendance correction request is not pending")
+	ErrCorrectionExists     = errors.New("this attendance already has a pending correction request")
+	ErrAttendanceChanged    = errors.New("the attendance changed after the correction was requested, ask the employee to submit a new correction")
+)
+
+// AttendanceCorrectionRepository defines access methods for correction requests and the attendance audit trail
+type AttendanceCorrectionRepository interface {
+	GetAttendanceSession(tenantID uuid.UUID, attendanceID uuid.UUID) (*models.AttendanceSession, error)
+	CreateCorrectionRequest(request *models.AttendanceCorrectionRequest) error
+	GetCorrectionRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceCorrectionRequest, error)
+	GetCorrectionRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.AttendanceCorrectionRequest, error)
+	GetPendingCorrectionRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.AttendanceCorrectionRequest, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	ApplyCorrection(request *models.AttendanceCorrectionRequest, approvedBy uuid.UUID, reason string, session *models.AttendanceSession, corrected *models.AttendanceSession) error
+	RejectCorrection(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string) error
+	CancelCorrectionRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error
+	GetAuditLogs(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceAuditLog, error)
+}
+
+type attendanceCorrectionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAttendanceCorrectionRepository(db *sql.DB) AttendanceCorrectionRepository {
+	return &attendanceCorrectionRepositoryImpl{db: db}
+}
+
+func (r *attendanceCorrectionRepositoryImpl) GetAttendanceSession(tenantID uuid.UUID, attendanceID uuid.UUID) (*models.AttendanceSession, error) {
+	var session models.AttendanceSession
+	var checkOut sql.NullTime
+	err := r.db.QueryRow(`SELECT id, tenant_id, user_id, TO_CHAR(attendance_date, 'YYYY-MM-DD'),
+			COALESCE(check_in_time, created_at), check_out_time, COALESCE(total_hours, 0),
+			COALESCE(break_minutes, 0), COALESCE(auto_closed, false),
+			roster_id, schedule_id, COALESCE(timezone, ''),
+			COALESCE(late_minutes, 0), COALESCE(early_leave_minutes, 0), COALESCE(overtime_minutes, 0)
+		FROM godplan.attendances
+		WHERE id = $1 AND tenant_id = $2`, attendanceID, tenantID).Scan(
+		&session.ID,
+		&session.TenantID,
+		&session.UserID,
+		&session.AttendanceDate,
+		&session.CheckInTime,
+		&checkOut,
+		&session.TotalHours,
+		&session.BreakMinutes,
+		&session.AutoClosed,
+		&session.RosterID,
+		&session.ScheduleID,
+		&session.Timezone,
+		&session.LateMinutes,
+		&session.EarlyLeaveMinutes,
+		&session.OvertimeMinutes,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrAttendanceNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	if checkOut.Valid {
+		session.CheckOutTime = &checkOut.Time
+	}
+	return &session, nil
+}
+
+func (r *attendanceCorrectionRepositoryImpl) CreateCorrectionRequest(request *models.AttendanceCorrectionRequest) error {
+	query := `INSERT INTO godplan.attendance_correction_requests
+		(tenant_id, attendance_id, employee_id, user_id, proposed_check_in_time, proposed_check_out_time, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		request.TenantID,
+		request.AttendanceID,
+		request.EmployeeID,
+		request.UserID,
+		request.ProposedCheckInTime,
+		request.ProposedCheckOutTime,
+		request.Reason,
+		request.Status,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrCorrectionExists
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const correctionRequestQuery = `SELECT cr.id, cr.tenant_id, cr.attendance_id, cr.employee_id, cr.user_id,
+		COALESCE(u.full_name, u.username), TO_CHAR(a.attendance_date, 'YYYY-MM-DD'),
+		COALESCE(a.check_in_time, a.created_at), a.check_out_time,
+		cr.proposed_check_in_time, cr.proposed_check_out_time, cr.reason, cr.status,
+		cr.approved_by, cr.approved_at, COALESCE(cr.rejection_reason, ''), cr.created_at, cr.updated_at
+	FROM godplan.attendance_correction_requests cr
+	JOIN godplan.attendances a ON a.id = cr.attendance_id
+	JOIN godplan.users u ON u.id = cr.user_id`
+
+func scanCorrectionRequest(row rowScanner) (*models.AttendanceCorrectionRequest, error) {
+	var request models.AttendanceCorrectionRequest
+	var currentCheckIn time.Time
+	var currentCheckOut, proposedCheckIn, proposedCheckOut, approvedAt sql.NullTime
+	err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.AttendanceID,
+		&request.EmployeeID,
+		&request.UserID,
+		&request.EmployeeName,
+		&request.AttendanceDate,
+		&currentCheckIn,
+		&currentCheckOut,
+		&proposedCheckIn,
+		&proposedCheckOut,
+		&request.Reason,
+		&request.Status,
+		&request.ApprovedBy,
+		&approvedAt,
+		&request.RejectionReason,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.CurrentCheckInTime = &currentCheckIn
+	request.CurrentCheckOutTime = nullTimePtr(currentCheckOut)
+	request.ProposedCheckInTime = nullTimePtr(proposedCheckIn)
+	request.ProposedCheckOutTime = nullTimePtr(proposedCheckOut)
+	request.ApprovedAt = nullTimePtr(approvedAt)
+	return &request, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (r *attendanceCorrectionRepositoryImpl) queryCorrectionRequests(query string, args ...interface{}) ([]models.AttendanceCorrectionRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	requests := []models.AttendanceCorrectionRequest{}
+	for rows.Next() {
+		request, err := scanCorrectionRequest(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, nil
+}
+
+func (r *attendanceCorrectionRepositoryImpl) GetCorrectionRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceCorrectionRequest, error) {
+	query := correctionRequestQuery + ` WHERE cr.id = $1 AND cr.tenant_id = $2`
+
+	request, err := scanCorrectionRequest(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrCorrectionNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return request, nil
+}
+
+func (r *attendanceCorrectionRepositoryImpl) GetCorrectionRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.AttendanceCorrectionRequest, error) {
+	query := correctionRequestQuery + `
+		WHERE cr.tenant_id = $1 AND cr.employee_id = $2
+		ORDER BY cr.created_at DESC`
+	return r.queryCorrectionRequests(query, tenantID, employeeID)
+}
+
+// GetPendingCorrectionRequests returns pending requests of the tenant, or only those of the
+// supervisor's direct reports when supervisorID is set
+func (r *attendanceCorrectionRepositoryImpl) GetPendingCorrectionRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.AttendanceCorrectionRequest, error) {
+	query := correctionRequestQuery + `
+		JOIN godplan.employees e ON e.id = cr.employee_id
+		WHERE cr.tenant_id = $1 AND cr.status = 'pending'
+		AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY a.attendance_date ASC, cr.created_at ASC`
+	return r.queryCorrectionRequests(query, tenantID, supervisorID)
+}
+
+func (r *attendanceCorrectionRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+// ApplyCorrection approves a pending request, writes the corrected session to the attendance
+// and keeps the original values in the audit trail, in one transaction. The attendance is only
+// updated when it still has the times of session, otherwise ErrAttendanceChanged is returned.
+func (r *attendanceCorrectionRepositoryImpl) ApplyCorrection(request *models.AttendanceCorrectionRequest, approvedBy uuid.UUID, reason string, session *models.AttendanceSession, corrected *models.AttendanceSession) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE godplan.attendance_correction_requests
+		SET status = 'approved', approved_by = $1, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND tenant_id = $4 AND status = 'pending'`,
+		approvedBy, reason, request.ID, request.TenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrCorrectionNotPending
+	}
+
+	// Jam pulang yang dikoreksi bukan lagi hasil auto clock-out
+	result, err = tx.Exec(`UPDATE godplan.attendances
+		SET check_in_time = $1, check_out_time = $2, total_hours = $3, break_minutes = $4,
+		    type = CASE WHEN $2::timestamp IS NULL THEN type ELSE 'CheckOut' END,
+		    auto_closed = $5, late_minutes = $10, early_leave_minutes = $11, overtime_minutes = $12,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND tenant_id = $7
+		AND COALESCE(check_in_time, created_at) = $8 AND check_out_time IS NOT DISTINCT FROM $9::timestamp`,
+		corrected.CheckInTime, corrected.CheckOutTime, corrected.TotalHours, corrected.BreakMinutes,
+		corrected.AutoClosed, session.ID, session.TenantID, session.CheckInTime, session.CheckOutTime,
+		corrected.LateMinutes, corrected.EarlyLeaveMinutes, corrected.OvertimeMinutes)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrAttendanceChanged
+	}
+
+	_, err = tx.Exec(`INSERT INTO godplan.attendance_audit_logs
+		(tenant_id, attendance_id, correction_request_id, changed_by, source,
+		 old_check_in_time, old_check_out_time, old_total_hours, old_auto_closed,
+		 new_check_in_time, new_check_out_time, new_total_hours)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		session.TenantID, session.ID, request.ID, approvedBy, models.AttendanceChangeCorrection,
+		session.CheckInTime, session.CheckOutTime, session.TotalHours, session.AutoClosed,
+		corrected.CheckInTime, corrected.CheckOutTime, corrected.TotalHours)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *attendanceCorrectionRepositoryImpl) RejectCorrection(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string) error {
+	result, err := r.db.Exec(`UPDATE godplan.attendance_correction_requests
+		SET status = 'rejected', approved_by = $1, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND tenant_id = $4 AND status = 'pending'`,
+		approvedBy, reason, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrCorrectionNotPending
+	}
+	return nil
+}
+
+// CancelCorrectionRequest lets the employee withdraw their own pending request
+func (r *attendanceCorrectionRepositoryImpl) CancelCorrectionRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.attendance_correction_requests
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND employee_id = $3 AND status = 'pending'`,
+		id, tenantID, employeeID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrCorrectionNotPending
+	}
+	return nil
+}
+
+// GetAuditLogs returns the changes of an attendance, oldest first
+func (r *attendanceCorrectionRepositoryImpl) GetAuditLogs(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceAuditLog, error) {
+	rows, err := r.db.Query(`SELECT l.id, l.tenant_id, l.attendance_id, l.correction_request_id, l.changed_by,
+			COALESCE(u.full_name, u.username, ''), l.source,
+			l.old_check_in_time, l.old_check_out_time, COALESCE(l.old_total_hours, 0), COALESCE(l.old_auto_closed, false),
+			l.new_check_in_time, l.new_check_out_time, COALESCE(l.new_total_hours, 0), l.created_at
+		FROM godplan.attendance_audit_logs l
+		LEFT JOIN godplan.users u ON u.id = l.changed_by
+		WHERE l.tenant_id = $1 AND l.attendance_id = $2
+		ORDER BY l.created_at ASC`, tenantID, attendanceID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	logs := []models.AttendanceAuditLog{}
+	for rows.Next() {
+		var log models.AttendanceAuditLog
+		var oldCheckIn, oldCheckOut, newCheckIn, newCheckOut sql.NullTime
+		err := rows.Scan(
+			&log.ID,
+			&log.TenantID,
+			&log.AttendanceID,
+			&log.CorrectionRequestID,
+			&log.ChangedBy,
+			&log.ChangedByName,
+			&log.Source,
+			&oldCheckIn,
+			&oldCheckOut,
+			&log.OldTotalHours,
+			&log.OldAutoClosed,
+			&newCheckIn,
+			&newCheckOut,
+			&log.NewTotalHours,
+			&log.CreatedAt,
+		)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		log.OldCheckInTime = nullTimePtr(oldCheckIn)
+		log.OldCheckOutTime = nullTimePtr(oldCheckOut)
+		log.NewCheckInTime = nullTimePtr(newCheckIn)
+		log.NewCheckOutTime = nullTimePtr(newCheckOut)
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
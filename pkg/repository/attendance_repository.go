@@ -0,0 +1,388 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrAttendanceNotFound   = errors.New("attendance not found")
+	ErrAttendanceNotPending = errors.New("attendance is not pending approval")
+)
+
+// AttendanceRepository defines access methods for attendance records outside the clock-in/out flow
+type AttendanceRepository interface {
+	GetPendingAttendances(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.PendingAttendance, error)
+	GetAttendanceStatus(tenantID uuid.UUID, attendanceID uuid.UUID) (uuid.UUID, string, error)
+	IsSupervisedBy(tenantID uuid.UUID, attendanceID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	UpdateApprovalStatus(tenantID uuid.UUID, attendanceID uuid.UUID, status string, approvedBy uuid.UUID, reason string) error
+	GetAttendanceRecap(tenantID uuid.UUID, filter models.AttendanceRecapFilter) ([]models.AttendanceRecap, error)
+	GetLastClockEvent(tenantID uuid.UUID, userID uuid.UUID, before time.Time) (*models.ClockEvent, error)
+	CountReusedCoordinateDays(tenantID uuid.UUID, userID uuid.UUID, lat, lng float64, excludeDate string) (int, error)
+	GetOpenSessions(checkedInBefore time.Time) ([]models.OpenAttendanceSession, error)
+	AutoCloseSession(attendanceID uuid.UUID, checkOut time.Time, totalHours float64, breakMinutes float64, closedAt time.Time) (bool, error)
+	GetGeofenceReplaySamples(tenantID uuid.UUID, startDate, endDate string) ([]models.GeofenceReplaySample, error)
+	FindSimilarPhoto(tenantID uuid.UUID, userID uuid.UUID, excludeAttendanceID *uuid.UUID, hash int64, sinceDate string, maxDistance int) (*models.PhotoMatch, error)
+}
+
+type attendanceRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAttendanceRepository(db *sql.DB) AttendanceRepository {
+	return &attendanceRepositoryImpl{db: db}
+}
+
+// GetPendingAttendances returns attendances waiting for approval. When supervisorID is set
+// only attendances of that supervisor's direct reports are returned.
+func (r *attendanceRepositoryImpl) GetPendingAttendances(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.PendingAttendance, error) {
+	query := `SELECT a.id, a.user_id, COALESCE(u.full_name, u.username), a.type, a.status,
+			TO_CHAR(a.attendance_date, 'YYYY-MM-DD'), a.check_in_time, a.check_out_time,
+			COALESCE(a.check_in_lat, 0), COALESCE(a.check_in_lng, 0), a.in_range, a.force_attendance,
+			COALESCE(ol.name, a.location_name, $3), COALESCE(a.fraud_score, 0), COALESCE(a.fraud_flags, '{}'), a.created_at,
+			a.photo_reuse_attendance_id
+		FROM godplan.attendances a
+		JOIN godplan.users u ON u.id = a.user_id
+		LEFT JOIN godplan.employees e ON e.user_id = a.user_id AND e.tenant_id = a.tenant_id
+		LEFT JOIN godplan.office_locations ol ON ol.id = a.office_location_id AND ol.tenant_id = a.tenant_id
+		WHERE a.tenant_id = $1
+		AND a.status IN ('pending', 'pending_forced')
+		AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY a.created_at ASC`
+
+	rows, err := r.db.Query(query, tenantID, supervisorID, utils.DefaultOfficeName)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	attendances := []models.PendingAttendance{}
+	for rows.Next() {
+		var a models.PendingAttendance
+		if err := rows.Scan(
+			&a.ID,
+			&a.UserID,
+			&a.EmployeeName,
+			&a.Type,
+			&a.Status,
+			&a.AttendanceDate,
+			&a.CheckInTime,
+			&a.CheckOutTime,
+			&a.Latitude,
+			&a.Longitude,
+			&a.InRange,
+			&a.ForceAttendance,
+			&a.LocationName,
+			&a.FraudScore,
+			pq.Array(&a.FraudFlags),
+			&a.CreatedAt,
+			&a.PhotoReuseAttendanceID,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		attendances = append(attendances, a)
+	}
+
+	return attendances, nil
+}
+
+// GetAttendanceStatus returns the owner user ID and current status of an attendance
+func (r *attendanceRepositoryImpl) GetAttendanceStatus(tenantID uuid.UUID, attendanceID uuid.UUID) (uuid.UUID, string, error) {
+	var userID uuid.UUID
+	var status string
+	err := r.db.QueryRow(`SELECT user_id, status FROM godplan.attendances WHERE id = $1 AND tenant_id = $2`,
+		attendanceID, tenantID).Scan(&userID, &status)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, "", ErrAttendanceNotFound
+	}
+	if err != nil {
+		return uuid.Nil, "", utils.ErrInternalServer
+	}
+	return userID, status, nil
+}
+
+// IsSupervisedBy checks whether the attendance owner reports directly to the given supervisor
+func (r *attendanceRepositoryImpl) IsSupervisedBy(tenantID uuid.UUID, attendanceID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM godplan.attendances a
+		JOIN godplan.employees e ON e.user_id = a.user_id AND e.tenant_id = a.tenant_id
+		WHERE a.id = $1 AND a.tenant_id = $2 AND e.supervisor_id = $3`
+	if err := r.db.QueryRow(query, attendanceID, tenantID, supervisorID).Scan(&count); err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+// UpdateApprovalStatus records a supervisor decision. Only pending attendances can be decided.
+func (r *attendanceRepositoryImpl) UpdateApprovalStatus(tenantID uuid.UUID, attendanceID uuid.UUID, status string, approvedBy uuid.UUID, reason string) error {
+	query := `UPDATE godplan.attendances
+		SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5 AND status IN ('pending', 'pending_forced')`
+
+	result, err := r.db.Exec(query, status, approvedBy, reason, attendanceID, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrAttendanceNotPending
+	}
+	return nil
+}
+
+// GetAttendanceRecap aggregates attendances per active employee for the filter period.
+// Rejected attendances do not count as presence, and presence on holidays is not counted
+// against working days.
+func (r *attendanceRepositoryImpl) GetAttendanceRecap(tenantID uuid.UUID, filter models.AttendanceRecapFilter) ([]models.AttendanceRecap, error) {
+	query := `WITH emp AS (
+			SELECT e.id, e.user_id, COALESCE(e.employee_id, '') AS employee_code,
+				COALESCE(u.full_name, u.username) AS employee_name, COALESCE(d.name, '') AS department,
+				COALESCE(NULLIF(s.working_days, 0), NULLIF(ds.working_days, 0), tc.working_days, 0) AS working_days,
+				e.join_date
+			FROM godplan.employees e
+			JOIN godplan.users u ON u.id = e.user_id AND u.is_active = true
+			LEFT JOIN godplan.departments d ON d.id = e.department_id AND d.tenant_id = e.tenant_id
+			LEFT JOIN godplan.attendance_schedules s ON s.id = e.schedule_id
+			LEFT JOIN godplan.attendance_schedules ds ON ds.tenant_id = e.tenant_id AND ds.is_default = true
+			LEFT JOIN godplan.tenant_calendars tc ON tc.tenant_id = e.tenant_id
+			WHERE e.tenant_id = $1 AND ($4::uuid IS NULL OR e.department_id = $4)
+		)
+		SELECT emp.id, emp.user_id, emp.employee_code, emp.employee_name, emp.department, emp.working_days, emp.join_date,
+			COALESCE(agg.days_present, 0), COALESCE(agg.present_on_working_days, 0),
+			COALESCE(agg.late_count, 0), COALESCE(agg.total_late_minutes, 0),
+			COALESCE(agg.total_hours, 0), COALESCE(agg.total_overtime_minutes, 0),
+			COALESCE(agg.forced_count, 0), COALESCE(agg.pending_count, 0)
+		FROM emp
+		LEFT JOIN LATERAL (
+			SELECT
+				COUNT(DISTINCT a.attendance_date) FILTER (WHERE a.status <> 'rejected') AS days_present,
+				COUNT(DISTINCT a.attendance_date) FILTER (WHERE a.status <> 'rejected'
+					AND ((CASE WHEN emp.working_days = 0 THEN 62 ELSE emp.working_days END)
+						& (1 << EXTRACT(DOW FROM a.attendance_date)::int)) <> 0
+					AND NOT EXISTS (SELECT 1 FROM godplan.tenant_holidays h
+						WHERE h.tenant_id = a.tenant_id AND h.holiday_date = a.attendance_date)) AS present_on_working_days,
+				COUNT(*) FILTER (WHERE a.late_minutes > 0 AND a.status <> 'rejected') AS late_count,
+				SUM(a.late_minutes) FILTER (WHERE a.status <> 'rejected') AS total_late_minutes,
+				SUM(a.total_hours) FILTER (WHERE a.status <> 'rejected') AS total_hours,
+				SUM(a.overtime_minutes) FILTER (WHERE a.status <> 'rejected') AS total_overtime_minutes,
+				COUNT(*) FILTER (WHERE a.force_attendance = true) AS forced_count,
+				COUNT(*) FILTER (WHERE a.status IN ('pending', 'pending_forced')) AS pending_count
+			FROM godplan.attendances a
+			WHERE a.tenant_id = $1 AND a.user_id = emp.user_id
+			AND a.attendance_date BETWEEN $2 AND $3
+		) agg ON true
+		ORDER BY emp.department, emp.employee_name`
+
+	rows, err := r.db.Query(query, tenantID,
+		filter.StartDate.Format("2006-01-02"), filter.EndDate.Format("2006-01-02"), filter.DepartmentID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	recaps := []models.AttendanceRecap{}
+	for rows.Next() {
+		var recap models.AttendanceRecap
+		var joinDate sql.NullTime
+		if err := rows.Scan(
+			&recap.EmployeeID,
+			&recap.UserID,
+			&recap.EmployeeCode,
+			&recap.EmployeeName,
+			&recap.Department,
+			&recap.WorkingDaysMask,
+			&joinDate,
+			&recap.DaysPresent,
+			&recap.PresentOnWorkingDays,
+			&recap.LateCount,
+			&recap.TotalLateMinutes,
+			&recap.TotalHours,
+			&recap.TotalOvertimeMinutes,
+			&recap.ForcedCount,
+			&recap.PendingCount,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		if joinDate.Valid {
+			recap.JoinDate = &joinDate.Time
+		}
+		recaps = append(recaps, recap)
+	}
+
+	return recaps, nil
+}
+
+// GetLastClockEvent returns the latest clock in or clock out position of the user before
+// the given time, or nil when the user has no earlier clock events
+func (r *attendanceRepositoryImpl) GetLastClockEvent(tenantID uuid.UUID, userID uuid.UUID, before time.Time) (*models.ClockEvent, error) {
+	query := `SELECT lat, lng, event_time FROM (
+			SELECT check_in_lat AS lat, check_in_lng AS lng, check_in_time AS event_time
+			FROM godplan.attendances
+			WHERE tenant_id = $1 AND user_id = $2 AND check_in_time IS NOT NULL AND check_in_lat IS NOT NULL
+			UNION ALL
+			SELECT check_out_lat, check_out_lng, check_out_time
+			FROM godplan.attendances
+			WHERE tenant_id = $1 AND user_id = $2 AND check_out_time IS NOT NULL AND check_out_lat IS NOT NULL
+		) events
+		WHERE event_time < $3
+		ORDER BY event_time DESC
+		LIMIT 1`
+
+	var event models.ClockEvent
+	err := r.db.QueryRow(query, tenantID, userID, before).Scan(&event.Latitude, &event.Longitude, &event.Time)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return &event, nil
+}
+
+// CountReusedCoordinateDays counts earlier days where the user clocked in or out at exactly
+// the same coordinates, other than excludeDate. Real GPS fixes jitter, so exact repeats point to a
+// fixed mock position.
+func (r *attendanceRepositoryImpl) CountReusedCoordinateDays(tenantID uuid.UUID, userID uuid.UUID, lat, lng float64, excludeDate string) (int, error) {
+	query := `SELECT COUNT(DISTINCT attendance_date) FROM godplan.attendances
+		WHERE tenant_id = $1 AND user_id = $2 AND attendance_date <> $5
+		AND ((ABS(check_in_lat - $3) < 0.0000001 AND ABS(check_in_lng - $4) < 0.0000001)
+			OR (ABS(check_out_lat - $3) < 0.0000001 AND ABS(check_out_lng - $4) < 0.0000001))`
+
+	var count int
+	if err := r.db.QueryRow(query, tenantID, userID, lat, lng, excludeDate).Scan(&count); err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return count, nil
+}
+
+// GetOpenSessions returns attendances of active tenants clocked in before the given time
+// without clock out, with the schedule of their rostered shift or the one used at clock in
+func (r *attendanceRepositoryImpl) GetOpenSessions(checkedInBefore time.Time) ([]models.OpenAttendanceSession, error) {
+	query := `SELECT a.id, a.tenant_id, a.user_id, a.check_in_time, TO_CHAR(a.attendance_date, 'YYYY-MM-DD'),
+			s.id, TO_CHAR(s.start_time, 'HH24:MI'), TO_CHAR(s.end_time, 'HH24:MI'), COALESCE(a.timezone, t.timezone, '')
+		FROM godplan.attendances a
+		JOIN godplan.tenants t ON t.id = a.tenant_id AND t.is_active = true
+		LEFT JOIN godplan.shift_rosters sr ON sr.id = a.roster_id
+		LEFT JOIN godplan.attendance_schedules s ON s.id = COALESCE(sr.schedule_id, a.schedule_id)
+		WHERE a.check_out_time IS NULL AND a.check_in_time IS NOT NULL AND a.check_in_time < $1
+		ORDER BY a.check_in_time ASC`
+
+	rows, err := r.db.Query(query, checkedInBefore)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	sessions := []models.OpenAttendanceSession{}
+	for rows.Next() {
+		var session models.OpenAttendanceSession
+		var scheduleID uuid.NullUUID
+		var startTime, endTime sql.NullString
+		if err := rows.Scan(
+			&session.ID,
+			&session.TenantID,
+			&session.UserID,
+			&session.CheckInTime,
+			&session.AttendanceDate,
+			&scheduleID,
+			&startTime,
+			&endTime,
+			&session.Timezone,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		if scheduleID.Valid {
+			session.Schedule = &models.AttendanceSchedule{
+				ID:        scheduleID.UUID,
+				TenantID:  session.TenantID,
+				StartTime: startTime.String,
+				EndTime:   endTime.String,
+			}
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// AutoCloseSession records the capped check out of a forgotten session and reports whether
+// it was closed. A session the employee clocked out of in the meantime is left untouched.
+func (r *attendanceRepositoryImpl) AutoCloseSession(attendanceID uuid.UUID, checkOut time.Time, totalHours float64, breakMinutes float64, closedAt time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE godplan.attendances
+		SET check_out_time = $1, total_hours = $2, type = 'CheckOut',
+		    early_leave_minutes = 0, overtime_minutes = 0,
+		    auto_closed = true, auto_closed_at = $3, updated_at = $3
+		WHERE id = $4 AND check_out_time IS NULL`,
+		checkOut, totalHours, closedAt, attendanceID)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// GetGeofenceReplaySamples returns the office clock ins of the period that were judged by GPS alone.
+// Clock ins proven by QR, Wi-Fi or BLE and WFH clock ins do not depend on the office geofence.
+func (r *attendanceRepositoryImpl) GetGeofenceReplaySamples(tenantID uuid.UUID, startDate, endDate string) ([]models.GeofenceReplaySample, error) {
+	query := `SELECT id, check_in_lat, check_in_lng, COALESCE(check_in_accuracy, 0)
+		FROM godplan.attendances
+		WHERE tenant_id = $1 AND attendance_date BETWEEN $2 AND $3
+		AND check_in_lat IS NOT NULL AND check_in_lng IS NOT NULL
+		AND COALESCE(presence_method, $4) IN ($4, $5)
+		AND COALESCE(work_mode, $6) = $6
+		ORDER BY check_in_time ASC`
+
+	rows, err := r.db.Query(query, tenantID, startDate, endDate,
+		models.PresenceMethodGPS, models.PresenceMethodForce, models.WorkModeOffice)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	samples := []models.GeofenceReplaySample{}
+	for rows.Next() {
+		var sample models.GeofenceReplaySample
+		if err := rows.Scan(&sample.AttendanceID, &sample.Latitude, &sample.Longitude, &sample.Accuracy); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, nil
+}
+
+// FindSimilarPhoto returns the tenant selfie since sinceDate whose perceptual hash is within
+// maxDistance bits of hash, preferring the user's own selfies, or nil when none is similar.
+// excludeAttendanceID (the session being clocked out) is skipped so a selfie never matches its own attendance.
+func (r *attendanceRepositoryImpl) FindSimilarPhoto(tenantID uuid.UUID, userID uuid.UUID, excludeAttendanceID *uuid.UUID, hash int64, sinceDate string, maxDistance int) (*models.PhotoMatch, error) {
+	query := `SELECT id, user_id, distance FROM (
+			SELECT a.id, a.user_id, LEAST(
+				LENGTH(REPLACE((a.check_in_photo_hash # $3)::bit(64)::text, '0', '')),
+				LENGTH(REPLACE((a.check_out_photo_hash # $3)::bit(64)::text, '0', ''))
+			) AS distance
+			FROM godplan.attendances a
+			WHERE a.tenant_id = $1 AND a.attendance_date >= $4
+			AND ($6::uuid IS NULL OR a.id <> $6)
+			AND (a.check_in_photo_hash IS NOT NULL OR a.check_out_photo_hash IS NOT NULL)
+		) candidates
+		WHERE distance <= $5
+		ORDER BY (user_id = $2) DESC, distance ASC
+		LIMIT 1`
+
+	var match models.PhotoMatch
+	err := r.db.QueryRow(query, tenantID, userID, hash, sinceDate, maxDistance, excludeAttendanceID).
+		Scan(&match.AttendanceID, &match.UserID, &match.Distance)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return &match, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// AttendanceSyncRepository defines access methods for the offline clock event log
+type AttendanceSyncRepository interface {
+	ClaimEvent(event *models.AttendanceSyncEvent) (bool, error)
+	GetEvent(tenantID uuid.UUID, userID uuid.UUID, clientEventID string) (*models.AttendanceSyncEvent, error)
+	CompleteEvent(id uuid.UUID, recordedAt *time.Time, result string, message string, attendanceID *uuid.UUID) error
+	ReleaseEvent(id uuid.UUID) error
+}
+
+type attendanceSyncRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAttendanceSyncRepository(db *sql.DB) AttendanceSyncRepository {
+	return &attendanceSyncRepositoryImpl{db: db}
+}
+
+// ClaimEvent stores the event as processing and reports whether it was claimed.
+// false means the client event ID was already synced (or is being synced) before.
+func (r *attendanceSyncRepositoryImpl) ClaimEvent(event *models.AttendanceSyncEvent) (bool, error) {
+	query := `INSERT INTO godplan.attendance_sync_events
+		(tenant_id, user_id, client_event_id, event_type, device_recorded_at, clock_drift_seconds, result)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, user_id, client_event_id) DO NOTHING
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query,
+		event.TenantID,
+		event.UserID,
+		event.ClientEventID,
+		event.EventType,
+		event.DeviceRecordedAt,
+		event.ClockDriftSeconds,
+		models.SyncResultProcessing,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	event.Result = models.SyncResultProcessing
+	return true, nil
+}
+
+func (r *attendanceSyncRepositoryImpl) GetEvent(tenantID uuid.UUID, userID uuid.UUID, clientEventID string) (*models.AttendanceSyncEvent, error) {
+	query := `SELECT id, tenant_id, user_id, client_event_id, event_type, device_recorded_at, recorded_at,
+			clock_drift_seconds, result, COALESCE(message, ''), attendance_id, created_at
+		FROM godplan.attendance_sync_events
+		WHERE tenant_id = $1 AND user_id = $2 AND client_event_id = $3`
+
+	var event models.AttendanceSyncEvent
+	var recordedAt sql.NullTime
+	var attendanceID uuid.NullUUID
+	err := r.db.QueryRow(query, tenantID, userID, clientEventID).Scan(
+		&event.ID,
+		&event.TenantID,
+		&event.UserID,
+		&event.ClientEventID,
+		&event.EventType,
+		&event.DeviceRecordedAt,
+		&recordedAt,
+		&event.ClockDriftSeconds,
+		&event.Result,
+		&event.Message,
+		&attendanceID,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	if recordedAt.Valid {
+		event.RecordedAt = &recordedAt.Time
+	}
+	if attendanceID.Valid {
+		event.AttendanceID = &attendanceID.UUID
+	}
+	return &event, nil
+}
+
+// CompleteEvent records the final result of a claimed event
+func (r *attendanceSyncRepositoryImpl) CompleteEvent(id uuid.UUID, recordedAt *time.Time, result string, message string, attendanceID *uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE godplan.attendance_sync_events
+		SET recorded_at = $2, result = $3, message = $4, attendance_id = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id, recordedAt, result, message, attendanceID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// ReleaseEvent removes a claim after a server error so the client can retry the event
+func (r *attendanceSyncRepositoryImpl) ReleaseEvent(id uuid.UUID) error {
+	if _, err := r.db.Exec(`DELETE FROM godplan.attendance_sync_events WHERE id = $1`, id); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
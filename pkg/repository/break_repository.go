@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrNoOpenAttendance    = errors.New("no active clock in session")
+	ErrBreakAlreadyStarted = errors.New("a break is already running")
+	ErrNoActiveBreak       = errors.New("no break is running")
+)
+
+// BreakRepository defines access methods for breaks inside attendance sessions
+type BreakRepository interface {
+	GetOpenAttendanceID(tenantID uuid.UUID, userID uuid.UUID) (uuid.UUID, error)
+	CreateBreak(attendanceBreak *models.AttendanceBreak) error
+	GetBreaks(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceBreak, error)
+	GetOpenBreak(tenantID uuid.UUID, attendanceID uuid.UUID) (*models.AttendanceBreak, error)
+	EndBreak(attendanceBreak *models.AttendanceBreak) error
+	SumBreakMinutes(attendanceID uuid.UUID, until time.Time) (float64, error)
+}
+
+type breakRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewBreakRepository(db *sql.DB) BreakRepository {
+	return &breakRepositoryImpl{db: db}
+}
+
+// GetOpenAttendanceID returns the attendance the user clocked in to and did not clock out of yet
+func (r *breakRepositoryImpl) GetOpenAttendanceID(tenantID uuid.UUID, userID uuid.UUID) (uuid.UUID, error) {
+	var attendanceID uuid.UUID
+	err := r.db.QueryRow(`SELECT id FROM godplan.attendances
+		WHERE user_id = $1 AND tenant_id = $2 AND check_out_time IS NULL
+		ORDER BY created_at DESC LIMIT 1`, userID, tenantID).Scan(&attendanceID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, ErrNoOpenAttendance
+	}
+	if err != nil {
+		return uuid.Nil, utils.ErrInternalServer
+	}
+	return attendanceID, nil
+}
+
+func (r *breakRepositoryImpl) CreateBreak(attendanceBreak *models.AttendanceBreak) error {
+	query := `INSERT INTO godplan.attendance_breaks (tenant_id, attendance_id, user_id, break_type, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query,
+		attendanceBreak.TenantID,
+		attendanceBreak.AttendanceID,
+		attendanceBreak.UserID,
+		attendanceBreak.BreakType,
+		attendanceBreak.StartedAt,
+	).Scan(&attendanceBreak.ID, &attendanceBreak.CreatedAt)
+	// idx_attendance_breaks_open: hanya satu istirahat berjalan per sesi
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrBreakAlreadyStarted
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const breakColumns = `id, tenant_id, attendance_id, user_id, break_type, started_at, ended_at,
+	COALESCE(duration_minutes, 0), COALESCE(exceeded_minutes, 0), created_at`
+
+func scanBreak(row rowScanner) (*models.AttendanceBreak, error) {
+	var attendanceBreak models.AttendanceBreak
+	var endedAt sql.NullTime
+	err := row.Scan(
+		&attendanceBreak.ID,
+		&attendanceBreak.TenantID,
+		&attendanceBreak.AttendanceID,
+		&attendanceBreak.UserID,
+		&attendanceBreak.BreakType,
+		&attendanceBreak.StartedAt,
+		&endedAt,
+		&attendanceBreak.DurationMinutes,
+		&attendanceBreak.ExceededMinutes,
+		&attendanceBreak.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		attendanceBreak.EndedAt = &endedAt.Time
+	}
+	return &attendanceBreak, nil
+}
+
+func (r *breakRepositoryImpl) GetBreaks(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceBreak, error) {
+	query := `SELECT ` + breakColumns + `
+		FROM godplan.attendance_breaks
+		WHERE tenant_id = $1 AND attendance_id = $2
+		ORDER BY started_at ASC`
+
+	rows, err := r.db.Query(query, tenantID, attendanceID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	breaks := []models.AttendanceBreak{}
+	for rows.Next() {
+		attendanceBreak, err := scanBreak(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		breaks = append(breaks, *attendanceBreak)
+	}
+
+	return breaks, nil
+}
+
+func (r *breakRepositoryImpl) GetOpenBreak(tenantID uuid.UUID, attendanceID uuid.UUID) (*models.AttendanceBreak, error) {
+	query := `SELECT ` + breakColumns + `
+		FROM godplan.attendance_breaks
+		WHERE tenant_id = $1 AND attendance_id = $2 AND ended_at IS NULL`
+
+	attendanceBreak, err := scanBreak(r.db.QueryRow(query, tenantID, attendanceID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNoActiveBreak
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return attendanceBreak, nil
+}
+
+// EndBreak stores the end, duration and exceeded minutes of a running break
+func (r *breakRepositoryImpl) EndBreak(attendanceBreak *models.AttendanceBreak) error {
+	result, err := r.db.Exec(`UPDATE godplan.attendance_breaks
+		SET ended_at = $1, duration_minutes = $2, exceeded_minutes = $3
+		WHERE id = $4 AND tenant_id = $5 AND ended_at IS NULL`,
+		attendanceBreak.EndedAt, attendanceBreak.DurationMinutes, attendanceBreak.ExceededMinutes,
+		attendanceBreak.ID, attendanceBreak.TenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrNoActiveBreak
+	}
+	return nil
+}
+
+// SumBreakMinutes returns the break minutes of the attendance up to the given time. Breaks
+// are cut off at that time, e.g. at the capped check out of an auto-closed session.
+func (r *breakRepositoryImpl) SumBreakMinutes(attendanceID uuid.UUID, until time.Time) (float64, error) {
+	var minutes float64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(GREATEST(0,
+			EXTRACT(EPOCH FROM (LEAST(COALESCE(ended_at, $2), $2) - started_at)) / 60)), 0)
+		FROM godplan.attendance_breaks
+		WHERE attendance_id = $1`, attendanceID, until).Scan(&minutes)
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return minutes, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrHolidayNotFound = errors.New("holiday not found")
+	ErrHolidayExists   = errors.New("a holiday already exists on this date")
+)
+
+// CalendarRepository defines access methods for the tenant working-day calendar
+type CalendarRepository interface {
+	GetWorkingDays(tenantID uuid.UUID) (int, error)
+	UpdateWorkingDays(tenantID uuid.UUID, workingDays int) error
+	GetHolidays(tenantID uuid.UUID, startDate, endDate string) ([]models.Holiday, error)
+	GetHolidayDates(tenantID uuid.UUID, startDate, endDate string) ([]string, error)
+	CreateHoliday(holiday *models.Holiday) error
+	DeleteHoliday(tenantID uuid.UUID, id uuid.UUID) error
+	ImportHolidays(tenantID uuid.UUID, holidays []models.Holiday) (int, error)
+}
+
+type calendarRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCalendarRepository(db *sql.DB) CalendarRepository {
+	return &calendarRepositoryImpl{db: db}
+}
+
+// GetWorkingDays returns the tenant working weekdays, Monday-Friday when not configured
+func (r *calendarRepositoryImpl) GetWorkingDays(tenantID uuid.UUID) (int, error) {
+	var workingDays int
+	err := r.db.QueryRow(`SELECT working_days FROM godplan.tenant_calendars WHERE tenant_id = $1`,
+		tenantID).Scan(&workingDays)
+	if err == sql.ErrNoRows {
+		return utils.DefaultWorkingDays, nil
+	}
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return workingDays, nil
+}
+
+func (r *calendarRepositoryImpl) UpdateWorkingDays(tenantID uuid.UUID, workingDays int) error {
+	query := `INSERT INTO godplan.tenant_calendars (tenant_id, working_days)
+		VALUES ($1, $2)
+		ON CONFLICT (tenant_id) DO UPDATE SET working_days = EXCLUDED.working_days, updated_at = CURRENT_TIMESTAMP`
+
+	if _, err := r.db.Exec(query, tenantID, workingDays); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// GetHolidays returns the tenant holidays between startDate and endDate (YYYY-MM-DD, inclusive)
+func (r *calendarRepositoryImpl) GetHolidays(tenantID uuid.UUID, startDate, endDate string) ([]models.Holiday, error) {
+	query := `SELECT id, tenant_id, TO_CHAR(holiday_date, 'YYYY-MM-DD'), name, holiday_type, created_at, updated_at
+		FROM godplan.tenant_holidays
+		WHERE tenant_id = $1 AND holiday_date BETWEEN $2 AND $3
+		ORDER BY holiday_date`
+
+	rows, err := r.db.Query(query, tenantID, startDate, endDate)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	holidays := []models.Holiday{}
+	for rows.Next() {
+		var holiday models.Holiday
+		if err := rows.Scan(
+			&holiday.ID,
+			&holiday.TenantID,
+			&holiday.Date,
+			&holiday.Name,
+			&holiday.Type,
+			&holiday.CreatedAt,
+			&holiday.UpdatedAt,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+// GetHolidayDates returns only the holiday dates (YYYY-MM-DD) of the period
+func (r *calendarRepositoryImpl) GetHolidayDates(tenantID uuid.UUID, startDate, endDate string) ([]string, error) {
+	var dates []string
+	err := r.db.QueryRow(`SELECT COALESCE(ARRAY_AGG(TO_CHAR(holiday_date, 'YYYY-MM-DD')), '{}')
+		FROM godplan.tenant_holidays
+		WHERE tenant_id = $1 AND holiday_date BETWEEN $2 AND $3`,
+		tenantID, startDate, endDate).Scan(pq.Array(&dates))
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return dates, nil
+}
+
+func (r *calendarRepositoryImpl) CreateHoliday(holiday *models.Holiday) error {
+	query := `INSERT INTO godplan.tenant_holidays (tenant_id, holiday_date, name, holiday_type)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		holiday.TenantID,
+		holiday.Date,
+		holiday.Name,
+		holiday.Type,
+	).Scan(&holiday.ID, &holiday.CreatedAt, &holiday.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrHolidayExists
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *calendarRepositoryImpl) DeleteHoliday(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.tenant_holidays WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
+
+// ImportHolidays inserts the holidays in one transaction. Dates that already have a
+// holiday are skipped so a re-import never overwrites edits; returns the number inserted.
+func (r *calendarRepositoryImpl) ImportHolidays(tenantID uuid.UUID, holidays []models.Holiday) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	imported := 0
+	for _, holiday := range holidays {
+		result, err := tx.Exec(`INSERT INTO godplan.tenant_holidays (tenant_id, holiday_date, name, holiday_type)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (tenant_id, holiday_date) DO NOTHING`,
+			tenantID, holiday.Date, holiday.Name, holiday.Type)
+		if err != nil {
+			return 0, utils.ErrInternalServer
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			imported++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return imported, nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrClientVisitNotFound   = errors.New("client visit not found")
+	ErrClientVisitInProgress = errors.New("you still have a visit in progress, check out first")
+	ErrClientVisitClosed     = errors.New("this visit is already checked out")
+)
+
+// ClientVisitRepository defines access methods for client visits
+type ClientVisitRepository interface {
+	CreateVisit(visit *models.ClientVisit) error
+	GetVisitByID(tenantID uuid.UUID, id uuid.UUID) (*models.ClientVisit, error)
+	GetOpenVisit(tenantID uuid.UUID, employeeID uuid.UUID) (*models.ClientVisit, error)
+	CheckOutVisit(visit *models.ClientVisit) error
+	GetProjectVisits(tenantID uuid.UUID, projectID uuid.UUID) ([]models.ClientVisit, error)
+	GetEmployeeVisits(tenantID uuid.UUID, employeeID uuid.UUID, date string) ([]models.ClientVisit, error)
+	HasVisitedProject(tenantID uuid.UUID, employeeID uuid.UUID, projectID uuid.UUID) (bool, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+}
+
+type clientVisitRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewClientVisitRepository(db *sql.DB) ClientVisitRepository {
+	return &clientVisitRepositoryImpl{db: db}
+}
+
+func (r *clientVisitRepositoryImpl) CreateVisit(visit *models.ClientVisit) error {
+	query := `INSERT INTO godplan.client_visits
+		(tenant_id, employee_id, user_id, project_id, client, visit_date, check_in_time,
+		 check_in_lat, check_in_lng, check_in_accuracy, check_in_photo, check_in_notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''))
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		visit.TenantID,
+		visit.EmployeeID,
+		visit.UserID,
+		visit.ProjectID,
+		visit.Client,
+		visit.VisitDate,
+		visit.CheckInTime,
+		visit.CheckInLatitude,
+		visit.CheckInLongitude,
+		visit.CheckInAccuracy,
+		visit.CheckInPhoto,
+		visit.CheckInNotes,
+	).Scan(&visit.ID, &visit.CreatedAt, &visit.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrClientVisitInProgress
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	visit.Status = models.VisitStatusOngoing
+	return nil
+}
+
+const clientVisitQuery = `SELECT v.id, v.tenant_id, v.employee_id, v.user_id, COALESCE(u.full_name, u.username),
+		v.project_id, COALESCE(p.title, ''), v.client, TO_CHAR(v.visit_date, 'YYYY-MM-DD'),
+		v.check_in_time, v.check_in_lat, v.check_in_lng, COALESCE(v.check_in_accuracy, 0),
+		COALESCE(v.check_in_photo, ''), COALESCE(v.check_in_notes, ''),
+		v.check_out_time, v.check_out_lat, v.check_out_lng, v.check_out_accuracy,
+		COALESCE(v.check_out_photo, ''), COALESCE(v.check_out_notes, ''), v.check_out_distance,
+		COALESCE(v.duration_minutes, 0), v.created_at, v.updated_at
+	FROM godplan.client_visits v
+	JOIN godplan.users u ON u.id = v.user_id
+	LEFT JOIN godplan.projects p ON p.id = v.project_id`
+
+func scanClientVisit(row rowScanner) (*models.ClientVisit, error) {
+	var visit models.ClientVisit
+	var checkOutTime sql.NullTime
+	var checkOutLat, checkOutLng, checkOutAccuracy, checkOutDistance sql.NullFloat64
+	err := row.Scan(
+		&visit.ID,
+		&visit.TenantID,
+		&visit.EmployeeID,
+		&visit.UserID,
+		&visit.EmployeeName,
+		&visit.ProjectID,
+		&visit.ProjectTitle,
+		&visit.Client,
+		&visit.VisitDate,
+		&visit.CheckInTime,
+		&visit.CheckInLatitude,
+		&visit.CheckInLongitude,
+		&visit.CheckInAccuracy,
+		&visit.CheckInPhoto,
+		&visit.CheckInNotes,
+		&checkOutTime,
+		&checkOutLat,
+		&checkOutLng,
+		&checkOutAccuracy,
+		&visit.CheckOutPhoto,
+		&visit.CheckOutNotes,
+		&checkOutDistance,
+		&visit.DurationMinutes,
+		&visit.CreatedAt,
+		&visit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	visit.CheckOutTime = nullTimePtr(checkOutTime)
+	visit.CheckOutLatitude = nullFloatPtr(checkOutLat)
+	visit.CheckOutLongitude = nullFloatPtr(checkOutLng)
+	visit.CheckOutAccuracy = nullFloatPtr(checkOutAccuracy)
+	visit.CheckOutDistance = nullFloatPtr(checkOutDistance)
+	visit.Status = models.VisitStatusOngoing
+	if visit.CheckOutTime != nil {
+		visit.Status = models.VisitStatusCompleted
+	}
+	return &visit, nil
+}
+
+func nullFloatPtr(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+func (r *clientVisitRepositoryImpl) queryVisits(query string, args ...interface{}) ([]models.ClientVisit, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	visits := []models.ClientVisit{}
+	for rows.Next() {
+		visit, err := scanClientVisit(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		visits = append(visits, *visit)
+	}
+
+	return visits, nil
+}
+
+func (r *clientVisitRepositoryImpl) GetVisitByID(tenantID uuid.UUID, id uuid.UUID) (*models.ClientVisit, error) {
+	query := clientVisitQuery + ` WHERE v.id = $1 AND v.tenant_id = $2`
+
+	visit, err := scanClientVisit(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrClientVisitNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return visit, nil
+}
+
+// GetOpenVisit returns the visit the employee has not checked out of yet
+func (r *clientVisitRepositoryImpl) GetOpenVisit(tenantID uuid.UUID, employeeID uuid.UUID) (*models.ClientVisit, error) {
+	query := clientVisitQuery + ` WHERE v.tenant_id = $1 AND v.employee_id = $2 AND v.check_out_time IS NULL`
+
+	visit, err := scanClientVisit(r.db.QueryRow(query, tenantID, employeeID))
+	if err == sql.ErrNoRows {
+		return nil, ErrClientVisitNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return visit, nil
+}
+
+func (r *clientVisitRepositoryImpl) CheckOutVisit(visit *models.ClientVisit) error {
+	result, err := r.db.Exec(`UPDATE godplan.client_visits
+		SET check_out_time = $1, check_out_lat = $2, check_out_lng = $3, check_out_accuracy = $4,
+		    check_out_photo = NULLIF($5, ''), check_out_notes = NULLIF($6, ''), check_out_distance = $7,
+		    duration_minutes = $8, updated_at = $1
+		WHERE id = $9 AND tenant_id = $10 AND check_out_time IS NULL`,
+		visit.CheckOutTime, visit.CheckOutLatitude, visit.CheckOutLongitude, visit.CheckOutAccuracy,
+		visit.CheckOutPhoto, visit.CheckOutNotes, visit.CheckOutDistance,
+		visit.DurationMinutes, visit.ID, visit.TenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrClientVisitClosed
+	}
+	visit.Status = models.VisitStatusCompleted
+	return nil
+}
+
+// GetProjectVisits returns the visit history of a CRM deal, newest first
+func (r *clientVisitRepositoryImpl) GetProjectVisits(tenantID uuid.UUID, projectID uuid.UUID) ([]models.ClientVisit, error) {
+	query := clientVisitQuery + `
+		WHERE v.tenant_id = $1 AND v.project_id = $2
+		ORDER BY v.check_in_time DESC`
+	return r.queryVisits(query, tenantID, projectID)
+}
+
+// GetEmployeeVisits returns the visits of the employee on the date in check-in order
+func (r *clientVisitRepositoryImpl) GetEmployeeVisits(tenantID uuid.UUID, employeeID uuid.UUID, date string) ([]models.ClientVisit, error) {
+	query := clientVisitQuery + `
+		WHERE v.tenant_id = $1 AND v.employee_id = $2 AND v.visit_date = $3
+		ORDER BY v.check_in_time ASC`
+	return r.queryVisits(query, tenantID, employeeID, date)
+}
+
+func (r *clientVisitRepositoryImpl) HasVisitedProject(tenantID uuid.UUID, employeeID uuid.UUID, projectID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (
+			SELECT 1 FROM godplan.client_visits WHERE tenant_id = $1 AND employee_id = $2 AND project_id = $3
+		)`, tenantID, employeeID, projectID).Scan(&exists)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return exists, nil
+}
+
+func (r *clientVisitRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrKioskNotFound = errors.New("kiosk not found")
+
+// KioskRepository defines access methods for QR code attendance kiosks
+type KioskRepository interface {
+	CreateKiosk(kiosk *models.AttendanceKiosk, tokenHash string) error
+	GetKiosks(tenantID uuid.UUID) ([]models.AttendanceKiosk, error)
+	GetKioskByID(id uuid.UUID) (*models.AttendanceKiosk, error)
+	GetKioskByTokenHash(tokenHash string) (*models.AttendanceKiosk, error)
+	UpdateKioskToken(tenantID uuid.UUID, id uuid.UUID, secret string, tokenHash string) (*models.AttendanceKiosk, error)
+	TouchKiosk(id uuid.UUID) error
+	DeleteKiosk(tenantID uuid.UUID, id uuid.UUID) error
+}
+
+type kioskRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewKioskRepository(db *sql.DB) KioskRepository {
+	return &kioskRepositoryImpl{db: db}
+}
+
+const kioskColumns = `k.id, k.tenant_id, k.office_location_id, ol.name, k.name, k.secret,
+	k.last_seen_at, k.created_at, k.updated_at`
+
+const kioskFrom = ` FROM godplan.attendance_kiosks k
+	JOIN godplan.office_locations ol ON ol.id = k.office_location_id`
+
+func scanKiosk(row rowScanner) (*models.AttendanceKiosk, error) {
+	var kiosk models.AttendanceKiosk
+	var lastSeenAt sql.NullTime
+	err := row.Scan(
+		&kiosk.ID,
+		&kiosk.TenantID,
+		&kiosk.OfficeLocationID,
+		&kiosk.OfficeName,
+		&kiosk.Name,
+		&kiosk.Secret,
+		&lastSeenAt,
+		&kiosk.CreatedAt,
+		&kiosk.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastSeenAt.Valid {
+		kiosk.LastSeenAt = &lastSeenAt.Time
+	}
+	return &kiosk, nil
+}
+
+func (r *kioskRepositoryImpl) CreateKiosk(kiosk *models.AttendanceKiosk, tokenHash string) error {
+	query := `INSERT INTO godplan.attendance_kiosks
+		(tenant_id, office_location_id, name, secret, token_hash)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		kiosk.TenantID,
+		kiosk.OfficeLocationID,
+		kiosk.Name,
+		kiosk.Secret,
+		tokenHash,
+	).Scan(&kiosk.ID, &kiosk.CreatedAt, &kiosk.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *kioskRepositoryImpl) GetKiosks(tenantID uuid.UUID) ([]models.AttendanceKiosk, error) {
+	query := `SELECT ` + kioskColumns + kioskFrom + `
+		WHERE k.tenant_id = $1
+		ORDER BY ol.name ASC, k.name ASC`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	kiosks := []models.AttendanceKiosk{}
+	for rows.Next() {
+		kiosk, err := scanKiosk(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		kiosks = append(kiosks, *kiosk)
+	}
+
+	return kiosks, nil
+}
+
+// GetKioskByID looks up a kiosk of an active office across tenants,
+// callers must compare the tenant themselves
+func (r *kioskRepositoryImpl) GetKioskByID(id uuid.UUID) (*models.AttendanceKiosk, error) {
+	query := `SELECT ` + kioskColumns + kioskFrom + `
+		WHERE k.id = $1 AND ol.is_active = true`
+	return r.getKiosk(query, id)
+}
+
+func (r *kioskRepositoryImpl) GetKioskByTokenHash(tokenHash string) (*models.AttendanceKiosk, error) {
+	query := `SELECT ` + kioskColumns + kioskFrom + `
+		WHERE k.token_hash = $1 AND ol.is_active = true`
+	return r.getKiosk(query, tokenHash)
+}
+
+func (r *kioskRepositoryImpl) getKiosk(query string, args ...interface{}) (*models.AttendanceKiosk, error) {
+	kiosk, err := scanKiosk(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrKioskNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return kiosk, nil
+}
+
+// UpdateKioskToken replaces the kiosk token and QR signing secret, invalidating the old device
+func (r *kioskRepositoryImpl) UpdateKioskToken(tenantID uuid.UUID, id uuid.UUID, secret string, tokenHash string) (*models.AttendanceKiosk, error) {
+	result, err := r.db.Exec(`UPDATE godplan.attendance_kiosks
+		SET secret = $1, token_hash = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND tenant_id = $4`, secret, tokenHash, id, tenantID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, ErrKioskNotFound
+	}
+
+	query := `SELECT ` + kioskColumns + kioskFrom + `
+		WHERE k.id = $1 AND k.tenant_id = $2`
+	return r.getKiosk(query, id, tenantID)
+}
+
+func (r *kioskRepositoryImpl) TouchKiosk(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE godplan.attendance_kiosks SET last_seen_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *kioskRepositoryImpl) DeleteKiosk(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.attendance_kiosks WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrKioskNotFound
+	}
+	return nil
+}
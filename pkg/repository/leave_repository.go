@@ -0,0 +1,533 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrLeaveTypeNotFound      = errors.New("leave type not found")
+	ErrLeaveTypeExists        = errors.New("a leave type with this code already exists")
+	ErrLeaveTypeInUse         = errors.New("leave type is used by leave requests, deactivate it instead")
+	ErrLeaveBalanceNotFound   = errors.New("leave balance not found")
+	ErrLeaveRequestNotFound   = errors.New("leave request not found")
+	ErrLeaveRequestNotPending = errors.New("leave request is not pending")
+)
+
+// defaultLeaveTypes di-seed untuk tenant yang belum punya jenis cuti (lihat migration 016)
+var defaultLeaveTypes = []models.LeaveType{
+	{Code: "annual", Name: "Cuti Tahunan", IsPaid: true, TracksBalance: true, AnnualQuota: 12},
+	{Code: "sick", Name: "Sakit", IsPaid: true, RequiresAttachment: true},
+	{Code: "permission", Name: "Izin", IsPaid: true},
+	{Code: "unpaid", Name: "Cuti Tidak Dibayar"},
+}
+
+// LeaveRepository defines access methods for leave types, balances and requests
+type LeaveRepository interface {
+	CreateLeaveType(leaveType *models.LeaveType) error
+	GetLeaveTypeByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveType, error)
+	GetLeaveTypes(tenantID uuid.UUID) ([]models.LeaveType, error)
+	UpdateLeaveType(leaveType *models.LeaveType) error
+	DeleteLeaveType(tenantID uuid.UUID, id uuid.UUID) error
+	SeedDefaultLeaveTypes(tenantID uuid.UUID) error
+
+	GetAccrualCandidates(tenantID uuid.UUID, year int, employeeID *uuid.UUID) ([]models.LeaveAccrualCandidate, error)
+	CreateLeaveBalance(tenantID uuid.UUID, employeeID uuid.UUID, leaveTypeID uuid.UUID, year int, entitled int, carriedOver int) (bool, error)
+	GetLeaveBalances(tenantID uuid.UUID, employeeID uuid.UUID, year int) ([]models.LeaveBalance, error)
+	GetLeaveBalance(tenantID uuid.UUID, employeeID uuid.UUID, leaveTypeID uuid.UUID, year int) (*models.LeaveBalance, error)
+
+	CreateLeaveRequest(request *models.LeaveRequest) error
+	GetLeaveRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveRequest, error)
+	GetLeaveRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.LeaveRequest, error)
+	GetPendingLeaveRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.LeaveRequest, error)
+	HasOverlappingLeave(tenantID uuid.UUID, employeeID uuid.UUID, startDate string, endDate string) (bool, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	DecideLeaveRequest(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string, deductBalance bool) error
+	CancelLeaveRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error
+}
+
+type leaveRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewLeaveRepository(db *sql.DB) LeaveRepository {
+	return &leaveRepositoryImpl{db: db}
+}
+
+const leaveTypeColumns = `id, tenant_id, code, name, COALESCE(is_paid, true), COALESCE(tracks_balance, false),
+	COALESCE(annual_quota, 0), COALESCE(max_carry_over, 0), COALESCE(requires_attachment, false),
+	COALESCE(is_active, true), created_at, updated_at`
+
+func scanLeaveType(row rowScanner) (*models.LeaveType, error) {
+	var leaveType models.LeaveType
+	err := row.Scan(
+		&leaveType.ID,
+		&leaveType.TenantID,
+		&leaveType.Code,
+		&leaveType.Name,
+		&leaveType.IsPaid,
+		&leaveType.TracksBalance,
+		&leaveType.AnnualQuota,
+		&leaveType.MaxCarryOver,
+		&leaveType.RequiresAttachment,
+		&leaveType.IsActive,
+		&leaveType.CreatedAt,
+		&leaveType.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &leaveType, nil
+}
+
+func (r *leaveRepositoryImpl) CreateLeaveType(leaveType *models.LeaveType) error {
+	query := `INSERT INTO godplan.leave_types
+		(tenant_id, code, name, is_paid, tracks_balance, annual_quota, max_carry_over, requires_attachment, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		leaveType.TenantID,
+		leaveType.Code,
+		leaveType.Name,
+		leaveType.IsPaid,
+		leaveType.TracksBalance,
+		leaveType.AnnualQuota,
+		leaveType.MaxCarryOver,
+		leaveType.RequiresAttachment,
+		leaveType.IsActive,
+	).Scan(&leaveType.ID, &leaveType.CreatedAt, &leaveType.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrLeaveTypeExists
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveTypeByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveType, error) {
+	query := `SELECT ` + leaveTypeColumns + `
+		FROM godplan.leave_types WHERE id = $1 AND tenant_id = $2`
+
+	leaveType, err := scanLeaveType(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrLeaveTypeNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return leaveType, nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveTypes(tenantID uuid.UUID) ([]models.LeaveType, error) {
+	query := `SELECT ` + leaveTypeColumns + `
+		FROM godplan.leave_types
+		WHERE tenant_id = $1
+		ORDER BY name ASC`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	leaveTypes := []models.LeaveType{}
+	for rows.Next() {
+		leaveType, err := scanLeaveType(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		leaveTypes = append(leaveTypes, *leaveType)
+	}
+
+	return leaveTypes, nil
+}
+
+func (r *leaveRepositoryImpl) UpdateLeaveType(leaveType *models.LeaveType) error {
+	query := `UPDATE godplan.leave_types
+		SET code = $1, name = $2, is_paid = $3, tracks_balance = $4, annual_quota = $5,
+		    max_carry_over = $6, requires_attachment = $7, is_active = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $9 AND tenant_id = $10
+		RETURNING updated_at`
+
+	err := r.db.QueryRow(query,
+		leaveType.Code,
+		leaveType.Name,
+		leaveType.IsPaid,
+		leaveType.TracksBalance,
+		leaveType.AnnualQuota,
+		leaveType.MaxCarryOver,
+		leaveType.RequiresAttachment,
+		leaveType.IsActive,
+		leaveType.ID,
+		leaveType.TenantID,
+	).Scan(&leaveType.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrLeaveTypeNotFound
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrLeaveTypeExists
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *leaveRepositoryImpl) DeleteLeaveType(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.leave_types WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return ErrLeaveTypeInUse
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrLeaveTypeNotFound
+	}
+	return nil
+}
+
+func (r *leaveRepositoryImpl) SeedDefaultLeaveTypes(tenantID uuid.UUID) error {
+	query := `INSERT INTO godplan.leave_types
+		(tenant_id, code, name, is_paid, tracks_balance, annual_quota, max_carry_over, requires_attachment)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, code) DO NOTHING`
+
+	for _, leaveType := range defaultLeaveTypes {
+		_, err := r.db.Exec(query, tenantID, leaveType.Code, leaveType.Name, leaveType.IsPaid,
+			leaveType.TracksBalance, leaveType.AnnualQuota, leaveType.MaxCarryOver, leaveType.RequiresAttachment)
+		if err != nil {
+			return utils.ErrInternalServer
+		}
+	}
+	return nil
+}
+
+// GetAccrualCandidates returns every active employee and active balance-tracked leave type
+// that has no balance yet for the year, together with the unused days of the previous year
+func (r *leaveRepositoryImpl) GetAccrualCandidates(tenantID uuid.UUID, year int, employeeID *uuid.UUID) ([]models.LeaveAccrualCandidate, error) {
+	query := `SELECT e.id, lt.id, COALESCE(lt.annual_quota, 0), COALESCE(lt.max_carry_over, 0), e.join_date,
+			COALESCE(prev.entitled + prev.carried_over - prev.used, 0)
+		FROM godplan.employees e
+		JOIN godplan.users u ON u.id = e.user_id AND u.is_active = true
+		JOIN godplan.leave_types lt ON lt.tenant_id = e.tenant_id AND lt.tracks_balance = true AND lt.is_active = true
+		LEFT JOIN godplan.leave_balances prev
+			ON prev.employee_id = e.id AND prev.leave_type_id = lt.id AND prev.year = $2 - 1
+		WHERE e.tenant_id = $1 AND ($3::uuid IS NULL OR e.id = $3)
+		AND NOT EXISTS (
+			SELECT 1 FROM godplan.leave_balances b
+			WHERE b.employee_id = e.id AND b.leave_type_id = lt.id AND b.year = $2
+		)`
+
+	rows, err := r.db.Query(query, tenantID, year, employeeID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	candidates := []models.LeaveAccrualCandidate{}
+	for rows.Next() {
+		var candidate models.LeaveAccrualCandidate
+		var joinDate sql.NullTime
+		if err := rows.Scan(
+			&candidate.EmployeeID,
+			&candidate.LeaveTypeID,
+			&candidate.AnnualQuota,
+			&candidate.MaxCarryOver,
+			&joinDate,
+			&candidate.PreviousRemaining,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		if joinDate.Valid {
+			candidate.JoinDate = &joinDate.Time
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// CreateLeaveBalance inserts a yearly balance and reports whether it was created.
+// An existing balance is left untouched so accrual can safely run more than once.
+func (r *leaveRepositoryImpl) CreateLeaveBalance(tenantID uuid.UUID, employeeID uuid.UUID, leaveTypeID uuid.UUID, year int, entitled int, carriedOver int) (bool, error) {
+	result, err := r.db.Exec(`INSERT INTO godplan.leave_balances
+		(tenant_id, employee_id, leave_type_id, year, entitled, carried_over)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (employee_id, leave_type_id, year) DO NOTHING`,
+		tenantID, employeeID, leaveTypeID, year, entitled, carriedOver)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+const leaveBalanceQuery = `SELECT b.employee_id, b.leave_type_id, lt.code, lt.name, b.year,
+		b.entitled, b.carried_over, b.used,
+		COALESCE((
+			SELECT SUM(lr.days) FROM godplan.leave_requests lr
+			WHERE lr.employee_id = b.employee_id AND lr.leave_type_id = b.leave_type_id
+			AND lr.status = 'pending' AND EXTRACT(YEAR FROM lr.start_date) = b.year
+		), 0)
+	FROM godplan.leave_balances b
+	JOIN godplan.leave_types lt ON lt.id = b.leave_type_id`
+
+func scanLeaveBalance(row rowScanner) (*models.LeaveBalance, error) {
+	var balance models.LeaveBalance
+	err := row.Scan(
+		&balance.EmployeeID,
+		&balance.LeaveTypeID,
+		&balance.LeaveTypeCode,
+		&balance.LeaveTypeName,
+		&balance.Year,
+		&balance.Entitled,
+		&balance.CarriedOver,
+		&balance.Used,
+		&balance.Pending,
+	)
+	if err != nil {
+		return nil, err
+	}
+	balance.Remaining = balance.Entitled + balance.CarriedOver - balance.Used - balance.Pending
+	return &balance, nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveBalances(tenantID uuid.UUID, employeeID uuid.UUID, year int) ([]models.LeaveBalance, error) {
+	query := leaveBalanceQuery + `
+		WHERE b.tenant_id = $1 AND b.employee_id = $2 AND b.year = $3
+		ORDER BY lt.name ASC`
+
+	rows, err := r.db.Query(query, tenantID, employeeID, year)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	balances := []models.LeaveBalance{}
+	for rows.Next() {
+		balance, err := scanLeaveBalance(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		balances = append(balances, *balance)
+	}
+
+	return balances, nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveBalance(tenantID uuid.UUID, employeeID uuid.UUID, leaveTypeID uuid.UUID, year int) (*models.LeaveBalance, error) {
+	query := leaveBalanceQuery + `
+		WHERE b.tenant_id = $1 AND b.employee_id = $2 AND b.leave_type_id = $3 AND b.year = $4`
+
+	balance, err := scanLeaveBalance(r.db.QueryRow(query, tenantID, employeeID, leaveTypeID, year))
+	if err == sql.ErrNoRows {
+		return nil, ErrLeaveBalanceNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return balance, nil
+}
+
+func (r *leaveRepositoryImpl) CreateLeaveRequest(request *models.LeaveRequest) error {
+	query := `INSERT INTO godplan.leave_requests
+		(tenant_id, employee_id, user_id, leave_type_id, start_date, end_date, days, reason, attachment_key, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		request.TenantID,
+		request.EmployeeID,
+		request.UserID,
+		request.LeaveTypeID,
+		request.StartDate,
+		request.EndDate,
+		request.Days,
+		request.Reason,
+		request.AttachmentKey,
+		request.Status,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const leaveRequestQuery = `SELECT lr.id, lr.tenant_id, lr.employee_id, lr.user_id, COALESCE(u.full_name, u.username),
+		lr.leave_type_id, lt.code, lt.name, TO_CHAR(lr.start_date, 'YYYY-MM-DD'), TO_CHAR(lr.end_date, 'YYYY-MM-DD'),
+		lr.days, COALESCE(lr.reason, ''), COALESCE(lr.attachment_key, ''), lr.status,
+		lr.approved_by, lr.approved_at, COALESCE(lr.rejection_reason, ''), lr.created_at, lr.updated_at
+	FROM godplan.leave_requests lr
+	JOIN godplan.users u ON u.id = lr.user_id
+	JOIN godplan.leave_types lt ON lt.id = lr.leave_type_id`
+
+func scanLeaveRequest(row rowScanner) (*models.LeaveRequest, error) {
+	var request models.LeaveRequest
+	var approvedAt sql.NullTime
+	err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.EmployeeID,
+		&request.UserID,
+		&request.EmployeeName,
+		&request.LeaveTypeID,
+		&request.LeaveTypeCode,
+		&request.LeaveTypeName,
+		&request.StartDate,
+		&request.EndDate,
+		&request.Days,
+		&request.Reason,
+		&request.AttachmentKey,
+		&request.Status,
+		&request.ApprovedBy,
+		&approvedAt,
+		&request.RejectionReason,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if approvedAt.Valid {
+		request.ApprovedAt = &approvedAt.Time
+	}
+	return &request, nil
+}
+
+func (r *leaveRepositoryImpl) queryLeaveRequests(query string, args ...interface{}) ([]models.LeaveRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	requests := []models.LeaveRequest{}
+	for rows.Next() {
+		request, err := scanLeaveRequest(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveRequest, error) {
+	query := leaveRequestQuery + ` WHERE lr.id = $1 AND lr.tenant_id = $2`
+
+	request, err := scanLeaveRequest(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrLeaveRequestNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return request, nil
+}
+
+func (r *leaveRepositoryImpl) GetLeaveRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.LeaveRequest, error) {
+	query := leaveRequestQuery + `
+		WHERE lr.tenant_id = $1 AND lr.employee_id = $2
+		ORDER BY lr.start_date DESC`
+	return r.queryLeaveRequests(query, tenantID, employeeID)
+}
+
+// GetPendingLeaveRequests returns pending requests of the tenant, or only those of the
+// supervisor's direct reports when supervisorID is set
+func (r *leaveRepositoryImpl) GetPendingLeaveRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.LeaveRequest, error) {
+	query := leaveRequestQuery + `
+		JOIN godplan.employees e ON e.id = lr.employee_id
+		WHERE lr.tenant_id = $1 AND lr.status = 'pending'
+		AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY lr.start_date ASC`
+	return r.queryLeaveRequests(query, tenantID, supervisorID)
+}
+
+// HasOverlappingLeave reports whether a pending or approved request already covers part of the period
+func (r *leaveRepositoryImpl) HasOverlappingLeave(tenantID uuid.UUID, employeeID uuid.UUID, startDate string, endDate string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (
+			SELECT 1 FROM godplan.leave_requests
+			WHERE tenant_id = $1 AND employee_id = $2 AND status IN ('pending', 'approved')
+			AND start_date <= $4 AND end_date >= $3
+		)`, tenantID, employeeID, startDate, endDate).Scan(&exists)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return exists, nil
+}
+
+func (r *leaveRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+// DecideLeaveRequest records a decision on a pending request. Approving a balance-tracked
+// leave also adds its days to the used balance of the request year, in the same transaction.
+func (r *leaveRepositoryImpl) DecideLeaveRequest(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string, deductBalance bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE godplan.leave_requests
+		SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5 AND status = 'pending'`,
+		status, approvedBy, reason, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrLeaveRequestNotPending
+	}
+
+	if deductBalance {
+		result, err := tx.Exec(`UPDATE godplan.leave_balances b
+			SET used = b.used + lr.days, updated_at = CURRENT_TIMESTAMP
+			FROM godplan.leave_requests lr
+			WHERE lr.id = $1 AND b.employee_id = lr.employee_id AND b.leave_type_id = lr.leave_type_id
+			AND b.year = EXTRACT(YEAR FROM lr.start_date)`, id)
+		if err != nil {
+			return utils.ErrInternalServer
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return ErrLeaveBalanceNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// CancelLeaveRequest lets the employee withdraw their own pending request
+func (r *leaveRepositoryImpl) CancelLeaveRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.leave_requests
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND employee_id = $3 AND status = 'pending'`,
+		id, tenantID, employeeID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrLeaveRequestNotPending
+	}
+	return nil
+}
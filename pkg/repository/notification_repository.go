@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationRepository defines access methods for in-app user notifications
+type NotificationRepository interface {
+	CreateNotification(notification *models.Notification) error
+	GetNotifications(tenantID uuid.UUID, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnread(tenantID uuid.UUID, userID uuid.UUID) (int, error)
+	MarkRead(tenantID uuid.UUID, userID uuid.UUID, id uuid.UUID) error
+	MarkAllRead(tenantID uuid.UUID, userID uuid.UUID) (int64, error)
+}
+
+type notificationRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepositoryImpl{db: db}
+}
+
+func (r *notificationRepositoryImpl) CreateNotification(notification *models.Notification) error {
+	query := `INSERT INTO godplan.notifications (tenant_id, user_id, type, title, message, reference_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query,
+		notification.TenantID,
+		notification.UserID,
+		notification.Type,
+		notification.Title,
+		notification.Message,
+		notification.ReferenceID,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// GetNotifications returns the newest notifications of the user first
+func (r *notificationRepositoryImpl) GetNotifications(tenantID uuid.UUID, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := `SELECT id, tenant_id, user_id, type, title, message, reference_id, read_at, created_at
+		FROM godplan.notifications
+		WHERE tenant_id = $1 AND user_id = $2 AND (NOT $3 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $4`
+
+	rows, err := r.db.Query(query, tenantID, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		var referenceID uuid.NullUUID
+		var readAt sql.NullTime
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.TenantID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Message,
+			&referenceID,
+			&readAt,
+			&notification.CreatedAt,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		if referenceID.Valid {
+			notification.ReferenceID = &referenceID.UUID
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepositoryImpl) CountUnread(tenantID uuid.UUID, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.notifications
+		WHERE tenant_id = $1 AND user_id = $2 AND read_at IS NULL`, tenantID, userID).Scan(&count)
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return count, nil
+}
+
+// MarkRead marks one notification of the user as read. Marking it again is a no-op.
+func (r *notificationRepositoryImpl) MarkRead(tenantID uuid.UUID, userID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND tenant_id = $2 AND user_id = $3`, id, tenantID, userID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepositoryImpl) MarkAllRead(tenantID uuid.UUID, userID uuid.UUID) (int64, error) {
+	result, err := r.db.Exec(`UPDATE godplan.notifications SET read_at = CURRENT_TIMESTAMP
+		WHERE tenant_id = $1 AND user_id = $2 AND read_at IS NULL`, tenantID, userID)
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	affected, _ := result.RowsAffected()
+	return affected, nil
+}
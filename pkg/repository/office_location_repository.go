@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrOfficeLocationNotFound  = errors.New("office location not found")
+	ErrPresenceSignalNotFound  = errors.New("presence signal not found")
+	ErrPresenceSignalDuplicate = errors.New("presence signal is already registered for this tenant")
+)
+
+// OfficeLocationRepository defines access methods for tenant office geofences
+type OfficeLocationRepository interface {
+	CreateLocation(location *models.OfficeLocation) error
+	GetLocationByID(tenantID uuid.UUID, id uuid.UUID) (*models.OfficeLocation, error)
+	GetLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error)
+	GetActiveLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error)
+	UpdateLocation(location *models.OfficeLocation) error
+	DeleteLocation(tenantID uuid.UUID, id uuid.UUID) error
+
+	CreateSignal(signal *models.OfficePresenceSignal) error
+	GetSignals(tenantID uuid.UUID, officeLocationID uuid.UUID) ([]models.OfficePresenceSignal, error)
+	DeleteSignal(tenantID uuid.UUID, officeLocationID uuid.UUID, id uuid.UUID) error
+	FindPresenceMatch(tenantID uuid.UUID, bssids []string, beaconIDs []string) (*models.PresenceMatch, error)
+}
+
+type officeLocationRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOfficeLocationRepository(db *sql.DB) OfficeLocationRepository {
+	return &officeLocationRepositoryImpl{db: db}
+}
+
+const officeLocationColumns = `id, tenant_id, name, latitude, longitude, radius, boundary, COALESCE(address, ''), COALESCE(timezone, ''), is_active, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOfficeLocation(row rowScanner) (*models.OfficeLocation, error) {
+	var location models.OfficeLocation
+	var boundary []byte
+	err := row.Scan(
+		&location.ID,
+		&location.TenantID,
+		&location.Name,
+		&location.Latitude,
+		&location.Longitude,
+		&location.Radius,
+		&boundary,
+		&location.Address,
+		&location.Timezone,
+		&location.IsActive,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(boundary) > 0 {
+		location.Boundary = &models.GeoJSONPolygon{}
+		if err := json.Unmarshal(boundary, location.Boundary); err != nil {
+			return nil, err
+		}
+	}
+	return &location, nil
+}
+
+// marshalBoundary converts the polygon into a JSONB parameter (NULL for circular geofences)
+func marshalBoundary(boundary *models.GeoJSONPolygon) (interface{}, error) {
+	if boundary == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(boundary)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (r *officeLocationRepositoryImpl) CreateLocation(location *models.OfficeLocation) error {
+	boundary, err := marshalBoundary(location.Boundary)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	query := `INSERT INTO godplan.office_locations
+		(tenant_id, name, latitude, longitude, radius, boundary, address, timezone, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING id, created_at, updated_at`
+
+	err = r.db.QueryRow(query,
+		location.TenantID,
+		location.Name,
+		location.Latitude,
+		location.Longitude,
+		location.Radius,
+		boundary,
+		location.Address,
+		location.Timezone,
+		location.IsActive,
+	).Scan(&location.ID, &location.CreatedAt, &location.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *officeLocationRepositoryImpl) GetLocationByID(tenantID uuid.UUID, id uuid.UUID) (*models.OfficeLocation, error) {
+	query := `SELECT ` + officeLocationColumns + `
+		FROM godplan.office_locations WHERE id = $1 AND tenant_id = $2`
+
+	location, err := scanOfficeLocation(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrOfficeLocationNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return location, nil
+}
+
+func (r *officeLocationRepositoryImpl) GetLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error) {
+	query := `SELECT ` + officeLocationColumns + `
+		FROM godplan.office_locations
+		WHERE tenant_id = $1
+		ORDER BY name ASC`
+	return r.queryLocations(query, tenantID)
+}
+
+func (r *officeLocationRepositoryImpl) GetActiveLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error) {
+	query := `SELECT ` + officeLocationColumns + `
+		FROM godplan.office_locations
+		WHERE tenant_id = $1 AND is_active = true
+		ORDER BY name ASC`
+	return r.queryLocations(query, tenantID)
+}
+
+func (r *officeLocationRepositoryImpl) queryLocations(query string, args ...interface{}) ([]models.OfficeLocation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	locations := []models.OfficeLocation{}
+	for rows.Next() {
+		location, err := scanOfficeLocation(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		locations = append(locations, *location)
+	}
+
+	return locations, nil
+}
+
+func (r *officeLocationRepositoryImpl) UpdateLocation(location *models.OfficeLocation) error {
+	boundary, err := marshalBoundary(location.Boundary)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	query := `UPDATE godplan.office_locations
+		SET name = $1, latitude = $2, longitude = $3, radius = $4, boundary = $5, address = $6,
+		    timezone = NULLIF($7, ''), is_active = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $9 AND tenant_id = $10
+		RETURNING updated_at`
+
+	err = r.db.QueryRow(query,
+		location.Name,
+		location.Latitude,
+		location.Longitude,
+		location.Radius,
+		boundary,
+		location.Address,
+		location.Timezone,
+		location.IsActive,
+		location.ID,
+		location.TenantID,
+	).Scan(&location.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrOfficeLocationNotFound
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *officeLocationRepositoryImpl) DeleteLocation(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.office_locations WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrOfficeLocationNotFound
+	}
+	return nil
+}
+
+func (r *officeLocationRepositoryImpl) CreateSignal(signal *models.OfficePresenceSignal) error {
+	query := `INSERT INTO godplan.office_presence_signals
+		(tenant_id, office_location_id, signal_type, identifier, label, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		signal.TenantID,
+		signal.OfficeLocationID,
+		signal.SignalType,
+		signal.Identifier,
+		signal.Label,
+		signal.IsActive,
+	).Scan(&signal.ID, &signal.CreatedAt, &signal.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrPresenceSignalDuplicate
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *officeLocationRepositoryImpl) GetSignals(tenantID uuid.UUID, officeLocationID uuid.UUID) ([]models.OfficePresenceSignal, error) {
+	query := `SELECT id, tenant_id, office_location_id, signal_type, identifier, COALESCE(label, ''),
+		       is_active, created_at, updated_at
+		FROM godplan.office_presence_signals
+		WHERE tenant_id = $1 AND office_location_id = $2
+		ORDER BY signal_type ASC, identifier ASC`
+
+	rows, err := r.db.Query(query, tenantID, officeLocationID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	signals := []models.OfficePresenceSignal{}
+	for rows.Next() {
+		var signal models.OfficePresenceSignal
+		if err := rows.Scan(
+			&signal.ID,
+			&signal.TenantID,
+			&signal.OfficeLocationID,
+			&signal.SignalType,
+			&signal.Identifier,
+			&signal.Label,
+			&signal.IsActive,
+			&signal.CreatedAt,
+			&signal.UpdatedAt,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		signals = append(signals, signal)
+	}
+
+	return signals, nil
+}
+
+func (r *officeLocationRepositoryImpl) DeleteSignal(tenantID uuid.UUID, officeLocationID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.office_presence_signals
+		WHERE id = $1 AND office_location_id = $2 AND tenant_id = $3`, id, officeLocationID, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrPresenceSignalNotFound
+	}
+	return nil
+}
+
+// FindPresenceMatch looks up the first active signal of an active office among the
+// BSSIDs and beacon IDs seen by the device. BLE beacons are preferred because their
+// range is much shorter than Wi-Fi. Returns nil when nothing matches.
+func (r *officeLocationRepositoryImpl) FindPresenceMatch(tenantID uuid.UUID, bssids []string, beaconIDs []string) (*models.PresenceMatch, error) {
+	query := `SELECT s.office_location_id, s.signal_type, s.identifier
+		FROM godplan.office_presence_signals s
+		JOIN godplan.office_locations o ON o.id = s.office_location_id
+		WHERE s.tenant_id = $1 AND s.is_active = true AND o.is_active = true
+		  AND ((s.signal_type = 'wifi' AND s.identifier = ANY($2))
+		    OR (s.signal_type = 'ble' AND s.identifier = ANY($3)))
+		ORDER BY CASE s.signal_type WHEN 'ble' THEN 0 ELSE 1 END, s.created_at ASC
+		LIMIT 1`
+
+	var match models.PresenceMatch
+	err := r.db.QueryRow(query, tenantID, pq.Array(bssids), pq.Array(beaconIDs)).
+		Scan(&match.OfficeLocationID, &match.Method, &match.Identifier)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return &match, nil
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrOvertimeNotFound   = errors.New("overtime request not found")
+	ErrOvertimeNotPending = errors.New("overtime request is not pending")
+)
+
+// OvertimeRepository defines access methods for overtime requests and their reconciliation
+type OvertimeRepository interface {
+	CreateOvertimeRequest(request *models.OvertimeRequest) error
+	GetOvertimeRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.OvertimeRequest, error)
+	GetOvertimeRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.OvertimeRequest, error)
+	GetPendingOvertimeRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.OvertimeRequest, error)
+	HasOverlappingRequest(tenantID uuid.UUID, employeeID uuid.UUID, start, end time.Time) (bool, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	ApproveOvertime(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string, dayType string) error
+	RejectOvertime(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string) error
+	CancelOvertimeRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error
+	GetApprovedOvertimeInWindow(tenantID uuid.UUID, userID uuid.UUID, from, to time.Time) ([]models.OvertimeRequest, error)
+	GetClosedSessions(tenantID uuid.UUID, userID uuid.UUID, from, to time.Time) ([]models.AttendanceSession, error)
+	SaveReconciliation(request *models.OvertimeRequest) error
+	UpdateAttendanceOvertime(tenantID uuid.UUID, attendanceID uuid.UUID, minutes float64) error
+	GetOvertimeReport(tenantID uuid.UUID, viewerID *uuid.UUID, employeeID *uuid.UUID, startDate, endDate string) ([]models.OvertimeReportRow, error)
+}
+
+type overtimeRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOvertimeRepository(db *sql.DB) OvertimeRepository {
+	return &overtimeRepositoryImpl{db: db}
+}
+
+func (r *overtimeRepositoryImpl) CreateOvertimeRequest(request *models.OvertimeRequest) error {
+	query := `INSERT INTO godplan.overtime_requests
+		(tenant_id, employee_id, user_id, overtime_date, start_time, end_time, planned_minutes, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		request.TenantID,
+		request.EmployeeID,
+		request.UserID,
+		request.OvertimeDate,
+		request.StartTime,
+		request.EndTime,
+		request.PlannedMinutes,
+		request.Reason,
+		request.Status,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const overtimeRequestQuery = `SELECT o.id, o.tenant_id, o.employee_id, o.user_id, COALESCE(u.full_name, u.username),
+		TO_CHAR(o.overtime_date, 'YYYY-MM-DD'), o.start_time, o.end_time, o.planned_minutes, o.reason, o.status,
+		o.approved_by, o.approved_at, COALESCE(o.rejection_reason, ''), COALESCE(o.day_type, ''), o.attendance_id,
+		COALESCE(o.actual_minutes, 0), COALESCE(o.multiplier, 0), COALESCE(o.compensable_hours, 0), o.reconciled_at,
+		o.created_at, o.updated_at
+	FROM godplan.overtime_requests o
+	JOIN godplan.users u ON u.id = o.user_id`
+
+func scanOvertimeRequest(row rowScanner) (*models.OvertimeRequest, error) {
+	var request models.OvertimeRequest
+	var approvedAt, reconciledAt sql.NullTime
+	err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.EmployeeID,
+		&request.UserID,
+		&request.EmployeeName,
+		&request.OvertimeDate,
+		&request.StartTime,
+		&request.EndTime,
+		&request.PlannedMinutes,
+		&request.Reason,
+		&request.Status,
+		&request.ApprovedBy,
+		&approvedAt,
+		&request.RejectionReason,
+		&request.DayType,
+		&request.AttendanceID,
+		&request.ActualMinutes,
+		&request.Multiplier,
+		&request.CompensableHours,
+		&reconciledAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.ApprovedAt = nullTimePtr(approvedAt)
+	request.ReconciledAt = nullTimePtr(reconciledAt)
+	return &request, nil
+}
+
+func (r *overtimeRepositoryImpl) queryOvertimeRequests(query string, args ...interface{}) ([]models.OvertimeRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	requests := []models.OvertimeRequest{}
+	for rows.Next() {
+		request, err := scanOvertimeRequest(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, nil
+}
+
+func (r *overtimeRepositoryImpl) GetOvertimeRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.OvertimeRequest, error) {
+	query := overtimeRequestQuery + ` WHERE o.id = $1 AND o.tenant_id = $2`
+
+	request, err := scanOvertimeRequest(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrOvertimeNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return request, nil
+}
+
+func (r *overtimeRepositoryImpl) GetOvertimeRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.OvertimeRequest, error) {
+	query := overtimeRequestQuery + `
+		WHERE o.tenant_id = $1 AND o.employee_id = $2
+		ORDER BY o.start_time DESC`
+	return r.queryOvertimeRequests(query, tenantID, employeeID)
+}
+
+// GetPendingOvertimeRequests returns pending requests of the tenant, or only those of the
+// supervisor's direct reports when supervisorID is set
+func (r *overtimeRepositoryImpl) GetPendingOvertimeRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.OvertimeRequest, error) {
+	query := overtimeRequestQuery + `
+		JOIN godplan.employees e ON e.id = o.employee_id
+		WHERE o.tenant_id = $1 AND o.status = 'pending'
+		AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY o.start_time ASC`
+	return r.queryOvertimeRequests(query, tenantID, supervisorID)
+}
+
+// HasOverlappingRequest reports whether the employee already has a pending or approved
+// request overlapping the window
+func (r *overtimeRepositoryImpl) HasOverlappingRequest(tenantID uuid.UUID, employeeID uuid.UUID, start, end time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (
+			SELECT 1 FROM godplan.overtime_requests
+			WHERE tenant_id = $1 AND employee_id = $2 AND status IN ('pending', 'approved')
+			AND start_time < $4 AND end_time > $3
+		)`, tenantID, employeeID, start, end).Scan(&exists)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return exists, nil
+}
+
+func (r *overtimeRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+func (r *overtimeRepositoryImpl) ApproveOvertime(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string, dayType string) error {
+	result, err := r.db.Exec(`UPDATE godplan.overtime_requests
+		SET status = 'approved', approved_by = $1, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($2, ''), day_type = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5 AND status = 'pending'`,
+		approvedBy, reason, dayType, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrOvertimeNotPending
+	}
+	return nil
+}
+
+func (r *overtimeRepositoryImpl) RejectOvertime(tenantID uuid.UUID, id uuid.UUID, approvedBy uuid.UUID, reason string) error {
+	result, err := r.db.Exec(`UPDATE godplan.overtime_requests
+		SET status = 'rejected', approved_by = $1, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND tenant_id = $4 AND status = 'pending'`,
+		approvedBy, reason, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrOvertimeNotPending
+	}
+	return nil
+}
+
+// CancelOvertimeRequest lets the employee withdraw their own pending request
+func (r *overtimeRepositoryImpl) CancelOvertimeRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.overtime_requests
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND employee_id = $3 AND status = 'pending'`,
+		id, tenantID, employeeID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrOvertimeNotPending
+	}
+	return nil
+}
+
+// GetApprovedOvertimeInWindow returns the approved requests of the user overlapping [from, to]
+func (r *overtimeRepositoryImpl) GetApprovedOvertimeInWindow(tenantID uuid.UUID, userID uuid.UUID, from, to time.Time) ([]models.OvertimeRequest, error) {
+	query := overtimeRequestQuery + `
+		WHERE o.tenant_id = $1 AND o.user_id = $2 AND o.status = 'approved'
+		AND o.start_time < $4 AND o.end_time > $3
+		ORDER BY o.start_time ASC`
+	return r.queryOvertimeRequests(query, tenantID, userID, from, to)
+}
+
+// GetClosedSessions returns the clocked out attendance sessions of the user overlapping [from, to]
+func (r *overtimeRepositoryImpl) GetClosedSessions(tenantID uuid.UUID, userID uuid.UUID, from, to time.Time) ([]models.AttendanceSession, error) {
+	rows, err := r.db.Query(`SELECT id, tenant_id, user_id, TO_CHAR(attendance_date, 'YYYY-MM-DD'),
+			COALESCE(check_in_time, created_at), check_out_time, COALESCE(total_hours, 0), COALESCE(break_minutes, 0)
+		FROM godplan.attendances
+		WHERE tenant_id = $1 AND user_id = $2 AND check_out_time IS NOT NULL
+		AND COALESCE(check_in_time, created_at) < $4 AND check_out_time > $3
+		ORDER BY check_in_time ASC`, tenantID, userID, from, to)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	sessions := []models.AttendanceSession{}
+	for rows.Next() {
+		var session models.AttendanceSession
+		var checkOut time.Time
+		if err := rows.Scan(
+			&session.ID,
+			&session.TenantID,
+			&session.UserID,
+			&session.AttendanceDate,
+			&session.CheckInTime,
+			&checkOut,
+			&session.TotalHours,
+			&session.BreakMinutes,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		session.CheckOutTime = &checkOut
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// SaveReconciliation stores the worked minutes and compensable hours of an approved request
+func (r *overtimeRepositoryImpl) SaveReconciliation(request *models.OvertimeRequest) error {
+	_, err := r.db.Exec(`UPDATE godplan.overtime_requests
+		SET attendance_id = $1, actual_minutes = $2, multiplier = $3, compensable_hours = $4,
+		    reconciled_at = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND tenant_id = $7 AND status = 'approved'`,
+		request.AttendanceID, request.ActualMinutes, request.Multiplier, request.CompensableHours,
+		request.ReconciledAt, request.ID, request.TenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// UpdateAttendanceOvertime records approved overtime on the attendance. Overtime computed from
+// the work schedule is kept when it is higher.
+func (r *overtimeRepositoryImpl) UpdateAttendanceOvertime(tenantID uuid.UUID, attendanceID uuid.UUID, minutes float64) error {
+	_, err := r.db.Exec(`UPDATE godplan.attendances
+		SET overtime_minutes = GREATEST(COALESCE(overtime_minutes, 0), $1), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND tenant_id = $3`, minutes, attendanceID, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// GetOvertimeReport sums approved overtime per employee between two dates (inclusive).
+// viewerID limits the report to that employee and their direct reports.
+func (r *overtimeRepositoryImpl) GetOvertimeReport(tenantID uuid.UUID, viewerID *uuid.UUID, employeeID *uuid.UUID, startDate, endDate string) ([]models.OvertimeReportRow, error) {
+	rows, err := r.db.Query(`SELECT e.id, COALESCE(u.full_name, u.username),
+			COUNT(*), COALESCE(SUM(o.planned_minutes), 0), COALESCE(SUM(o.actual_minutes), 0),
+			COALESCE(SUM(o.actual_minutes) FILTER (WHERE o.day_type = 'weekday'), 0),
+			COALESCE(SUM(o.actual_minutes) FILTER (WHERE o.day_type = 'weekend'), 0),
+			COALESCE(SUM(o.actual_minutes) FILTER (WHERE o.day_type = 'holiday'), 0),
+			COALESCE(SUM(o.compensable_hours), 0)
+		FROM godplan.overtime_requests o
+		JOIN godplan.employees e ON e.id = o.employee_id
+		JOIN godplan.users u ON u.id = o.user_id
+		WHERE o.tenant_id = $1 AND o.status = 'approved'
+		AND o.overtime_date BETWEEN $2 AND $3
+		AND ($4::uuid IS NULL OR e.id = $4 OR e.supervisor_id = $4)
+		AND ($5::uuid IS NULL OR e.id = $5)
+		GROUP BY e.id, u.full_name, u.username
+		ORDER BY COALESCE(u.full_name, u.username) ASC`, tenantID, startDate, endDate, viewerID, employeeID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	report := []models.OvertimeReportRow{}
+	for rows.Next() {
+		var row models.OvertimeReportRow
+		if err := rows.Scan(
+			&row.EmployeeID,
+			&row.EmployeeName,
+			&row.ApprovedRequests,
+			&row.PlannedMinutes,
+			&row.ActualMinutes,
+			&row.WeekdayMinutes,
+			&row.WeekendMinutes,
+			&row.HolidayMinutes,
+			&row.CompensableHours,
+		); err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		report = append(report, row)
+	}
+
+	return report, nil
+}
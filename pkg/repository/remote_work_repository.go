@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrHomeLocationNotFound = errors.New("home location not found")
+	ErrWFHRequestNotFound   = errors.New("WFH request not found")
+	ErrWFHRequestNotPending = errors.New("WFH request is not pending")
+)
+
+// RemoteWorkRepository defines access methods for home locations and WFH requests
+type RemoteWorkRepository interface {
+	GetHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) (*models.HomeLocation, error)
+	GetHomeLocations(tenantID uuid.UUID) ([]models.HomeLocation, error)
+	SaveHomeLocation(home *models.HomeLocation) error
+	DeleteHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) error
+
+	CreateWFHRequest(request *models.WFHRequest) error
+	GetWFHRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.WFHRequest, error)
+	GetWFHRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.WFHRequest, error)
+	GetPendingWFHRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.WFHRequest, error)
+	GetApprovedWFHRequest(tenantID uuid.UUID, userID uuid.UUID, date string) (*models.WFHRequest, error)
+	HasOverlappingWFH(tenantID uuid.UUID, employeeID uuid.UUID, startDate string, endDate string) (bool, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	DecideWFHRequest(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string) error
+	CancelWFHRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error
+}
+
+type remoteWorkRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRemoteWorkRepository(db *sql.DB) RemoteWorkRepository {
+	return &remoteWorkRepositoryImpl{db: db}
+}
+
+const homeLocationQuery = `SELECT h.id, h.tenant_id, h.employee_id, COALESCE(u.full_name, u.username), h.name,
+		h.latitude, h.longitude, h.radius, h.created_at, h.updated_at
+	FROM godplan.employee_home_locations h
+	JOIN godplan.employees e ON e.id = h.employee_id
+	JOIN godplan.users u ON u.id = e.user_id`
+
+func scanHomeLocation(row rowScanner) (*models.HomeLocation, error) {
+	var home models.HomeLocation
+	err := row.Scan(
+		&home.ID,
+		&home.TenantID,
+		&home.EmployeeID,
+		&home.EmployeeName,
+		&home.Name,
+		&home.Latitude,
+		&home.Longitude,
+		&home.Radius,
+		&home.CreatedAt,
+		&home.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+func (r *remoteWorkRepositoryImpl) GetHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) (*models.HomeLocation, error) {
+	query := homeLocationQuery + ` WHERE h.tenant_id = $1 AND h.employee_id = $2`
+
+	home, err := scanHomeLocation(r.db.QueryRow(query, tenantID, employeeID))
+	if err == sql.ErrNoRows {
+		return nil, ErrHomeLocationNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return home, nil
+}
+
+func (r *remoteWorkRepositoryImpl) GetHomeLocations(tenantID uuid.UUID) ([]models.HomeLocation, error) {
+	query := homeLocationQuery + `
+		WHERE h.tenant_id = $1
+		ORDER BY COALESCE(u.full_name, u.username) ASC`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	homes := []models.HomeLocation{}
+	for rows.Next() {
+		home, err := scanHomeLocation(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		homes = append(homes, *home)
+	}
+
+	return homes, nil
+}
+
+// SaveHomeLocation registers the employee home location or moves the existing one
+func (r *remoteWorkRepositoryImpl) SaveHomeLocation(home *models.HomeLocation) error {
+	query := `INSERT INTO godplan.employee_home_locations (tenant_id, employee_id, name, latitude, longitude, radius)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (employee_id) DO UPDATE SET name = EXCLUDED.name, latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude, radius = EXCLUDED.radius, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		home.TenantID,
+		home.EmployeeID,
+		home.Name,
+		home.Latitude,
+		home.Longitude,
+		home.Radius,
+	).Scan(&home.ID, &home.CreatedAt, &home.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *remoteWorkRepositoryImpl) DeleteHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.employee_home_locations WHERE tenant_id = $1 AND employee_id = $2`,
+		tenantID, employeeID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrHomeLocationNotFound
+	}
+	return nil
+}
+
+func (r *remoteWorkRepositoryImpl) CreateWFHRequest(request *models.WFHRequest) error {
+	query := `INSERT INTO godplan.wfh_requests (tenant_id, employee_id, user_id, start_date, end_date, reason, status)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		request.TenantID,
+		request.EmployeeID,
+		request.UserID,
+		request.StartDate,
+		request.EndDate,
+		request.Reason,
+		request.Status,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const wfhRequestQuery = `SELECT w.id, w.tenant_id, w.employee_id, w.user_id, COALESCE(u.full_name, u.username),
+		TO_CHAR(w.start_date, 'YYYY-MM-DD'), TO_CHAR(w.end_date, 'YYYY-MM-DD'), COALESCE(w.reason, ''), w.status,
+		w.approved_by, w.approved_at, COALESCE(w.rejection_reason, ''), w.created_at, w.updated_at
+	FROM godplan.wfh_requests w
+	JOIN godplan.users u ON u.id = w.user_id`
+
+func scanWFHRequest(row rowScanner) (*models.WFHRequest, error) {
+	var request models.WFHRequest
+	var approvedAt sql.NullTime
+	err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.EmployeeID,
+		&request.UserID,
+		&request.EmployeeName,
+		&request.StartDate,
+		&request.EndDate,
+		&request.Reason,
+		&request.Status,
+		&request.ApprovedBy,
+		&approvedAt,
+		&request.RejectionReason,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if approvedAt.Valid {
+		request.ApprovedAt = &approvedAt.Time
+	}
+	return &request, nil
+}
+
+func (r *remoteWorkRepositoryImpl) queryWFHRequests(query string, args ...interface{}) ([]models.WFHRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	requests := []models.WFHRequest{}
+	for rows.Next() {
+		request, err := scanWFHRequest(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, nil
+}
+
+func (r *remoteWorkRepositoryImpl) GetWFHRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.WFHRequest, error) {
+	query := wfhRequestQuery + ` WHERE w.id = $1 AND w.tenant_id = $2`
+
+	request, err := scanWFHRequest(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrWFHRequestNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return request, nil
+}
+
+func (r *remoteWorkRepositoryImpl) GetWFHRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.WFHRequest, error) {
+	query := wfhRequestQuery + `
+		WHERE w.tenant_id = $1 AND w.employee_id = $2
+		ORDER BY w.start_date DESC`
+	return r.queryWFHRequests(query, tenantID, employeeID)
+}
+
+// GetPendingWFHRequests returns pending requests of the tenant, or only those of the
+// supervisor's direct reports when supervisorID is set
+func (r *remoteWorkRepositoryImpl) GetPendingWFHRequests(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.WFHRequest, error) {
+	query := wfhRequestQuery + `
+		JOIN godplan.employees e ON e.id = w.employee_id
+		WHERE w.tenant_id = $1 AND w.status = 'pending'
+		AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY w.start_date ASC`
+	return r.queryWFHRequests(query, tenantID, supervisorID)
+}
+
+// GetApprovedWFHRequest returns the approved WFH request of the user covering the date,
+// or nil when the user works from the office that day
+func (r *remoteWorkRepositoryImpl) GetApprovedWFHRequest(tenantID uuid.UUID, userID uuid.UUID, date string) (*models.WFHRequest, error) {
+	query := wfhRequestQuery + `
+		WHERE w.tenant_id = $1 AND w.user_id = $2 AND w.status = 'approved'
+		AND w.start_date <= $3 AND w.end_date >= $3
+		LIMIT 1`
+
+	request, err := scanWFHRequest(r.db.QueryRow(query, tenantID, userID, date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return request, nil
+}
+
+// HasOverlappingWFH reports whether a pending or approved request already covers part of the period
+func (r *remoteWorkRepositoryImpl) HasOverlappingWFH(tenantID uuid.UUID, employeeID uuid.UUID, startDate string, endDate string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (
+			SELECT 1 FROM godplan.wfh_requests
+			WHERE tenant_id = $1 AND employee_id = $2 AND status IN ('pending', 'approved')
+			AND start_date <= $4 AND end_date >= $3
+		)`, tenantID, employeeID, startDate, endDate).Scan(&exists)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return exists, nil
+}
+
+func (r *remoteWorkRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+func (r *remoteWorkRepositoryImpl) DecideWFHRequest(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string) error {
+	result, err := r.db.Exec(`UPDATE godplan.wfh_requests
+		SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5 AND status = 'pending'`,
+		status, approvedBy, reason, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrWFHRequestNotPending
+	}
+	return nil
+}
+
+// CancelWFHRequest lets the employee withdraw their own pending request
+func (r *remoteWorkRepositoryImpl) CancelWFHRequest(tenantID uuid.UUID, id uuid.UUID, employeeID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.wfh_requests
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND employee_id = $3 AND status = 'pending'`,
+		id, tenantID, employeeID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrWFHRequestNotPending
+	}
+	return nil
+}
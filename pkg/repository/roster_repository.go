@@ -0,0 +1,465 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrRosterEntryNotFound    = errors.New("roster entry not found")
+	ErrRosterInvalidReference = errors.New("employee or schedule not found in this tenant")
+	ErrShiftSwapNotFound      = errors.New("shift swap request not found")
+	ErrShiftSwapNotPending    = errors.New("shift swap request is no longer pending")
+)
+
+// RosterRepository defines access methods for shift rosters and shift swaps
+type RosterRepository interface {
+	SaveRosterEntries(tenantID uuid.UUID, createdBy uuid.UUID, entries []models.RosterEntryRequest) (int, error)
+	GetRoster(tenantID uuid.UUID, startDate, endDate string, employeeID *uuid.UUID) ([]models.ShiftRoster, error)
+	GetRosterEntry(tenantID uuid.UUID, employeeID uuid.UUID, shiftDate string) (*models.ShiftRoster, error)
+	DeleteRosterEntry(tenantID uuid.UUID, id uuid.UUID) error
+	GetRosteredShifts(tenantID uuid.UUID, userID uuid.UUID, startDate, endDate string) ([]models.RosteredShift, error)
+	GetRosteredShift(tenantID uuid.UUID, rosterID uuid.UUID) (*models.RosteredShift, error)
+
+	EmployeeExists(tenantID uuid.UUID, employeeID uuid.UUID) (bool, error)
+	IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error)
+	CreateShiftSwap(swap *models.ShiftSwapRequest) error
+	GetShiftSwapByID(tenantID uuid.UUID, id uuid.UUID) (*models.ShiftSwapRequest, error)
+	GetShiftSwaps(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.ShiftSwapRequest, error)
+	GetPendingShiftSwaps(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.ShiftSwapRequest, error)
+	DecideShiftSwap(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string) error
+	CancelShiftSwap(tenantID uuid.UUID, id uuid.UUID, requesterID uuid.UUID) error
+}
+
+type rosterRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRosterRepository(db *sql.DB) RosterRepository {
+	return &rosterRepositoryImpl{db: db}
+}
+
+// SaveRosterEntries upserts the entries in one transaction. An employee or schedule from
+// another tenant rolls back the whole batch with ErrRosterInvalidReference.
+func (r *rosterRepositoryImpl) SaveRosterEntries(tenantID uuid.UUID, createdBy uuid.UUID, entries []models.RosterEntryRequest) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO godplan.shift_rosters (tenant_id, employee_id, shift_date, schedule_id, notes, created_by)
+		SELECT $1, e.id, $3, $4, NULLIF($5, ''), $6
+		FROM godplan.employees e
+		WHERE e.id = $2 AND e.tenant_id = $1
+		AND ($4::uuid IS NULL OR EXISTS (
+			SELECT 1 FROM godplan.attendance_schedules s WHERE s.id = $4 AND s.tenant_id = $1))
+		ON CONFLICT (employee_id, shift_date) DO UPDATE
+		SET schedule_id = EXCLUDED.schedule_id, notes = EXCLUDED.notes,
+		    created_by = EXCLUDED.created_by, updated_at = CURRENT_TIMESTAMP`
+
+	for _, entry := range entries {
+		result, err := tx.Exec(query, tenantID, entry.EmployeeID, entry.Date, entry.ScheduleID, entry.Notes, createdBy)
+		if err != nil {
+			return 0, utils.ErrInternalServer
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return 0, ErrRosterInvalidReference
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	return len(entries), nil
+}
+
+const rosterQuery = `SELECT sr.id, sr.tenant_id, sr.employee_id, COALESCE(u.full_name, u.username),
+		TO_CHAR(sr.shift_date, 'YYYY-MM-DD'), sr.schedule_id, COALESCE(s.name, ''),
+		COALESCE(TO_CHAR(s.start_time, 'HH24:MI'), ''), COALESCE(TO_CHAR(s.end_time, 'HH24:MI'), ''),
+		COALESCE(sr.notes, ''), sr.created_at, sr.updated_at
+	FROM godplan.shift_rosters sr
+	JOIN godplan.employees e ON e.id = sr.employee_id
+	JOIN godplan.users u ON u.id = e.user_id
+	LEFT JOIN godplan.attendance_schedules s ON s.id = sr.schedule_id`
+
+func scanRosterEntry(row rowScanner) (*models.ShiftRoster, error) {
+	var entry models.ShiftRoster
+	var scheduleID uuid.NullUUID
+	err := row.Scan(
+		&entry.ID,
+		&entry.TenantID,
+		&entry.EmployeeID,
+		&entry.EmployeeName,
+		&entry.ShiftDate,
+		&scheduleID,
+		&entry.ScheduleName,
+		&entry.StartTime,
+		&entry.EndTime,
+		&entry.Notes,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if scheduleID.Valid {
+		entry.ScheduleID = &scheduleID.UUID
+	}
+	entry.IsDayOff = !scheduleID.Valid
+	return &entry, nil
+}
+
+// GetRoster returns the roster entries of the period, for one employee when employeeID is set
+func (r *rosterRepositoryImpl) GetRoster(tenantID uuid.UUID, startDate, endDate string, employeeID *uuid.UUID) ([]models.ShiftRoster, error) {
+	query := rosterQuery + `
+		WHERE sr.tenant_id = $1 AND sr.shift_date BETWEEN $2 AND $3
+		AND ($4::uuid IS NULL OR sr.employee_id = $4)
+		ORDER BY sr.shift_date, COALESCE(u.full_name, u.username)`
+
+	rows, err := r.db.Query(query, tenantID, startDate, endDate, employeeID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	entries := []models.ShiftRoster{}
+	for rows.Next() {
+		entry, err := scanRosterEntry(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+func (r *rosterRepositoryImpl) GetRosterEntry(tenantID uuid.UUID, employeeID uuid.UUID, shiftDate string) (*models.ShiftRoster, error) {
+	query := rosterQuery + ` WHERE sr.tenant_id = $1 AND sr.employee_id = $2 AND sr.shift_date = $3`
+
+	entry, err := scanRosterEntry(r.db.QueryRow(query, tenantID, employeeID, shiftDate))
+	if err == sql.ErrNoRows {
+		return nil, ErrRosterEntryNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return entry, nil
+}
+
+func (r *rosterRepositoryImpl) DeleteRosterEntry(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.shift_rosters WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrRosterEntryNotFound
+	}
+	return nil
+}
+
+const rosteredShiftQuery = `SELECT sr.id, sr.employee_id, TO_CHAR(sr.shift_date, 'YYYY-MM-DD'), ` + scheduleColumns + `
+	FROM godplan.shift_rosters sr
+	JOIN godplan.attendance_schedules s ON s.id = sr.schedule_id`
+
+func scanRosteredShift(row rowScanner) (*models.RosteredShift, error) {
+	var shift models.RosteredShift
+	schedule := &shift.Schedule
+	err := row.Scan(
+		&shift.RosterID,
+		&shift.EmployeeID,
+		&shift.ShiftDate,
+		&schedule.ID,
+		&schedule.TenantID,
+		&schedule.Name,
+		&schedule.StartTime,
+		&schedule.EndTime,
+		&schedule.WorkingDays,
+		&schedule.ToleranceLate,
+		&schedule.ToleranceEarly,
+		&schedule.IsDefault,
+		&schedule.IsActive,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// GetRosteredShifts returns the working shifts (day off entries excluded) of the user's
+// employee record starting between startDate and endDate
+func (r *rosterRepositoryImpl) GetRosteredShifts(tenantID uuid.UUID, userID uuid.UUID, startDate, endDate string) ([]models.RosteredShift, error) {
+	query := rosteredShiftQuery + `
+		JOIN godplan.employees e ON e.id = sr.employee_id
+		WHERE sr.tenant_id = $1 AND e.user_id = $2 AND sr.shift_date BETWEEN $3 AND $4
+		ORDER BY sr.shift_date`
+
+	rows, err := r.db.Query(query, tenantID, userID, startDate, endDate)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	shifts := []models.RosteredShift{}
+	for rows.Next() {
+		shift, err := scanRosteredShift(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		shifts = append(shifts, *shift)
+	}
+
+	return shifts, nil
+}
+
+func (r *rosterRepositoryImpl) GetRosteredShift(tenantID uuid.UUID, rosterID uuid.UUID) (*models.RosteredShift, error) {
+	query := rosteredShiftQuery + ` WHERE sr.id = $1 AND sr.tenant_id = $2`
+
+	shift, err := scanRosteredShift(r.db.QueryRow(query, rosterID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrRosterEntryNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return shift, nil
+}
+
+func (r *rosterRepositoryImpl) EmployeeExists(tenantID uuid.UUID, employeeID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees WHERE id = $1 AND tenant_id = $2`,
+		employeeID, tenantID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+func (r *rosterRepositoryImpl) IsEmployeeSupervisedBy(tenantID uuid.UUID, employeeID uuid.UUID, supervisorID uuid.UUID) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM godplan.employees
+		WHERE id = $1 AND tenant_id = $2 AND supervisor_id = $3`, employeeID, tenantID, supervisorID).Scan(&count)
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return count > 0, nil
+}
+
+func (r *rosterRepositoryImpl) CreateShiftSwap(swap *models.ShiftSwapRequest) error {
+	query := `INSERT INTO godplan.shift_swap_requests
+		(tenant_id, requester_id, requester_date, target_employee_id, target_date, reason, status)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query,
+		swap.TenantID,
+		swap.RequesterID,
+		swap.RequesterDate,
+		swap.TargetEmployeeID,
+		swap.TargetDate,
+		swap.Reason,
+		swap.Status,
+	).Scan(&swap.ID, &swap.CreatedAt, &swap.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+const shiftSwapQuery = `SELECT ss.id, ss.tenant_id, ss.requester_id, COALESCE(ru.full_name, ru.username),
+		TO_CHAR(ss.requester_date, 'YYYY-MM-DD'), ss.target_employee_id, COALESCE(tu.full_name, tu.username),
+		TO_CHAR(ss.target_date, 'YYYY-MM-DD'), COALESCE(ss.reason, ''), ss.status,
+		ss.approved_by, ss.approved_at, COALESCE(ss.rejection_reason, ''), ss.created_at, ss.updated_at
+	FROM godplan.shift_swap_requests ss
+	JOIN godplan.employees re ON re.id = ss.requester_id
+	JOIN godplan.users ru ON ru.id = re.user_id
+	JOIN godplan.employees te ON te.id = ss.target_employee_id
+	JOIN godplan.users tu ON tu.id = te.user_id`
+
+func scanShiftSwap(row rowScanner) (*models.ShiftSwapRequest, error) {
+	var swap models.ShiftSwapRequest
+	var approvedAt sql.NullTime
+	err := row.Scan(
+		&swap.ID,
+		&swap.TenantID,
+		&swap.RequesterID,
+		&swap.RequesterName,
+		&swap.RequesterDate,
+		&swap.TargetEmployeeID,
+		&swap.TargetName,
+		&swap.TargetDate,
+		&swap.Reason,
+		&swap.Status,
+		&swap.ApprovedBy,
+		&approvedAt,
+		&swap.RejectionReason,
+		&swap.CreatedAt,
+		&swap.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if approvedAt.Valid {
+		swap.ApprovedAt = &approvedAt.Time
+	}
+	return &swap, nil
+}
+
+func (r *rosterRepositoryImpl) queryShiftSwaps(query string, args ...interface{}) ([]models.ShiftSwapRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	swaps := []models.ShiftSwapRequest{}
+	for rows.Next() {
+		swap, err := scanShiftSwap(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		swaps = append(swaps, *swap)
+	}
+
+	return swaps, nil
+}
+
+func (r *rosterRepositoryImpl) GetShiftSwapByID(tenantID uuid.UUID, id uuid.UUID) (*models.ShiftSwapRequest, error) {
+	query := shiftSwapQuery + ` WHERE ss.id = $1 AND ss.tenant_id = $2`
+
+	swap, err := scanShiftSwap(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftSwapNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return swap, nil
+}
+
+// GetShiftSwaps returns the swaps the employee requested or is the target of
+func (r *rosterRepositoryImpl) GetShiftSwaps(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.ShiftSwapRequest, error) {
+	query := shiftSwapQuery + `
+		WHERE ss.tenant_id = $1 AND (ss.requester_id = $2 OR ss.target_employee_id = $2)
+		ORDER BY ss.created_at DESC`
+	return r.queryShiftSwaps(query, tenantID, employeeID)
+}
+
+// GetPendingShiftSwaps returns pending swaps of the tenant, or only those requested by the
+// supervisor's direct reports when supervisorID is set
+func (r *rosterRepositoryImpl) GetPendingShiftSwaps(tenantID uuid.UUID, supervisorID *uuid.UUID) ([]models.ShiftSwapRequest, error) {
+	query := shiftSwapQuery + `
+		WHERE ss.tenant_id = $1 AND ss.status = 'pending'
+		AND ($2::uuid IS NULL OR re.supervisor_id = $2)
+		ORDER BY ss.requester_date ASC`
+	return r.queryShiftSwaps(query, tenantID, supervisorID)
+}
+
+// DecideShiftSwap records a decision on a pending swap. Approving exchanges the roster
+// entries of both employees on the requester date and the target date in the same
+// transaction, keeping the roster IDs so attendances stay linked.
+func (r *rosterRepositoryImpl) DecideShiftSwap(tenantID uuid.UUID, id uuid.UUID, status string, approvedBy uuid.UUID, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	var requesterID, targetID uuid.UUID
+	var requesterDate, targetDate string
+	err = tx.QueryRow(`UPDATE godplan.shift_swap_requests
+		SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP,
+		    rejection_reason = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5 AND status = 'pending'
+		RETURNING requester_id, target_employee_id, TO_CHAR(requester_date, 'YYYY-MM-DD'), TO_CHAR(target_date, 'YYYY-MM-DD')`,
+		status, approvedBy, reason, id, tenantID).Scan(&requesterID, &targetID, &requesterDate, &targetDate)
+	if err == sql.ErrNoRows {
+		return ErrShiftSwapNotPending
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	if status == models.ShiftSwapStatusApproved {
+		dates := []string{requesterDate}
+		if targetDate != requesterDate {
+			dates = append(dates, targetDate)
+		}
+		for _, date := range dates {
+			if err := swapRosterDay(tx, tenantID, requesterID, targetID, date); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// swapRosterDay exchanges the roster of two employees on one date. When only one of them
+// has an entry, the entry moves to the other employee.
+func swapRosterDay(tx *sql.Tx, tenantID uuid.UUID, employeeA, employeeB uuid.UUID, date string) error {
+	entries := map[uuid.UUID]uuid.NullUUID{}
+	scheduleIDs := map[uuid.UUID]uuid.NullUUID{}
+
+	rows, err := tx.Query(`SELECT id, employee_id, schedule_id FROM godplan.shift_rosters
+		WHERE tenant_id = $1 AND shift_date = $2 AND employee_id IN ($3, $4)
+		FOR UPDATE`, tenantID, date, employeeA, employeeB)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	for rows.Next() {
+		var rosterID uuid.UUID
+		var employeeID uuid.UUID
+		var scheduleID uuid.NullUUID
+		if err := rows.Scan(&rosterID, &employeeID, &scheduleID); err != nil {
+			rows.Close()
+			return utils.ErrInternalServer
+		}
+		entries[employeeID] = uuid.NullUUID{UUID: rosterID, Valid: true}
+		scheduleIDs[employeeID] = scheduleID
+	}
+	rows.Close()
+
+	rosterA, rosterB := entries[employeeA], entries[employeeB]
+	switch {
+	case rosterA.Valid && rosterB.Valid:
+		_, err = tx.Exec(`UPDATE godplan.shift_rosters SET schedule_id = CASE id WHEN $1 THEN $3::uuid ELSE $4::uuid END,
+			updated_at = CURRENT_TIMESTAMP WHERE id IN ($1, $2)`,
+			rosterA.UUID, rosterB.UUID, scheduleIDs[employeeB], scheduleIDs[employeeA])
+	case rosterA.Valid:
+		_, err = tx.Exec(`UPDATE godplan.shift_rosters SET employee_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+			employeeB, rosterA.UUID)
+	case rosterB.Valid:
+		_, err = tx.Exec(`UPDATE godplan.shift_rosters SET employee_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+			employeeA, rosterB.UUID)
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+// CancelShiftSwap lets the requester withdraw their own pending swap
+func (r *rosterRepositoryImpl) CancelShiftSwap(tenantID uuid.UUID, id uuid.UUID, requesterID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE godplan.shift_swap_requests
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND requester_id = $3 AND status = 'pending'`,
+		id, tenantID, requesterID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrShiftSwapNotPending
+	}
+	return nil
+}
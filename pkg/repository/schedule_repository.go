@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// ScheduleRepository defines access methods for tenant work schedules
+type ScheduleRepository interface {
+	CreateSchedule(schedule *models.AttendanceSchedule) error
+	GetScheduleByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceSchedule, error)
+	GetSchedules(tenantID uuid.UUID) ([]models.AttendanceSchedule, error)
+	UpdateSchedule(schedule *models.AttendanceSchedule) error
+	DeleteSchedule(tenantID uuid.UUID, id uuid.UUID) error
+	AssignSchedule(tenantID uuid.UUID, scheduleID uuid.UUID, employeeIDs []uuid.UUID) (int64, error)
+	GetScheduleForUser(tenantID uuid.UUID, userID uuid.UUID) (*models.AttendanceSchedule, error)
+}
+
+type scheduleRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &scheduleRepositoryImpl{db: db}
+}
+
+const scheduleColumns = `s.id, s.tenant_id, s.name, TO_CHAR(s.start_time, 'HH24:MI'), TO_CHAR(s.end_time, 'HH24:MI'),
+	COALESCE(s.working_days, 0), COALESCE(s.tolerance_late, 0), COALESCE(s.tolerance_early, 0),
+	COALESCE(s.is_default, false), COALESCE(s.is_active, true), s.created_at, COALESCE(s.updated_at, s.created_at)`
+
+func scanSchedule(row rowScanner) (*models.AttendanceSchedule, error) {
+	var schedule models.AttendanceSchedule
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.TenantID,
+		&schedule.Name,
+		&schedule.StartTime,
+		&schedule.EndTime,
+		&schedule.WorkingDays,
+		&schedule.ToleranceLate,
+		&schedule.ToleranceEarly,
+		&schedule.IsDefault,
+		&schedule.IsActive,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// clearDefaultSchedule unsets the current tenant default so a new one can be marked
+func clearDefaultSchedule(tx *sql.Tx, tenantID uuid.UUID, exceptID uuid.UUID) error {
+	_, err := tx.Exec(`UPDATE godplan.attendance_schedules SET is_default = false
+		WHERE tenant_id = $1 AND id <> $2 AND is_default = true`, tenantID, exceptID)
+	return err
+}
+
+func (r *scheduleRepositoryImpl) CreateSchedule(schedule *models.AttendanceSchedule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if schedule.IsDefault {
+		if err := clearDefaultSchedule(tx, schedule.TenantID, uuid.Nil); err != nil {
+			return utils.ErrInternalServer
+		}
+	}
+
+	query := `INSERT INTO godplan.attendance_schedules
+		(tenant_id, name, start_time, end_time, working_days, tolerance_late, tolerance_early, is_default, is_active)
+		VALUES ($1, $2, $3::time, $4::time, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err = tx.QueryRow(query,
+		schedule.TenantID,
+		schedule.Name,
+		schedule.StartTime,
+		schedule.EndTime,
+		schedule.WorkingDays,
+		schedule.ToleranceLate,
+		schedule.ToleranceEarly,
+		schedule.IsDefault,
+		schedule.IsActive,
+	).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *scheduleRepositoryImpl) GetScheduleByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceSchedule, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM godplan.attendance_schedules s WHERE s.id = $1 AND s.tenant_id = $2`
+
+	schedule, err := scanSchedule(r.db.QueryRow(query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return schedule, nil
+}
+
+func (r *scheduleRepositoryImpl) GetSchedules(tenantID uuid.UUID) ([]models.AttendanceSchedule, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM godplan.attendance_schedules s WHERE s.tenant_id = $1
+		ORDER BY s.is_default DESC, s.name ASC`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	schedules := []models.AttendanceSchedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, nil
+}
+
+func (r *scheduleRepositoryImpl) UpdateSchedule(schedule *models.AttendanceSchedule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if schedule.IsDefault {
+		if err := clearDefaultSchedule(tx, schedule.TenantID, schedule.ID); err != nil {
+			return utils.ErrInternalServer
+		}
+	}
+
+	query := `UPDATE godplan.attendance_schedules
+		SET name = $1, start_time = $2::time, end_time = $3::time, working_days = $4,
+		    tolerance_late = $5, tolerance_early = $6, is_default = $7, is_active = $8,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $9 AND tenant_id = $10
+		RETURNING updated_at`
+
+	err = tx.QueryRow(query,
+		schedule.Name,
+		schedule.StartTime,
+		schedule.EndTime,
+		schedule.WorkingDays,
+		schedule.ToleranceLate,
+		schedule.ToleranceEarly,
+		schedule.IsDefault,
+		schedule.IsActive,
+		schedule.ID,
+		schedule.TenantID,
+	).Scan(&schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrScheduleNotFound
+	}
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	// Keep the denormalized schedule name on employees in sync
+	if _, err := tx.Exec(`UPDATE godplan.employees SET work_schedule = $1
+		WHERE schedule_id = $2 AND tenant_id = $3`, schedule.Name, schedule.ID, schedule.TenantID); err != nil {
+		return utils.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ErrInternalServer
+	}
+	return nil
+}
+
+func (r *scheduleRepositoryImpl) DeleteSchedule(tenantID uuid.UUID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM godplan.attendance_schedules WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// AssignSchedule sets the schedule of the given employees and returns how many were updated
+func (r *scheduleRepositoryImpl) AssignSchedule(tenantID uuid.UUID, scheduleID uuid.UUID, employeeIDs []uuid.UUID) (int64, error) {
+	ids := make([]string, len(employeeIDs))
+	for i, id := range employeeIDs {
+		ids[i] = id.String()
+	}
+
+	query := `UPDATE godplan.employees e
+		SET schedule_id = s.id, work_schedule = s.name, updated_at = CURRENT_TIMESTAMP
+		FROM godplan.attendance_schedules s
+		WHERE s.id = $1 AND s.tenant_id = $2
+		AND e.tenant_id = $2 AND e.id = ANY($3::uuid[])`
+
+	result, err := r.db.Exec(query, scheduleID, tenantID, pq.Array(ids))
+	if err != nil {
+		return 0, utils.ErrInternalServer
+	}
+	affected, _ := result.RowsAffected()
+	return affected, nil
+}
+
+// GetScheduleForUser returns the active schedule assigned to the user's employee record,
+// falling back to the tenant default schedule. Returns ErrScheduleNotFound when neither exists.
+func (r *scheduleRepositoryImpl) GetScheduleForUser(tenantID uuid.UUID, userID uuid.UUID) (*models.AttendanceSchedule, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM godplan.attendance_schedules s
+		LEFT JOIN godplan.employees e ON e.schedule_id = s.id AND e.user_id = $2 AND e.tenant_id = $1
+		WHERE s.tenant_id = $1 AND COALESCE(s.is_active, true) = true
+		AND (e.id IS NOT NULL OR s.is_default = true)
+		ORDER BY (e.id IS NOT NULL) DESC
+		LIMIT 1`
+
+	schedule, err := scanSchedule(r.db.QueryRow(query, tenantID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	return schedule, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// TeamPresenceRepository defines read access for the team presence board
+type TeamPresenceRepository interface {
+	GetTeamPresence(tenantID uuid.UUID, supervisorID *uuid.UUID, date string) ([]models.TeamPresenceRecord, error)
+}
+
+type teamPresenceRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTeamPresenceRepository(db *sql.DB) TeamPresenceRepository {
+	return &teamPresenceRepositoryImpl{db: db}
+}
+
+// GetTeamPresence returns the latest attendance (with its running break), approved leave and
+// day type of every active employee of the tenant on the date, or only of the supervisor's
+// direct reports when supervisorID is set
+func (r *teamPresenceRepositoryImpl) GetTeamPresence(tenantID uuid.UUID, supervisorID *uuid.UUID, date string) ([]models.TeamPresenceRecord, error) {
+	query := `SELECT e.id, u.id, COALESCE(u.full_name, u.username), COALESCE(u.avatar_url, ''), COALESCE(p.name, 'Employee'),
+			TO_CHAR(a.attendance_date, 'YYYY-MM-DD'), COALESCE(a.check_in_time, a.created_at), a.check_out_time,
+			COALESCE(a.late_minutes, 0), COALESCE(a.work_mode, 'office'), a.id IS NOT NULL AND a.office_location_id IS NULL, COALESCE(ol.name, ''),
+			b.started_at, COALESCE(b.break_type, ''),
+			COALESCE(lv.name, ''),
+			COALESCE(
+				(SELECT CASE WHEN r.schedule_id IS NULL THEN 'day_off' ELSE '' END FROM godplan.shift_rosters r
+					WHERE r.tenant_id = e.tenant_id AND r.employee_id = e.id AND r.shift_date = $3::date LIMIT 1),
+				CASE
+					WHEN EXISTS (SELECT 1 FROM godplan.tenant_holidays WHERE tenant_id = e.tenant_id AND holiday_date = $3::date)
+					THEN 'holiday'
+					WHEN (COALESCE(
+						(SELECT NULLIF(s.working_days, 0) FROM godplan.attendance_schedules s
+							WHERE s.id = e.schedule_id AND COALESCE(s.is_active, true) = true),
+						(SELECT NULLIF(working_days, 0) FROM godplan.attendance_schedules
+							WHERE tenant_id = e.tenant_id AND is_default = true AND COALESCE(is_active, true) = true LIMIT 1),
+						(SELECT working_days FROM godplan.tenant_calendars WHERE tenant_id = e.tenant_id),
+						62) & (1 << EXTRACT(DOW FROM $3::date)::int)) = 0
+					THEN 'day_off'
+					ELSE ''
+				END)
+		FROM godplan.employees e
+		JOIN godplan.users u ON u.id = e.user_id AND u.is_active = true
+		LEFT JOIN godplan.positions p ON p.id = e.position_id
+		LEFT JOIN LATERAL (
+			SELECT id, attendance_date, check_in_time, created_at, check_out_time, late_minutes, work_mode, office_location_id
+			FROM godplan.attendances
+			WHERE user_id = u.id AND tenant_id = e.tenant_id
+			ORDER BY created_at DESC LIMIT 1
+		) a ON true
+		LEFT JOIN godplan.office_locations ol ON ol.id = a.office_location_id
+		LEFT JOIN godplan.attendance_breaks b ON b.attendance_id = a.id AND b.ended_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT lt.name FROM godplan.leave_requests lr
+			JOIN godplan.leave_types lt ON lt.id = lr.leave_type_id
+			WHERE lr.employee_id = e.id AND lr.tenant_id = e.tenant_id AND lr.status = 'approved'
+			AND $3::date BETWEEN lr.start_date AND lr.end_date
+			LIMIT 1
+		) lv ON true
+		WHERE e.tenant_id = $1 AND ($2::uuid IS NULL OR e.supervisor_id = $2)
+		ORDER BY COALESCE(u.full_name, u.username)`
+
+	rows, err := r.db.Query(query, tenantID, supervisorID, date)
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	defer rows.Close()
+
+	records := []models.TeamPresenceRecord{}
+	for rows.Next() {
+		var record models.TeamPresenceRecord
+		var attendanceDate sql.NullString
+		var checkIn, checkOut, breakStartedAt sql.NullTime
+		var defaultOffice bool
+		err := rows.Scan(
+			&record.EmployeeID,
+			&record.UserID,
+			&record.Name,
+			&record.AvatarURL,
+			&record.Position,
+			&attendanceDate,
+			&checkIn,
+			&checkOut,
+			&record.LateMinutes,
+			&record.WorkMode,
+			&defaultOffice,
+			&record.LastOfficeName,
+			&breakStartedAt,
+			&record.BreakType,
+			&record.LeaveType,
+			&record.DayType,
+		)
+		if err != nil {
+			return nil, utils.ErrInternalServer
+		}
+
+		record.LastCheckInDate = attendanceDate.String
+		record.LastCheckInAt = nullTimePtr(checkIn)
+		record.LastCheckOutAt = nullTimePtr(checkOut)
+		record.BreakStartedAt = nullTimePtr(breakStartedAt)
+		// Attendance WFH tidak terikat kantor, attendance lain tanpa office_location_id
+		// tercatat di kantor default (env var)
+		if record.WorkMode == models.WorkModeWFH {
+			record.LastOfficeName = models.WFHLocationName
+		} else if defaultOffice {
+			record.LastOfficeName = utils.DefaultOfficeName
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrTenantNotFound = errors.New("tenant not found")
+
+// TenantSettingsRepository reads and writes keys of the tenants.settings JSONB column
+// and the tenant timezone
+type TenantSettingsRepository interface {
+	GetSetting(tenantID uuid.UUID, key string, dest interface{}) (bool, error)
+	SaveSetting(tenantID uuid.UUID, key string, value interface{}) error
+	GetTimezone(tenantID uuid.UUID) (string, error)
+	SaveTimezone(tenantID uuid.UUID, timezone string) error
+}
+
+type tenantSettingsRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTenantSettingsRepository(db *sql.DB) TenantSettingsRepository {
+	return &tenantSettingsRepositoryImpl{db: db}
+}
+
+// GetSetting decodes the value stored under key into dest and reports whether the key is set.
+// dest is left untouched when the key is missing, so callers can pre-fill defaults.
+func (r *tenantSettingsRepositoryImpl) GetSetting(tenantID uuid.UUID, key string, dest interface{}) (bool, error) {
+	var raw []byte
+	err := r.db.QueryRow(`SELECT settings -> $2 FROM godplan.tenants WHERE id = $1`, tenantID, key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return false, ErrTenantNotFound
+	}
+	if err != nil {
+		return false, utils.ErrInternalServer
+	}
+	if raw == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, utils.ErrInternalServer
+	}
+	return true, nil
+}
+
+// SaveSetting stores value under key, keeping the other settings of the tenant
+func (r *tenantSettingsRepositoryImpl) SaveSetting(tenantID uuid.UUID, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+
+	result, err := r.db.Exec(`UPDATE godplan.tenants
+		SET settings = jsonb_set(COALESCE(settings, '{}'::jsonb), ARRAY[$2::text], $3::jsonb),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, tenantID, key, string(raw))
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrTenantNotFound
+	}
+	return nil
+}
+
+// GetTimezone returns the IANA timezone of the tenant
+func (r *tenantSettingsRepositoryImpl) GetTimezone(tenantID uuid.UUID) (string, error) {
+	var timezone string
+	err := r.db.QueryRow(`SELECT timezone FROM godplan.tenants WHERE id = $1`, tenantID).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return "", ErrTenantNotFound
+	}
+	if err != nil {
+		return "", utils.ErrInternalServer
+	}
+	return timezone, nil
+}
+
+func (r *tenantSettingsRepositoryImpl) SaveTimezone(tenantID uuid.UUID, timezone string) error {
+	result, err := r.db.Exec(`UPDATE godplan.tenants SET timezone = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		tenantID, timezone)
+	if err != nil {
+		return utils.ErrInternalServer
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrTenantNotFound
+	}
+	return nil
+}
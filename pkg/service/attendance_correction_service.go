@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrCorrectionTimesRequired = errors.New("propose a new check_in_time, check_out_time or both")
+	ErrCorrectionUnchanged     = errors.New("the proposed times are the same as the recorded attendance")
+	ErrSelfCorrectionApproval  = errors.New("you cannot approve or reject your own attendance correction")
+)
+
+// AttendanceCorrectionService defines business logic for attendance correction requests
+type AttendanceCorrectionService interface {
+	SubmitCorrection(request *models.AttendanceCorrectionRequest) error
+	GetCorrectionRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.AttendanceCorrectionRequest, error)
+	CancelCorrectionRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error
+	GetPendingCorrectionRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.AttendanceCorrectionRequest, error)
+	DecideCorrection(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error)
+	GetAuditLogs(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceAuditLog, error)
+}
+
+type attendanceCorrectionServiceImpl struct {
+	correctionRepo   repository.AttendanceCorrectionRepository
+	breakRepo        repository.BreakRepository
+	settingsRepo     repository.TenantSettingsRepository
+	notificationRepo repository.NotificationRepository
+	scheduleRepo     repository.ScheduleRepository
+	rosterRepo       repository.RosterRepository
+	calendarRepo     repository.CalendarRepository
+	overtimeRepo     repository.OvertimeRepository
+}
+
+func NewAttendanceCorrectionService(correctionRepo repository.AttendanceCorrectionRepository, breakRepo repository.BreakRepository, settingsRepo repository.TenantSettingsRepository, notificationRepo repository.NotificationRepository,
+	scheduleRepo repository.ScheduleRepository, rosterRepo repository.RosterRepository, calendarRepo repository.CalendarRepository, overtimeRepo repository.OvertimeRepository) AttendanceCorrectionService {
+	return &attendanceCorrectionServiceImpl{correctionRepo: correctionRepo, breakRepo: breakRepo, settingsRepo: settingsRepo, notificationRepo: notificationRepo,
+		scheduleRepo: scheduleRepo, rosterRepo: rosterRepo, calendarRepo: calendarRepo, overtimeRepo: overtimeRepo}
+}
+
+// SubmitCorrection validates and stores a pending correction of one of the user's own attendances
+func (s *attendanceCorrectionServiceImpl) SubmitCorrection(request *models.AttendanceCorrectionRequest) error {
+	if request.ProposedCheckInTime == nil && request.ProposedCheckOutTime == nil {
+		return ErrCorrectionTimesRequired
+	}
+
+	session, err := s.correctionRepo.GetAttendanceSession(request.TenantID, request.AttendanceID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != request.UserID {
+		return repository.ErrAttendanceNotFound
+	}
+
+	// Timestamp attendance disimpan dalam jam server, sama seperti clock in/out
+	request.ProposedCheckInTime = serverTime(request.ProposedCheckInTime)
+	request.ProposedCheckOutTime = serverTime(request.ProposedCheckOutTime)
+
+	checkIn, checkOut := correctedTimes(session, request)
+	if err := utils.ValidateSessionTimes(checkIn, checkOut, time.Now()); err != nil {
+		return err
+	}
+	if checkIn.Equal(session.CheckInTime) && sameTime(checkOut, session.CheckOutTime) {
+		return ErrCorrectionUnchanged
+	}
+
+	request.Reason = strings.TrimSpace(request.Reason)
+	request.Status = models.CorrectionStatusPending
+	return s.correctionRepo.CreateCorrectionRequest(request)
+}
+
+func (s *attendanceCorrectionServiceImpl) GetCorrectionRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.AttendanceCorrectionRequest, error) {
+	return s.correctionRepo.GetCorrectionRequests(tenantID, employeeID)
+}
+
+func (s *attendanceCorrectionServiceImpl) CancelCorrectionRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error {
+	request, err := s.correctionRepo.GetCorrectionRequestByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if request.EmployeeID != employeeID {
+		return repository.ErrCorrectionNotFound
+	}
+	return s.correctionRepo.CancelCorrectionRequest(tenantID, id, employeeID)
+}
+
+func (s *attendanceCorrectionServiceImpl) GetPendingCorrectionRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.AttendanceCorrectionRequest, error) {
+	if approver.CanApproveAll {
+		return s.correctionRepo.GetPendingCorrectionRequests(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.AttendanceCorrectionRequest{}, nil
+	}
+	return s.correctionRepo.GetPendingCorrectionRequests(tenantID, approver.EmployeeID)
+}
+
+// DecideCorrection approves or rejects a pending correction and returns its new status.
+// Approving writes the corrected times to the attendance, recomputes total_hours and the late,
+// early leave and overtime minutes and keeps the original values in the audit trail. The same
+// supervisor rules as attendance approval apply.
+func (s *attendanceCorrectionServiceImpl) DecideCorrection(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	request, err := s.correctionRepo.GetCorrectionRequestByID(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if request.UserID == approver.UserID {
+		return "", ErrSelfCorrectionApproval
+	}
+	if request.Status != models.CorrectionStatusPending {
+		return "", repository.ErrCorrectionNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.correctionRepo.IsEmployeeSupervisedBy(tenantID, request.EmployeeID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	if !approve {
+		if err := s.correctionRepo.RejectCorrection(tenantID, id, approver.UserID, reason); err != nil {
+			return "", err
+		}
+		s.notifyDecision(request, models.CorrectionStatusRejected, reason)
+		return models.CorrectionStatusRejected, nil
+	}
+
+	if err := s.applyCorrection(request, approver.UserID, reason); err != nil {
+		return "", err
+	}
+	s.notifyDecision(request, models.CorrectionStatusApproved, reason)
+	return models.CorrectionStatusApproved, nil
+}
+
+// applyCorrection menghitung ulang sesi attendance dengan jam usulan lalu menyimpannya
+func (s *attendanceCorrectionServiceImpl) applyCorrection(request *models.AttendanceCorrectionRequest, approvedBy uuid.UUID, reason string) error {
+	session, err := s.correctionRepo.GetAttendanceSession(request.TenantID, request.AttendanceID)
+	if err != nil {
+		return err
+	}
+
+	checkIn, checkOut := correctedTimes(session, request)
+	if err := utils.ValidateSessionTimes(checkIn, checkOut, time.Now()); err != nil {
+		return err
+	}
+
+	corrected := *session
+	corrected.CheckInTime = checkIn
+	corrected.CheckOutTime = checkOut
+	if request.ProposedCheckOutTime != nil {
+		corrected.AutoClosed = false
+	}
+	if checkOut != nil {
+		// Istirahat dipotong di jam pulang usulan, termasuk istirahat yang masih berjalan
+		breakMinutes, err := s.breakRepo.SumBreakMinutes(session.ID, *checkOut)
+		if err != nil {
+			return err
+		}
+		corrected.BreakMinutes = breakMinutes
+		corrected.TotalHours = utils.WorkedHours(checkIn, *checkOut, corrected.BreakMinutes)
+	}
+
+	// Terlambat, pulang cepat dan lembur dihitung ulang seperti saat Clock Out, dalam timezone
+	// attendance (attendance lama tanpa timezone memakai timezone tenant)
+	timezone := session.Timezone
+	if timezone == "" {
+		timezone, _ = s.settingsRepo.GetTimezone(session.TenantID)
+	}
+	stats := sessionTimeStats(s.scheduleRepo, s.rosterRepo, s.calendarRepo, session.TenantID, session.RosterID, session.ScheduleID,
+		session.AttendanceDate, utils.ResolveTimezone(timezone), checkIn, checkOut)
+	corrected.LateMinutes, corrected.EarlyLeaveMinutes, corrected.OvertimeMinutes = stats.LateMinutes, stats.EarlyLeaveMinutes, stats.OvertimeMinutes
+
+	if err := s.correctionRepo.ApplyCorrection(request, approvedBy, reason, session, &corrected); err != nil {
+		return err
+	}
+
+	// Istirahat yang masih berjalan baru ditutup setelah koreksi tersimpan, sehingga koreksi yang
+	// gagal tidak meninggalkan istirahat tertutup. total_hours di atas sudah memotongnya di jam
+	// pulang, kegagalan di sini hanya membuat istirahat tetap terbuka.
+	if session.CheckOutTime == nil && checkOut != nil {
+		closeSessionBreaks(s.breakRepo, s.settingsRepo, session.TenantID, session.ID, *checkOut)
+	}
+
+	// Lembur yang sudah disetujui direkonsiliasi dengan jam yang dikoreksi seperti saat Clock Out
+	// ("lupa Clock Out" adalah alasan koreksi yang paling umum). Koreksi tetap tersimpan walaupun
+	// rekonsiliasi gagal.
+	if checkOut != nil {
+		reconcileOvertimeSession(s.overtimeRepo, s.settingsRepo, session.TenantID, session.UserID, session.ID, checkIn, *checkOut)
+	}
+	return nil
+}
+
+// notifyDecision memberi tahu karyawan hasil koreksinya. Koreksi sudah tersimpan,
+// sehingga kegagalan notifikasi tidak membatalkan keputusan.
+func (s *attendanceCorrectionServiceImpl) notifyDecision(request *models.AttendanceCorrectionRequest, status string, reason string) {
+	title := "Koreksi absensi disetujui"
+	message := fmt.Sprintf("Koreksi absensi tanggal %s telah disetujui.", request.AttendanceDate)
+	if status == models.CorrectionStatusRejected {
+		title = "Koreksi absensi ditolak"
+		message = fmt.Sprintf("Koreksi absensi tanggal %s ditolak: %s", request.AttendanceDate, reason)
+	}
+	s.notificationRepo.CreateNotification(&models.Notification{
+		TenantID:    request.TenantID,
+		UserID:      request.UserID,
+		Type:        models.NotificationTypeAttendanceCorrection,
+		Title:       title,
+		Message:     message,
+		ReferenceID: &request.ID,
+	})
+}
+
+func (s *attendanceCorrectionServiceImpl) GetAuditLogs(tenantID uuid.UUID, attendanceID uuid.UUID) ([]models.AttendanceAuditLog, error) {
+	if _, err := s.correctionRepo.GetAttendanceSession(tenantID, attendanceID); err != nil {
+		return nil, err
+	}
+	return s.correctionRepo.GetAuditLogs(tenantID, attendanceID)
+}
+
+// correctedTimes returns the session times after the correction, an omitted proposal keeps
+// the recorded time
+func correctedTimes(session *models.AttendanceSession, request *models.AttendanceCorrectionRequest) (time.Time, *time.Time) {
+	checkIn := session.CheckInTime
+	if request.ProposedCheckInTime != nil {
+		checkIn = *request.ProposedCheckInTime
+	}
+	checkOut := session.CheckOutTime
+	if request.ProposedCheckOutTime != nil {
+		checkOut = request.ProposedCheckOutTime
+	}
+	return checkIn, checkOut
+}
+
+func serverTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(time.Local)
+	return &local
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// attendancePolicySettingKey is the tenants.settings key of the attendance policy
+const attendancePolicySettingKey = "attendance"
+
+// AttendancePolicyService defines business logic for the tenant clock in/out rules
+type AttendancePolicyService interface {
+	GetPolicy(tenantID uuid.UUID) (models.AttendancePolicy, error)
+	UpdatePolicy(tenantID uuid.UUID, policy models.AttendancePolicy) error
+}
+
+type attendancePolicyServiceImpl struct {
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewAttendancePolicyService(settingsRepo repository.TenantSettingsRepository) AttendancePolicyService {
+	return &attendancePolicyServiceImpl{settingsRepo: settingsRepo}
+}
+
+func (s *attendancePolicyServiceImpl) GetPolicy(tenantID uuid.UUID) (models.AttendancePolicy, error) {
+	return loadAttendancePolicy(s.settingsRepo, tenantID)
+}
+
+func (s *attendancePolicyServiceImpl) UpdatePolicy(tenantID uuid.UUID, policy models.AttendancePolicy) error {
+	return s.settingsRepo.SaveSetting(tenantID, attendancePolicySettingKey, policy)
+}
+
+// loadAttendancePolicy reads the tenant attendance policy, fields missing from the stored
+// setting keep their default value
+func loadAttendancePolicy(settingsRepo repository.TenantSettingsRepository, tenantID uuid.UUID) (models.AttendancePolicy, error) {
+	policy := utils.DefaultAttendancePolicy()
+	if _, err := settingsRepo.GetSetting(tenantID, attendancePolicySettingKey, &policy); err != nil {
+		return utils.DefaultAttendancePolicy(), err
+	}
+	return policy, nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrNotSupervisor           = errors.New("you are not the supervisor of this employee")
+	ErrSelfApproval            = errors.New("you cannot approve or reject your own attendance")
+	ErrRejectionReasonRequired = errors.New("rejection reason is required")
+	ErrInvalidReportPeriod     = errors.New("end_date must not be before start_date and the period must not exceed 366 days")
+)
+
+// AttendanceService defines business logic for attendance approval
+type AttendanceService interface {
+	GetPendingApprovals(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.PendingAttendance, error)
+	DecideAttendance(tenantID uuid.UUID, approver models.AttendanceApprover, attendanceID uuid.UUID, approve bool, reason string) (string, error)
+	BulkDecideAttendances(tenantID uuid.UUID, approver models.AttendanceApprover, req models.BulkAttendanceDecisionRequest) []models.AttendanceDecisionResult
+	GetAttendanceRecap(tenantID uuid.UUID, filter models.AttendanceRecapFilter) ([]models.AttendanceRecap, error)
+	AssessClockEvent(tenantID uuid.UUID, userID uuid.UUID, location utils.LocationCheckWithAccuracy, at time.Time, attendanceDate string) models.FraudAssessment
+	AssessSelfie(tenantID uuid.UUID, userID uuid.UUID, attendanceID *uuid.UUID, checkInHash *int64, hash *int64, capturedAt *time.Time, at time.Time) (models.FraudAssessment, *models.PhotoMatch)
+}
+
+type attendanceServiceImpl struct {
+	attendanceRepo repository.AttendanceRepository
+	calendarRepo   repository.CalendarRepository
+}
+
+func NewAttendanceService(attendanceRepo repository.AttendanceRepository, calendarRepo repository.CalendarRepository) AttendanceService {
+	return &attendanceServiceImpl{attendanceRepo: attendanceRepo, calendarRepo: calendarRepo}
+}
+
+func (s *attendanceServiceImpl) GetPendingApprovals(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.PendingAttendance, error) {
+	if approver.CanApproveAll {
+		return s.attendanceRepo.GetPendingAttendances(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.PendingAttendance{}, nil
+	}
+	return s.attendanceRepo.GetPendingAttendances(tenantID, approver.EmployeeID)
+}
+
+// DecideAttendance approves or rejects one pending attendance and returns its new status
+func (s *attendanceServiceImpl) DecideAttendance(tenantID uuid.UUID, approver models.AttendanceApprover, attendanceID uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	ownerID, status, err := s.attendanceRepo.GetAttendanceStatus(tenantID, attendanceID)
+	if err != nil {
+		return "", err
+	}
+	if ownerID == approver.UserID {
+		return "", ErrSelfApproval
+	}
+	if status != models.AttendanceStatusPending && status != models.AttendanceStatusPendingForced {
+		return "", repository.ErrAttendanceNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.attendanceRepo.IsSupervisedBy(tenantID, attendanceID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	newStatus := models.AttendanceStatusApproved
+	if !approve {
+		newStatus = models.AttendanceStatusRejected
+	}
+
+	if err := s.attendanceRepo.UpdateApprovalStatus(tenantID, attendanceID, newStatus, approver.UserID, reason); err != nil {
+		return "", err
+	}
+	return newStatus, nil
+}
+
+// BulkDecideAttendances applies the same decision to each attendance independently,
+// so one invalid ID does not block the rest of the batch
+func (s *attendanceServiceImpl) BulkDecideAttendances(tenantID uuid.UUID, approver models.AttendanceApprover, req models.BulkAttendanceDecisionRequest) []models.AttendanceDecisionResult {
+	approve := req.Action == "approve"
+	results := make([]models.AttendanceDecisionResult, 0, len(req.AttendanceIDs))
+
+	for _, attendanceID := range req.AttendanceIDs {
+		status, err := s.DecideAttendance(tenantID, approver, attendanceID, approve, req.Reason)
+		result := models.AttendanceDecisionResult{AttendanceID: attendanceID, Success: err == nil, Status: status}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// GetAttendanceRecap returns per-employee totals for the period. Working days only count
+// from the join date up to today, so a recap of the running month does not report future
+// days as absent. Tenant holidays and company days off are never working days.
+func (s *attendanceServiceImpl) GetAttendanceRecap(tenantID uuid.UUID, filter models.AttendanceRecapFilter) ([]models.AttendanceRecap, error) {
+	if filter.EndDate.Before(filter.StartDate) || filter.EndDate.Sub(filter.StartDate) > 366*24*time.Hour {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	recaps, err := s.attendanceRepo.GetAttendanceRecap(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.calendarRepo.GetHolidayDates(tenantID,
+		filter.StartDate.Format("2006-01-02"), filter.EndDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	calendar := utils.NewWorkCalendar(0, holidays)
+
+	countUntil := filter.EndDate
+	if today := time.Now(); countUntil.After(today) {
+		countUntil = today
+	}
+
+	for i := range recaps {
+		recap := &recaps[i]
+
+		// Hari sebelum karyawan bergabung tidak dihitung sebagai hari kerja
+		countFrom := filter.StartDate
+		if recap.JoinDate != nil && recap.JoinDate.After(countFrom) {
+			countFrom = *recap.JoinDate
+		}
+		if countUntil.Before(countFrom) {
+			continue
+		}
+		calendar.WorkingDays = recap.WorkingDaysMask
+		recap.WorkingDays = calendar.CountWorkingDays(countFrom, countUntil)
+		recap.DaysAbsent = recap.WorkingDays - recap.PresentOnWorkingDays
+		if recap.DaysAbsent < 0 {
+			recap.DaysAbsent = 0
+		}
+	}
+
+	return recaps, nil
+}
+
+// AssessClockEvent scores a clock in/out for GPS spoofing using the user's clock history.
+// attendanceDate is the local date of the session, coordinates reused on that date are not counted.
+// History lookup errors only drop the history based signals, they never block attendance.
+func (s *attendanceServiceImpl) AssessClockEvent(tenantID uuid.UUID, userID uuid.UUID, location utils.LocationCheckWithAccuracy, at time.Time, attendanceDate string) models.FraudAssessment {
+	signals := utils.FraudSignals{Location: location, Time: at}
+
+	if previous, err := s.attendanceRepo.GetLastClockEvent(tenantID, userID, at); err == nil {
+		signals.PreviousEvent = previous
+	}
+	if days, err := s.attendanceRepo.CountReusedCoordinateDays(tenantID, userID, location.Latitude, location.Longitude, attendanceDate); err == nil {
+		signals.ReusedOnDays = days
+	}
+
+	return utils.AssessFraudRisk(signals)
+}
+
+// photoReuseLookbackDays is how far back selfies of the tenant are compared for reuse
+const photoReuseLookbackDays = 30
+
+// AssessSelfie scores a clock in/out selfie for reuse against the selfies of the tenant in the
+// last photoReuseLookbackDays days and for a stale EXIF capture time. Lookup errors only drop
+// the reuse signal, they never block attendance.
+// At clock out attendanceID is the session being closed: it is left out of the reuse lookup and
+// its clock in selfie (checkInHash) is compared directly, so a repeat is flagged as repeated_photo.
+func (s *attendanceServiceImpl) AssessSelfie(tenantID uuid.UUID, userID uuid.UUID, attendanceID *uuid.UUID, checkInHash *int64, hash *int64, capturedAt *time.Time, at time.Time) (models.FraudAssessment, *models.PhotoMatch) {
+	signals := utils.SelfieSignals{UserID: userID, Time: at, CapturedAt: capturedAt}
+
+	if hash != nil {
+		since := at.AddDate(0, 0, -photoReuseLookbackDays).Format("2006-01-02")
+		if match, err := s.attendanceRepo.FindSimilarPhoto(tenantID, userID, attendanceID, *hash, since, utils.DuplicatePhotoDistance); err == nil {
+			signals.Match = match
+		}
+		if checkInHash != nil {
+			signals.SameAsCheckIn = utils.HammingDistance(uint64(*checkInHash), uint64(*hash)) <= utils.DuplicatePhotoDistance
+		}
+	}
+
+	return utils.AssessSelfie(signals), signals.Match
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+)
+
+// AttendanceSyncService keeps offline clock events idempotent by their client event ID
+type AttendanceSyncService interface {
+	// ClaimEvent returns (nil, existing) when the event was already synced before
+	ClaimEvent(event *models.AttendanceSyncEvent) (*models.AttendanceSyncEvent, error)
+	CompleteEvent(event *models.AttendanceSyncEvent, recordedAt *time.Time, result string, message string, attendanceID *uuid.UUID) error
+	ReleaseEvent(event *models.AttendanceSyncEvent) error
+}
+
+type attendanceSyncServiceImpl struct {
+	repo repository.AttendanceSyncRepository
+}
+
+func NewAttendanceSyncService(repo repository.AttendanceSyncRepository) AttendanceSyncService {
+	return &attendanceSyncServiceImpl{repo: repo}
+}
+
+func (s *attendanceSyncServiceImpl) ClaimEvent(event *models.AttendanceSyncEvent) (*models.AttendanceSyncEvent, error) {
+	claimed, err := s.repo.ClaimEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+	return s.repo.GetEvent(event.TenantID, event.UserID, event.ClientEventID)
+}
+
+func (s *attendanceSyncServiceImpl) CompleteEvent(event *models.AttendanceSyncEvent, recordedAt *time.Time, result string, message string, attendanceID *uuid.UUID) error {
+	event.RecordedAt = recordedAt
+	event.Result = result
+	event.Message = message
+	event.AttendanceID = attendanceID
+	return s.repo.CompleteEvent(event.ID, recordedAt, result, message, attendanceID)
+}
+
+func (s *attendanceSyncServiceImpl) ReleaseEvent(event *models.AttendanceSyncEvent) error {
+	return s.repo.ReleaseEvent(event.ID)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// autoClockOutSettingKey is the tenants.settings key of the auto clock-out policy
+const autoClockOutSettingKey = "auto_clock_out"
+
+// DefaultAutoClockOutPolicy applies to tenants that did not configure auto clock-out
+var DefaultAutoClockOutPolicy = models.AutoClockOutPolicy{
+	Enabled:             true,
+	GraceHours:          4,
+	DefaultSessionHours: 8,
+}
+
+// AutoClockOutService closes attendance sessions employees forgot to clock out of
+type AutoClockOutService interface {
+	GetPolicy(tenantID uuid.UUID) (models.AutoClockOutPolicy, error)
+	UpdatePolicy(tenantID uuid.UUID, policy models.AutoClockOutPolicy) error
+	CloseForgottenSessions(now time.Time) (int, error)
+}
+
+type autoClockOutServiceImpl struct {
+	attendanceRepo   repository.AttendanceRepository
+	settingsRepo     repository.TenantSettingsRepository
+	notificationRepo repository.NotificationRepository
+	breakRepo        repository.BreakRepository
+	overtimeRepo     repository.OvertimeRepository
+}
+
+func NewAutoClockOutService(attendanceRepo repository.AttendanceRepository, settingsRepo repository.TenantSettingsRepository, notificationRepo repository.NotificationRepository, breakRepo repository.BreakRepository, overtimeRepo repository.OvertimeRepository) AutoClockOutService {
+	return &autoClockOutServiceImpl{attendanceRepo: attendanceRepo, settingsRepo: settingsRepo, notificationRepo: notificationRepo, breakRepo: breakRepo, overtimeRepo: overtimeRepo}
+}
+
+func (s *autoClockOutServiceImpl) GetPolicy(tenantID uuid.UUID) (models.AutoClockOutPolicy, error) {
+	policy := DefaultAutoClockOutPolicy
+	if _, err := s.settingsRepo.GetSetting(tenantID, autoClockOutSettingKey, &policy); err != nil {
+		return DefaultAutoClockOutPolicy, err
+	}
+	return policy, nil
+}
+
+func (s *autoClockOutServiceImpl) UpdatePolicy(tenantID uuid.UUID, policy models.AutoClockOutPolicy) error {
+	return s.settingsRepo.SaveSetting(tenantID, autoClockOutSettingKey, policy)
+}
+
+// CloseForgottenSessions closes every open session whose cut-off (scheduled end, or check in
+// plus the default session length, plus the tenant grace hours) has passed. total_hours is
+// capped at the planned session minus breaks, running breaks are ended, approved overtime is
+// reconciled and the employee gets a notification to submit a correction.
+// A failure only skips the tenant or session it belongs to and is logged, so the other tenants
+// are still closed; the error is returned only when the open sessions cannot be listed.
+// Returns the number of sessions closed.
+func (s *autoClockOutServiceImpl) CloseForgottenSessions(now time.Time) (int, error) {
+	sessions, err := s.attendanceRepo.GetOpenSessions(now)
+	if err != nil {
+		return 0, err
+	}
+
+	policies := map[uuid.UUID]models.AutoClockOutPolicy{}
+	closed := 0
+	for _, session := range sessions {
+		policy, ok := policies[session.TenantID]
+		if !ok {
+			if policy, err = s.GetPolicy(session.TenantID); err != nil {
+				// Tenant dilewati sampai run berikutnya, policy-nya tidak diketahui
+				log.Printf("❌ [AutoClockOut] Tenant %s skipped, policy lookup failed: %v", session.TenantID, err)
+				policy = models.AutoClockOutPolicy{Enabled: false}
+			}
+			policies[session.TenantID] = policy
+		}
+		if !policy.Enabled {
+			continue
+		}
+
+		checkOut, cutOff := utils.AutoClockOutTimes(session.CheckInTime, scheduledEnd(session),
+			policy.GraceHours, policy.DefaultSessionHours)
+		if now.Before(cutOff) {
+			continue
+		}
+
+		// Istirahat yang masih berjalan dipotong di jam pulang otomatis dan baru ditutup setelah
+		// sesi tertutup
+		breakMinutes, err := s.breakRepo.SumBreakMinutes(session.ID, checkOut)
+		if err != nil {
+			log.Printf("❌ [AutoClockOut] Session %s skipped, break minutes failed: %v", session.ID, err)
+			continue
+		}
+		totalHours := utils.WorkedHours(session.CheckInTime, checkOut, breakMinutes)
+		updated, err := s.attendanceRepo.AutoCloseSession(session.ID, checkOut, totalHours, breakMinutes, now)
+		if err != nil {
+			log.Printf("❌ [AutoClockOut] Session %s skipped, close failed: %v", session.ID, err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+		closed++
+
+		// Sesi sudah tertutup, langkah berikut tidak membatalkannya
+		if _, err := closeSessionBreaks(s.breakRepo, s.settingsRepo, session.TenantID, session.ID, checkOut); err != nil {
+			log.Printf("⚠️ [AutoClockOut] Session %s closed, ending running break failed: %v", session.ID, err)
+		}
+
+		// AutoCloseSession mengosongkan lembur, lembur yang sudah disetujui direkonsiliasi
+		// dengan jam pulang otomatis seperti saat Clock Out
+		if err := reconcileOvertimeSession(s.overtimeRepo, s.settingsRepo, session.TenantID, session.UserID, session.ID, session.CheckInTime, checkOut); err != nil {
+			log.Printf("⚠️ [AutoClockOut] Session %s closed, overtime reconciliation failed: %v", session.ID, err)
+		}
+
+		if err := s.notificationRepo.CreateNotification(autoClockOutNotification(session, checkOut, totalHours)); err != nil {
+			log.Printf("⚠️ [AutoClockOut] Session %s closed, notification failed: %v", session.ID, err)
+		}
+	}
+
+	return closed, nil
+}
+
+// scheduledEnd returns the end of the session schedule anchored on the attendance date in the
+// session timezone, or the zero time when the session has no usable schedule
+func scheduledEnd(session models.OpenAttendanceSession) time.Time {
+	if session.Schedule == nil {
+		return time.Time{}
+	}
+	date, err := utils.ParseShiftDate(session.AttendanceDate, utils.ResolveTimezone(session.Timezone))
+	if err != nil {
+		return time.Time{}
+	}
+	_, end, err := utils.ScheduleWindow(session.Schedule, date)
+	if err != nil {
+		return time.Time{}
+	}
+	// Stored timestamps keep the location of check_in_time
+	return end.In(session.CheckInTime.Location())
+}
+
+func autoClockOutNotification(session models.OpenAttendanceSession, checkOut time.Time, totalHours float64) *models.Notification {
+	return &models.Notification{
+		TenantID: session.TenantID,
+		UserID:   session.UserID,
+		Type:     models.NotificationTypeAutoClockOut,
+		Title:    "Clock Out otomatis",
+		Message: fmt.Sprintf("Anda belum Clock Out untuk absensi tanggal %s. Sesi ditutup otomatis dengan jam pulang %s (%.1f jam kerja). "+
+			"Ajukan koreksi absensi jika jam pulang Anda berbeda.",
+			session.AttendanceDate, checkOut.In(utils.ResolveTimezone(session.Timezone)).Format("15:04"), totalHours),
+		ReferenceID: &session.ID,
+	}
+}
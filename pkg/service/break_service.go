@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// breakSettingKey is the tenants.settings key of the break policy
+const breakSettingKey = "breaks"
+
+// DefaultBreakPolicy applies to tenants that did not configure break limits
+var DefaultBreakPolicy = models.BreakPolicy{
+	MaxBreakMinutes:     60,
+	MaxTotalMinutes:     90,
+	MaxBreaksPerSession: 3,
+}
+
+var (
+	ErrBreakLimitReached  = errors.New("the maximum number of breaks for this session has been reached")
+	ErrBreakAllowanceUsed = errors.New("the break time allowance for this session has been used up")
+)
+
+// BreakService defines business logic for breaks inside attendance sessions
+type BreakService interface {
+	GetPolicy(tenantID uuid.UUID) (models.BreakPolicy, error)
+	UpdatePolicy(tenantID uuid.UUID, policy models.BreakPolicy) error
+	GetCurrentBreaks(tenantID uuid.UUID, userID uuid.UUID, now time.Time) (*models.AttendanceBreakSummary, error)
+	StartBreak(tenantID uuid.UUID, userID uuid.UUID, breakType string, now time.Time) (*models.AttendanceBreak, error)
+	EndBreak(tenantID uuid.UUID, userID uuid.UUID, now time.Time) (*models.AttendanceBreak, error)
+	SessionBreakMinutes(attendanceID uuid.UUID, at time.Time) (float64, error)
+	CloseSessionBreaks(tenantID uuid.UUID, attendanceID uuid.UUID, at time.Time) (float64, error)
+}
+
+type breakServiceImpl struct {
+	breakRepo    repository.BreakRepository
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewBreakService(breakRepo repository.BreakRepository, settingsRepo repository.TenantSettingsRepository) BreakService {
+	return &breakServiceImpl{breakRepo: breakRepo, settingsRepo: settingsRepo}
+}
+
+func (s *breakServiceImpl) GetPolicy(tenantID uuid.UUID) (models.BreakPolicy, error) {
+	return loadBreakPolicy(s.settingsRepo, tenantID)
+}
+
+func (s *breakServiceImpl) UpdatePolicy(tenantID uuid.UUID, policy models.BreakPolicy) error {
+	return s.settingsRepo.SaveSetting(tenantID, breakSettingKey, policy)
+}
+
+// GetCurrentBreaks returns the breaks of the open session and what is left of the allowance
+func (s *breakServiceImpl) GetCurrentBreaks(tenantID uuid.UUID, userID uuid.UUID, now time.Time) (*models.AttendanceBreakSummary, error) {
+	attendanceID, err := s.breakRepo.GetOpenAttendanceID(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := loadBreakPolicy(s.settingsRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := s.breakRepo.GetBreaks(tenantID, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.AttendanceBreakSummary{AttendanceID: attendanceID, Breaks: breaks, Policy: policy}
+	for i := range breaks {
+		if breaks[i].EndedAt == nil {
+			// Istirahat yang sedang berjalan dihitung sampai sekarang
+			summary.OnBreak = true
+			breaks[i].DurationMinutes = math.Max(0, now.Sub(breaks[i].StartedAt).Minutes())
+		}
+		summary.UsedMinutes += breaks[i].DurationMinutes
+	}
+	if policy.MaxTotalMinutes > 0 {
+		remaining := math.Max(0, float64(policy.MaxTotalMinutes)-summary.UsedMinutes)
+		summary.RemainingMinutes = &remaining
+	}
+	return summary, nil
+}
+
+// StartBreak starts a break in the open session unless one is running or the tenant
+// limits on the number of breaks or the total break time are reached
+func (s *breakServiceImpl) StartBreak(tenantID uuid.UUID, userID uuid.UUID, breakType string, now time.Time) (*models.AttendanceBreak, error) {
+	attendanceID, err := s.breakRepo.GetOpenAttendanceID(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := loadBreakPolicy(s.settingsRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := s.breakRepo.GetBreaks(tenantID, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	used := 0.0
+	for _, b := range breaks {
+		if b.EndedAt == nil {
+			return nil, repository.ErrBreakAlreadyStarted
+		}
+		used += b.DurationMinutes
+	}
+	if policy.MaxBreaksPerSession > 0 && len(breaks) >= policy.MaxBreaksPerSession {
+		return nil, ErrBreakLimitReached
+	}
+	if policy.MaxTotalMinutes > 0 && used >= float64(policy.MaxTotalMinutes) {
+		return nil, ErrBreakAllowanceUsed
+	}
+
+	if breakType == "" {
+		breakType = models.BreakTypeOther
+	}
+	attendanceBreak := &models.AttendanceBreak{
+		TenantID:     tenantID,
+		AttendanceID: attendanceID,
+		UserID:       userID,
+		BreakType:    breakType,
+		StartedAt:    now,
+	}
+	if err := s.breakRepo.CreateBreak(attendanceBreak); err != nil {
+		return nil, err
+	}
+	return attendanceBreak, nil
+}
+
+// EndBreak ends the running break of the open session. Minutes over the tenant limits are
+// recorded as exceeded_minutes; the whole break is still not counted as worked time.
+func (s *breakServiceImpl) EndBreak(tenantID uuid.UUID, userID uuid.UUID, now time.Time) (*models.AttendanceBreak, error) {
+	attendanceID, err := s.breakRepo.GetOpenAttendanceID(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	attendanceBreak, err := s.breakRepo.GetOpenBreak(tenantID, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := finishBreak(s.breakRepo, s.settingsRepo, attendanceBreak, now); err != nil {
+		return nil, err
+	}
+	return attendanceBreak, nil
+}
+
+// SessionBreakMinutes returns the break minutes of the session up to the given time, a break
+// still running is counted until then. Nothing is closed, so a rejected clock out keeps it running.
+func (s *breakServiceImpl) SessionBreakMinutes(attendanceID uuid.UUID, at time.Time) (float64, error) {
+	return s.breakRepo.SumBreakMinutes(attendanceID, at)
+}
+
+// CloseSessionBreaks ends a break still running at clock out and returns the break minutes
+// of the session up to that time, to be subtracted from total_hours
+func (s *breakServiceImpl) CloseSessionBreaks(tenantID uuid.UUID, attendanceID uuid.UUID, at time.Time) (float64, error) {
+	return closeSessionBreaks(s.breakRepo, s.settingsRepo, tenantID, attendanceID, at)
+}
+
+func loadBreakPolicy(settingsRepo repository.TenantSettingsRepository, tenantID uuid.UUID) (models.BreakPolicy, error) {
+	policy := DefaultBreakPolicy
+	if _, err := settingsRepo.GetSetting(tenantID, breakSettingKey, &policy); err != nil {
+		return DefaultBreakPolicy, err
+	}
+	return policy, nil
+}
+
+func closeSessionBreaks(breakRepo repository.BreakRepository, settingsRepo repository.TenantSettingsRepository, tenantID uuid.UUID, attendanceID uuid.UUID, at time.Time) (float64, error) {
+	attendanceBreak, err := breakRepo.GetOpenBreak(tenantID, attendanceID)
+	if err != nil && err != repository.ErrNoActiveBreak {
+		return 0, err
+	}
+	if attendanceBreak != nil {
+		if err := finishBreak(breakRepo, settingsRepo, attendanceBreak, at); err != nil {
+			return 0, err
+		}
+	}
+	return breakRepo.SumBreakMinutes(attendanceID, at)
+}
+
+// finishBreak ends a running break at the given time (not before its start)
+func finishBreak(breakRepo repository.BreakRepository, settingsRepo repository.TenantSettingsRepository, attendanceBreak *models.AttendanceBreak, at time.Time) error {
+	policy, err := loadBreakPolicy(settingsRepo, attendanceBreak.TenantID)
+	if err != nil {
+		return err
+	}
+	breaks, err := breakRepo.GetBreaks(attendanceBreak.TenantID, attendanceBreak.AttendanceID)
+	if err != nil {
+		return err
+	}
+	usedBefore := 0.0
+	for _, b := range breaks {
+		if b.ID != attendanceBreak.ID {
+			usedBefore += b.DurationMinutes
+		}
+	}
+
+	if at.Before(attendanceBreak.StartedAt) {
+		at = attendanceBreak.StartedAt
+	}
+	attendanceBreak.EndedAt = &at
+	attendanceBreak.DurationMinutes = at.Sub(attendanceBreak.StartedAt).Minutes()
+	attendanceBreak.ExceededMinutes = utils.BreakExceededMinutes(attendanceBreak.DurationMinutes, usedBefore,
+		policy.MaxBreakMinutes, policy.MaxTotalMinutes)
+	return breakRepo.EndBreak(attendanceBreak)
+}
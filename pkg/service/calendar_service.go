@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrInvalidHolidayDate = errors.New("date must use YYYY-MM-DD format")
+	ErrHolidaySetMissing  = errors.New("no bundled national holidays for this year")
+)
+
+// CalendarService defines business logic for the tenant working-day calendar
+type CalendarService interface {
+	GetCalendar(tenantID uuid.UUID, year int) (*models.TenantCalendar, error)
+	UpdateWorkingDays(tenantID uuid.UUID, workingDays int) error
+	CreateHoliday(holiday *models.Holiday) error
+	DeleteHoliday(tenantID uuid.UUID, id uuid.UUID) error
+	ImportNationalHolidays(tenantID uuid.UUID, year int) (int, error)
+	ResolveSchedule(tenantID uuid.UUID, schedule *models.AttendanceSchedule, date time.Time) (*models.AttendanceSchedule, bool)
+}
+
+type calendarServiceImpl struct {
+	calendarRepo repository.CalendarRepository
+}
+
+func NewCalendarService(calendarRepo repository.CalendarRepository) CalendarService {
+	return &calendarServiceImpl{calendarRepo: calendarRepo}
+}
+
+func (s *calendarServiceImpl) GetCalendar(tenantID uuid.UUID, year int) (*models.TenantCalendar, error) {
+	workingDays, err := s.calendarRepo.GetWorkingDays(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.calendarRepo.GetHolidays(tenantID, fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-12-31", year))
+	if err != nil {
+		return nil, err
+	}
+	return &models.TenantCalendar{TenantID: tenantID, Year: year, WorkingDays: workingDays, Holidays: holidays}, nil
+}
+
+func (s *calendarServiceImpl) UpdateWorkingDays(tenantID uuid.UUID, workingDays int) error {
+	return s.calendarRepo.UpdateWorkingDays(tenantID, workingDays)
+}
+
+func (s *calendarServiceImpl) CreateHoliday(holiday *models.Holiday) error {
+	date, err := time.Parse("2006-01-02", holiday.Date)
+	if err != nil {
+		return ErrInvalidHolidayDate
+	}
+	holiday.Date = date.Format("2006-01-02")
+	if holiday.Type == "" {
+		holiday.Type = models.HolidayTypeCompany
+	}
+	return s.calendarRepo.CreateHoliday(holiday)
+}
+
+func (s *calendarServiceImpl) DeleteHoliday(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.calendarRepo.DeleteHoliday(tenantID, id)
+}
+
+// ImportNationalHolidays copies the bundled Indonesian holidays of a year into the tenant
+// calendar and returns how many were added. Dates the tenant already has are kept as is.
+func (s *calendarServiceImpl) ImportNationalHolidays(tenantID uuid.UUID, year int) (int, error) {
+	national, ok := utils.IndonesianHolidays(year)
+	if !ok {
+		return 0, ErrHolidaySetMissing
+	}
+
+	holidays := make([]models.Holiday, 0, len(national))
+	for _, holiday := range national {
+		holidays = append(holidays, models.Holiday{
+			TenantID: tenantID,
+			Date:     holiday.Date,
+			Name:     holiday.Name,
+			Type:     models.HolidayTypeNational,
+		})
+	}
+	return s.calendarRepo.ImportHolidays(tenantID, holidays)
+}
+
+// ResolveSchedule returns the schedule with the tenant working weekdays filled in when the
+// schedule has none, and whether date is a working date. Calendar lookup errors are treated
+// as a working day so attendance never fails because of the calendar.
+func (s *calendarServiceImpl) ResolveSchedule(tenantID uuid.UUID, schedule *models.AttendanceSchedule, date time.Time) (*models.AttendanceSchedule, bool) {
+	return resolveSchedule(s.calendarRepo, tenantID, schedule, date)
+}
+
+func resolveSchedule(calendarRepo repository.CalendarRepository, tenantID uuid.UUID, schedule *models.AttendanceSchedule, date time.Time) (*models.AttendanceSchedule, bool) {
+	calendar, err := loadWorkCalendar(calendarRepo, tenantID, schedule.WorkingDays, date, date)
+	if err != nil {
+		return schedule, utils.IsWorkingDay(schedule.WorkingDays, date.Weekday())
+	}
+
+	resolved := *schedule
+	resolved.WorkingDays = calendar.WorkingDays
+	return &resolved, calendar.IsWorkingDate(date)
+}
+
+// loadWorkCalendar builds the calendar of a period. Schedules without working days
+// (workingDays = 0) follow the tenant working weekdays.
+func loadWorkCalendar(calendarRepo repository.CalendarRepository, tenantID uuid.UUID, workingDays int, start, end time.Time) (utils.WorkCalendar, error) {
+	if workingDays == 0 {
+		tenantDays, err := calendarRepo.GetWorkingDays(tenantID)
+		if err != nil {
+			return utils.WorkCalendar{}, err
+		}
+		workingDays = tenantDays
+	}
+
+	holidays, err := calendarRepo.GetHolidayDates(tenantID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return utils.WorkCalendar{}, err
+	}
+	return utils.NewWorkCalendar(workingDays, holidays), nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrVisitProjectNotFound = errors.New("CRM project not found")
+	ErrVisitClientRequired  = errors.New("client is required when the CRM deal has no client")
+	ErrVisitForbidden       = errors.New("you are not allowed to view these client visits")
+	ErrInvalidVisitDate     = errors.New("date must use the YYYY-MM-DD format")
+)
+
+// ClientVisitService defines business logic for geotagged client visits
+type ClientVisitService interface {
+	CheckIn(visit *models.ClientVisit) error
+	CheckOut(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID, checkOut models.ClientVisit) (*models.ClientVisit, error)
+	GetOpenVisit(tenantID uuid.UUID, employeeID uuid.UUID) (*models.ClientVisit, error)
+	GetProjectVisits(tenantID uuid.UUID, approver models.AttendanceApprover, projectID uuid.UUID) ([]models.ClientVisit, error)
+	GetDailyReport(tenantID uuid.UUID, approver models.AttendanceApprover, employeeID uuid.UUID, date string, now time.Time) (*models.DailyVisitReport, error)
+}
+
+type clientVisitServiceImpl struct {
+	visitRepo    repository.ClientVisitRepository
+	crmRepo      repository.CRMRepository
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewClientVisitService(visitRepo repository.ClientVisitRepository, crmRepo repository.CRMRepository, settingsRepo repository.TenantSettingsRepository) ClientVisitService {
+	return &clientVisitServiceImpl{visitRepo: visitRepo, crmRepo: crmRepo, settingsRepo: settingsRepo}
+}
+
+func (s *clientVisitServiceImpl) tenantLocation(tenantID uuid.UUID) *time.Location {
+	timezone, _ := s.settingsRepo.GetTimezone(tenantID)
+	return utils.ResolveTimezone(timezone)
+}
+
+func (s *clientVisitServiceImpl) getProject(tenantID uuid.UUID, projectID uuid.UUID) (*models.CRMProject, error) {
+	project, err := s.crmRepo.GetProjectByID(tenantID, projectID)
+	if err == repository.ErrTaskNotFound {
+		return nil, ErrVisitProjectNotFound
+	}
+	return project, err
+}
+
+// CheckIn starts a visit to the client of a CRM deal. The client defaults to the client of
+// the deal and the visit date follows the tenant timezone.
+func (s *clientVisitServiceImpl) CheckIn(visit *models.ClientVisit) error {
+	project, err := s.getProject(visit.TenantID, visit.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	visit.Client = strings.TrimSpace(visit.Client)
+	if visit.Client == "" {
+		visit.Client = strings.TrimSpace(project.Client)
+	}
+	if visit.Client == "" {
+		return ErrVisitClientRequired
+	}
+	visit.ProjectTitle = project.Title
+	visit.CheckInNotes = strings.TrimSpace(visit.CheckInNotes)
+	visit.VisitDate = visit.CheckInTime.In(s.tenantLocation(visit.TenantID)).Format("2006-01-02")
+
+	return s.visitRepo.CreateVisit(visit)
+}
+
+// CheckOut closes one of the employee's own ongoing visits, recording the duration and how far
+// the check-out happened from the check-in location
+func (s *clientVisitServiceImpl) CheckOut(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID, checkOut models.ClientVisit) (*models.ClientVisit, error) {
+	visit, err := s.visitRepo.GetVisitByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if visit.EmployeeID != employeeID {
+		return nil, repository.ErrClientVisitNotFound
+	}
+	if visit.CheckOutTime != nil {
+		return nil, repository.ErrClientVisitClosed
+	}
+
+	distance := utils.CalculateDistance(visit.CheckInLatitude, visit.CheckInLongitude,
+		*checkOut.CheckOutLatitude, *checkOut.CheckOutLongitude)
+	visit.CheckOutTime = checkOut.CheckOutTime
+	visit.CheckOutLatitude = checkOut.CheckOutLatitude
+	visit.CheckOutLongitude = checkOut.CheckOutLongitude
+	visit.CheckOutAccuracy = checkOut.CheckOutAccuracy
+	visit.CheckOutPhoto = checkOut.CheckOutPhoto
+	visit.CheckOutNotes = strings.TrimSpace(checkOut.CheckOutNotes)
+	visit.CheckOutDistance = &distance
+	visit.DurationMinutes = utils.VisitDurationMinutes(visit.CheckInTime, *visit.CheckOutTime)
+
+	if err := s.visitRepo.CheckOutVisit(visit); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+func (s *clientVisitServiceImpl) GetOpenVisit(tenantID uuid.UUID, employeeID uuid.UUID) (*models.ClientVisit, error) {
+	return s.visitRepo.GetOpenVisit(tenantID, employeeID)
+}
+
+// GetProjectVisits returns the visit history of a deal. Admin/HR, the deal manager and
+// employees who visited the deal themselves may view it.
+func (s *clientVisitServiceImpl) GetProjectVisits(tenantID uuid.UUID, approver models.AttendanceApprover, projectID uuid.UUID) ([]models.ClientVisit, error) {
+	project, err := s.getProject(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return nil, ErrVisitForbidden
+		}
+		if project.ManagerID != *approver.EmployeeID {
+			visited, err := s.visitRepo.HasVisitedProject(tenantID, *approver.EmployeeID, projectID)
+			if err != nil {
+				return nil, err
+			}
+			if !visited {
+				return nil, ErrVisitForbidden
+			}
+		}
+	}
+
+	visits, err := s.visitRepo.GetProjectVisits(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	loc := s.tenantLocation(tenantID)
+	for i := range visits {
+		visits[i].CheckInTime = visits[i].CheckInTime.In(loc)
+		if visits[i].CheckOutTime != nil {
+			checkOut := visits[i].CheckOutTime.In(loc)
+			visits[i].CheckOutTime = &checkOut
+		}
+	}
+	return visits, nil
+}
+
+// GetDailyReport returns the visits of a salesperson on date (today in the tenant timezone when
+// empty). Besides their own report, supervisors may view their direct reports and admin/HR anyone.
+func (s *clientVisitServiceImpl) GetDailyReport(tenantID uuid.UUID, approver models.AttendanceApprover, employeeID uuid.UUID, date string, now time.Time) (*models.DailyVisitReport, error) {
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return nil, ErrVisitForbidden
+		}
+		if *approver.EmployeeID != employeeID {
+			supervised, err := s.visitRepo.IsEmployeeSupervisedBy(tenantID, employeeID, *approver.EmployeeID)
+			if err != nil {
+				return nil, err
+			}
+			if !supervised {
+				return nil, ErrVisitForbidden
+			}
+		}
+	}
+
+	loc := s.tenantLocation(tenantID)
+	if date == "" {
+		date = now.In(loc).Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, ErrInvalidVisitDate
+	}
+
+	visits, err := s.visitRepo.GetEmployeeVisits(tenantID, employeeID, date)
+	if err != nil {
+		return nil, err
+	}
+	report := utils.SummarizeVisits(date, employeeID, visits, now, loc)
+	return &report, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// maxSimulationDays limits how many days of clock ins one simulation replays
+const maxSimulationDays = 92
+
+var (
+	ErrInvalidSimulationPeriod = errors.New("start_date and end_date must use YYYY-MM-DD, end_date must not be before start_date and the period must not exceed 92 days")
+	ErrUnknownSimulationOffice = errors.New("office_radii refers to an office location that is not active in this tenant")
+)
+
+// GeofenceSimulationService replays past clock ins against a proposed geofence configuration
+type GeofenceSimulationService interface {
+	Simulate(tenantID uuid.UUID, req models.GeofenceSimulationRequest) (*models.GeofenceSimulationReport, error)
+}
+
+type geofenceSimulationServiceImpl struct {
+	attendanceRepo repository.AttendanceRepository
+	locationRepo   repository.OfficeLocationRepository
+	settingsRepo   repository.TenantSettingsRepository
+}
+
+func NewGeofenceSimulationService(attendanceRepo repository.AttendanceRepository, locationRepo repository.OfficeLocationRepository, settingsRepo repository.TenantSettingsRepository) GeofenceSimulationService {
+	return &geofenceSimulationServiceImpl{attendanceRepo: attendanceRepo, locationRepo: locationRepo, settingsRepo: settingsRepo}
+}
+
+// Simulate is a dry run: nothing is written, the current configuration is compared with the
+// proposed radius overrides and attendance policy over the GPS clock ins of the period
+func (s *geofenceSimulationServiceImpl) Simulate(tenantID uuid.UUID, req models.GeofenceSimulationRequest) (*models.GeofenceSimulationReport, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidSimulationPeriod
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil || end.Before(start) || end.Sub(start) >= maxSimulationDays*24*time.Hour {
+		return nil, ErrInvalidSimulationPeriod
+	}
+
+	// Sama seperti GetGeofences: tenant tanpa kantor terdaftar memakai kantor dari env var
+	offices, err := s.locationRepo.GetActiveLocations(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(offices) == 0 {
+		offices = []models.OfficeLocation{utils.DefaultOfficeLocation()}
+	}
+	for _, override := range req.OfficeRadii {
+		if !hasOffice(offices, override.OfficeLocationID) {
+			return nil, ErrUnknownSimulationOffice
+		}
+	}
+
+	currentPolicy, err := loadAttendancePolicy(s.settingsRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	proposedPolicy := currentPolicy
+	if req.Policy != nil {
+		proposedPolicy = *req.Policy
+	}
+
+	samples, err := s.attendanceRepo.GetGeofenceReplaySamples(tenantID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	proposedOffices := utils.ApplyRadiusOverrides(offices, req.Radius, req.OfficeRadii)
+	report := utils.SimulateGeofence(samples, offices, currentPolicy, proposedOffices, proposedPolicy)
+	report.StartDate, report.EndDate = req.StartDate, req.EndDate
+	return &report, nil
+}
+
+func hasOffice(offices []models.OfficeLocation, id uuid.UUID) bool {
+	for _, office := range offices {
+		if office.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// KioskService defines business logic for QR code attendance kiosks
+type KioskService interface {
+	RegisterKiosk(kiosk *models.AttendanceKiosk) (*models.AttendanceKioskRegistration, error)
+	GetKiosks(tenantID uuid.UUID) ([]models.AttendanceKiosk, error)
+	RotateKioskToken(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceKioskRegistration, error)
+	DeleteKiosk(tenantID uuid.UUID, id uuid.UUID) error
+	AuthenticateKiosk(token string) (*models.AttendanceKiosk, error)
+	IssueCode(kiosk *models.AttendanceKiosk, at time.Time) models.KioskCode
+	VerifyCode(tenantID uuid.UUID, code string, at time.Time) (*models.AttendanceKiosk, error)
+}
+
+type kioskServiceImpl struct {
+	kioskRepo    repository.KioskRepository
+	locationRepo repository.OfficeLocationRepository
+}
+
+func NewKioskService(kioskRepo repository.KioskRepository, locationRepo repository.OfficeLocationRepository) KioskService {
+	return &kioskServiceImpl{kioskRepo: kioskRepo, locationRepo: locationRepo}
+}
+
+// RegisterKiosk creates a kiosk for an existing office. The returned token is only
+// available here; the database keeps its SHA-256 hash.
+func (s *kioskServiceImpl) RegisterKiosk(kiosk *models.AttendanceKiosk) (*models.AttendanceKioskRegistration, error) {
+	office, err := s.locationRepo.GetLocationByID(kiosk.TenantID, kiosk.OfficeLocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, token, err := generateKioskCredentials()
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+	kiosk.Secret = secret
+	kiosk.OfficeName = office.Name
+
+	if err := s.kioskRepo.CreateKiosk(kiosk, utils.HashToken(token)); err != nil {
+		return nil, err
+	}
+	return &models.AttendanceKioskRegistration{Kiosk: kiosk, Token: token}, nil
+}
+
+func (s *kioskServiceImpl) GetKiosks(tenantID uuid.UUID) ([]models.AttendanceKiosk, error) {
+	return s.kioskRepo.GetKiosks(tenantID)
+}
+
+// RotateKioskToken issues a new token and signing secret, e.g. when a tablet is lost
+func (s *kioskServiceImpl) RotateKioskToken(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceKioskRegistration, error) {
+	secret, token, err := generateKioskCredentials()
+	if err != nil {
+		return nil, utils.ErrInternalServer
+	}
+
+	kiosk, err := s.kioskRepo.UpdateKioskToken(tenantID, id, secret, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	return &models.AttendanceKioskRegistration{Kiosk: kiosk, Token: token}, nil
+}
+
+func (s *kioskServiceImpl) DeleteKiosk(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.kioskRepo.DeleteKiosk(tenantID, id)
+}
+
+// AuthenticateKiosk resolves the kiosk owning the token and records that it is online
+func (s *kioskServiceImpl) AuthenticateKiosk(token string) (*models.AttendanceKiosk, error) {
+	if token == "" {
+		return nil, repository.ErrKioskNotFound
+	}
+
+	kiosk, err := s.kioskRepo.GetKioskByTokenHash(utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	// last_seen_at hanya untuk monitoring, kegagalan tidak menghentikan kiosk
+	_ = s.kioskRepo.TouchKiosk(kiosk.ID)
+	return kiosk, nil
+}
+
+func (s *kioskServiceImpl) IssueCode(kiosk *models.AttendanceKiosk, at time.Time) models.KioskCode {
+	code, expiresAt := utils.GenerateKioskCode([]byte(kiosk.Secret), kiosk.ID, at)
+	return models.KioskCode{
+		KioskID:    kiosk.ID,
+		OfficeName: kiosk.OfficeName,
+		Code:       code,
+		ExpiresAt:  expiresAt,
+		Period:     int(utils.KioskCodePeriod / time.Second),
+	}
+}
+
+// VerifyCode validates a scanned QR code for the tenant and returns the kiosk that
+// displayed it. Codes of another tenant, a deleted kiosk or an expired period are
+// all reported as utils.ErrInvalidKioskCode.
+func (s *kioskServiceImpl) VerifyCode(tenantID uuid.UUID, code string, at time.Time) (*models.AttendanceKiosk, error) {
+	kioskID, err := utils.ParseKioskCodeID(code)
+	if err != nil {
+		return nil, err
+	}
+
+	kiosk, err := s.kioskRepo.GetKioskByID(kioskID)
+	if err == repository.ErrKioskNotFound {
+		return nil, utils.ErrInvalidKioskCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if kiosk.TenantID != tenantID || !utils.VerifyKioskCode([]byte(kiosk.Secret), code, at) {
+		return nil, utils.ErrInvalidKioskCode
+	}
+	return kiosk, nil
+}
+
+func generateKioskCredentials() (secret string, token string, err error) {
+	if secret, err = utils.GenerateRandomToken(32); err != nil {
+		return "", "", err
+	}
+	if token, err = utils.GenerateRandomToken(32); err != nil {
+		return "", "", err
+	}
+	return secret, token, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrInvalidLeavePeriod       = errors.New("start_date and end_date must use YYYY-MM-DD and end_date must not be before start_date")
+	ErrLeaveCrossesYear         = errors.New("leave balances are per year, split a leave across the new year into one request ending on 31 December and one starting on 1 January")
+	ErrNoLeaveWorkingDays       = errors.New("the leave period does not contain any working day")
+	ErrLeaveOverlap             = errors.New("another pending or approved leave request already covers part of this period")
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance for this request")
+	ErrLeaveAttachmentRequired  = errors.New("this leave type requires a supporting document attachment")
+	ErrLeaveTypeInactive        = errors.New("this leave type is no longer available")
+	ErrSelfLeaveApproval        = errors.New("you cannot approve or reject your own leave request")
+)
+
+// LeaveService defines business logic for leave types, balances and requests
+type LeaveService interface {
+	GetLeaveTypes(tenantID uuid.UUID) ([]models.LeaveType, error)
+	GetLeaveTypeByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveType, error)
+	CreateLeaveType(leaveType *models.LeaveType) error
+	UpdateLeaveType(leaveType *models.LeaveType) error
+	DeleteLeaveType(tenantID uuid.UUID, id uuid.UUID) error
+
+	AccrueBalances(tenantID uuid.UUID, year int, employeeID *uuid.UUID) (int, error)
+	GetLeaveBalances(tenantID uuid.UUID, employeeID uuid.UUID, year int) ([]models.LeaveBalance, error)
+
+	SubmitLeaveRequest(request *models.LeaveRequest) error
+	GetLeaveRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveRequest, error)
+	GetLeaveRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.LeaveRequest, error)
+	CancelLeaveRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error
+	GetPendingLeaveRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.LeaveRequest, error)
+	DecideLeaveRequest(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error)
+}
+
+type leaveServiceImpl struct {
+	leaveRepo    repository.LeaveRepository
+	scheduleRepo repository.ScheduleRepository
+	calendarRepo repository.CalendarRepository
+}
+
+func NewLeaveService(leaveRepo repository.LeaveRepository, scheduleRepo repository.ScheduleRepository, calendarRepo repository.CalendarRepository) LeaveService {
+	return &leaveServiceImpl{leaveRepo: leaveRepo, scheduleRepo: scheduleRepo, calendarRepo: calendarRepo}
+}
+
+// GetLeaveTypes returns the tenant leave types. Tenants created after migration 016
+// get the default types (cuti tahunan, sakit, izin, cuti tidak dibayar) on first use.
+func (s *leaveServiceImpl) GetLeaveTypes(tenantID uuid.UUID) ([]models.LeaveType, error) {
+	leaveTypes, err := s.leaveRepo.GetLeaveTypes(tenantID)
+	if err != nil || len(leaveTypes) > 0 {
+		return leaveTypes, err
+	}
+
+	if err := s.leaveRepo.SeedDefaultLeaveTypes(tenantID); err != nil {
+		return nil, err
+	}
+	return s.leaveRepo.GetLeaveTypes(tenantID)
+}
+
+func (s *leaveServiceImpl) GetLeaveTypeByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveType, error) {
+	return s.leaveRepo.GetLeaveTypeByID(tenantID, id)
+}
+
+func (s *leaveServiceImpl) CreateLeaveType(leaveType *models.LeaveType) error {
+	leaveType.Code = strings.ToLower(strings.TrimSpace(leaveType.Code))
+	return s.leaveRepo.CreateLeaveType(leaveType)
+}
+
+func (s *leaveServiceImpl) UpdateLeaveType(leaveType *models.LeaveType) error {
+	leaveType.Code = strings.ToLower(strings.TrimSpace(leaveType.Code))
+	return s.leaveRepo.UpdateLeaveType(leaveType)
+}
+
+func (s *leaveServiceImpl) DeleteLeaveType(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.leaveRepo.DeleteLeaveType(tenantID, id)
+}
+
+// AccrueBalances creates the yearly balances of balance-tracked leave types for all
+// employees (or one employee) that do not have them yet. The quota is prorated in the
+// join year and unused days of the previous year are carried over up to the type limit.
+// Returns the number of balances created.
+func (s *leaveServiceImpl) AccrueBalances(tenantID uuid.UUID, year int, employeeID *uuid.UUID) (int, error) {
+	candidates, err := s.leaveRepo.GetAccrualCandidates(tenantID, year, employeeID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, candidate := range candidates {
+		entitled := utils.ProrateLeaveQuota(candidate.AnnualQuota, candidate.JoinDate, year)
+		carriedOver := utils.CarryOverLeaveDays(candidate.PreviousRemaining, candidate.MaxCarryOver)
+
+		inserted, err := s.leaveRepo.CreateLeaveBalance(tenantID, candidate.EmployeeID, candidate.LeaveTypeID, year, entitled, carriedOver)
+		if err != nil {
+			return created, err
+		}
+		if inserted {
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// GetLeaveBalances returns the employee balances of a year, accruing missing ones first
+func (s *leaveServiceImpl) GetLeaveBalances(tenantID uuid.UUID, employeeID uuid.UUID, year int) ([]models.LeaveBalance, error) {
+	if _, err := s.AccrueBalances(tenantID, year, &employeeID); err != nil {
+		return nil, err
+	}
+	return s.leaveRepo.GetLeaveBalances(tenantID, employeeID, year)
+}
+
+// SubmitLeaveRequest validates and stores a new pending request. Days are the working
+// days of the employee schedule within the period, excluding tenant holidays.
+func (s *leaveServiceImpl) SubmitLeaveRequest(request *models.LeaveRequest) error {
+	start, err := time.Parse("2006-01-02", request.StartDate)
+	if err != nil {
+		return ErrInvalidLeavePeriod
+	}
+	end, err := time.Parse("2006-01-02", request.EndDate)
+	if err != nil || end.Before(start) {
+		return ErrInvalidLeavePeriod
+	}
+	// Hari cuti dipotong dari saldo satu tahun, cuti akhir tahun diajukan per tahun
+	if end.Year() != start.Year() {
+		return ErrLeaveCrossesYear
+	}
+
+	leaveType, err := s.leaveRepo.GetLeaveTypeByID(request.TenantID, request.LeaveTypeID)
+	if err != nil {
+		return err
+	}
+	if !leaveType.IsActive {
+		return ErrLeaveTypeInactive
+	}
+	if leaveType.RequiresAttachment && request.AttachmentKey == "" {
+		return ErrLeaveAttachmentRequired
+	}
+
+	workingDays := 0
+	if schedule, err := s.scheduleRepo.GetScheduleForUser(request.TenantID, request.UserID); err == nil && schedule != nil {
+		workingDays = schedule.WorkingDays
+	}
+	calendar, err := loadWorkCalendar(s.calendarRepo, request.TenantID, workingDays, start, end)
+	if err != nil {
+		return err
+	}
+	request.Days = calendar.CountWorkingDays(start, end)
+	if request.Days == 0 {
+		return ErrNoLeaveWorkingDays
+	}
+
+	overlap, err := s.leaveRepo.HasOverlappingLeave(request.TenantID, request.EmployeeID, request.StartDate, request.EndDate)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrLeaveOverlap
+	}
+
+	if leaveType.TracksBalance {
+		if err := s.checkBalance(request.TenantID, request.EmployeeID, leaveType.ID, start.Year(), request.Days); err != nil {
+			return err
+		}
+	}
+
+	request.Status = models.LeaveStatusPending
+	return s.leaveRepo.CreateLeaveRequest(request)
+}
+
+// checkBalance makes sure the remaining balance (after pending requests) covers the days
+func (s *leaveServiceImpl) checkBalance(tenantID, employeeID, leaveTypeID uuid.UUID, year int, days int) error {
+	if _, err := s.AccrueBalances(tenantID, year, &employeeID); err != nil {
+		return err
+	}
+
+	balance, err := s.leaveRepo.GetLeaveBalance(tenantID, employeeID, leaveTypeID, year)
+	if err == repository.ErrLeaveBalanceNotFound {
+		return ErrInsufficientLeaveBalance
+	}
+	if err != nil {
+		return err
+	}
+	if balance.Remaining < days {
+		return ErrInsufficientLeaveBalance
+	}
+	return nil
+}
+
+func (s *leaveServiceImpl) GetLeaveRequestByID(tenantID uuid.UUID, id uuid.UUID) (*models.LeaveRequest, error) {
+	return s.leaveRepo.GetLeaveRequestByID(tenantID, id)
+}
+
+func (s *leaveServiceImpl) GetLeaveRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.LeaveRequest, error) {
+	return s.leaveRepo.GetLeaveRequests(tenantID, employeeID)
+}
+
+func (s *leaveServiceImpl) CancelLeaveRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error {
+	request, err := s.leaveRepo.GetLeaveRequestByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if request.EmployeeID != employeeID {
+		return repository.ErrLeaveRequestNotFound
+	}
+	return s.leaveRepo.CancelLeaveRequest(tenantID, id, employeeID)
+}
+
+func (s *leaveServiceImpl) GetPendingLeaveRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.LeaveRequest, error) {
+	if approver.CanApproveAll {
+		return s.leaveRepo.GetPendingLeaveRequests(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.LeaveRequest{}, nil
+	}
+	return s.leaveRepo.GetPendingLeaveRequests(tenantID, approver.EmployeeID)
+}
+
+// DecideLeaveRequest approves or rejects a pending leave request and returns its new
+// status. The same supervisor rules as attendance approval apply.
+func (s *leaveServiceImpl) DecideLeaveRequest(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	request, err := s.leaveRepo.GetLeaveRequestByID(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if request.UserID == approver.UserID {
+		return "", ErrSelfLeaveApproval
+	}
+	if request.Status != models.LeaveStatusPending {
+		return "", repository.ErrLeaveRequestNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.leaveRepo.IsEmployeeSupervisedBy(tenantID, request.EmployeeID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	newStatus := models.LeaveStatusRejected
+	deductBalance := false
+	if approve {
+		newStatus = models.LeaveStatusApproved
+
+		leaveType, err := s.leaveRepo.GetLeaveTypeByID(tenantID, request.LeaveTypeID)
+		if err != nil {
+			return "", err
+		}
+		deductBalance = leaveType.TracksBalance
+		if deductBalance {
+			// Sisa saldo sudah dikurangi request ini sendiri (pending), jadi cukup tidak negatif
+			if err := s.checkBalance(tenantID, request.EmployeeID, request.LeaveTypeID, startYear(request.StartDate), 0); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := s.leaveRepo.DecideLeaveRequest(tenantID, id, newStatus, approver.UserID, reason, deductBalance); err != nil {
+		return "", err
+	}
+	return newStatus, nil
+}
+
+func startYear(date string) int {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Now().Year()
+	}
+	return t.Year()
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+)
+
+// NotificationService defines business logic for in-app user notifications
+type NotificationService interface {
+	GetNotifications(tenantID uuid.UUID, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, int, error)
+	MarkRead(tenantID uuid.UUID, userID uuid.UUID, id uuid.UUID) error
+	MarkAllRead(tenantID uuid.UUID, userID uuid.UUID) (int64, error)
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationServiceImpl{notificationRepo: notificationRepo}
+}
+
+// GetNotifications returns the newest notifications of the user and the unread count
+func (s *notificationServiceImpl) GetNotifications(tenantID uuid.UUID, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, int, error) {
+	notifications, err := s.notificationRepo.GetNotifications(tenantID, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.notificationRepo.CountUnread(tenantID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (s *notificationServiceImpl) MarkRead(tenantID uuid.UUID, userID uuid.UUID, id uuid.UUID) error {
+	return s.notificationRepo.MarkRead(tenantID, userID, id)
+}
+
+func (s *notificationServiceImpl) MarkAllRead(tenantID uuid.UUID, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllRead(tenantID, userID)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrInvalidGeofence = errors.New("office location needs a positive radius or a valid polygon boundary")
+
+// OfficeLocationService defines business logic for tenant office geofences
+type OfficeLocationService interface {
+	CreateLocation(location *models.OfficeLocation) error
+	GetLocationByID(tenantID uuid.UUID, id uuid.UUID) (*models.OfficeLocation, error)
+	GetLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error)
+	UpdateLocation(location *models.OfficeLocation) error
+	DeleteLocation(tenantID uuid.UUID, id uuid.UUID) error
+	GetGeofences(tenantID uuid.UUID) ([]models.OfficeLocation, error)
+
+	CreateSignal(signal *models.OfficePresenceSignal) error
+	GetSignals(tenantID uuid.UUID, officeLocationID uuid.UUID) ([]models.OfficePresenceSignal, error)
+	DeleteSignal(tenantID uuid.UUID, officeLocationID uuid.UUID, id uuid.UUID) error
+	MatchPresence(tenantID uuid.UUID, proof models.PresenceProof) *models.PresenceMatch
+}
+
+type officeLocationServiceImpl struct {
+	locationRepo repository.OfficeLocationRepository
+}
+
+func NewOfficeLocationService(locationRepo repository.OfficeLocationRepository) OfficeLocationService {
+	return &officeLocationServiceImpl{locationRepo: locationRepo}
+}
+
+func (s *officeLocationServiceImpl) CreateLocation(location *models.OfficeLocation) error {
+	if err := prepareGeofence(location); err != nil {
+		return err
+	}
+	return s.locationRepo.CreateLocation(location)
+}
+
+func (s *officeLocationServiceImpl) GetLocationByID(tenantID uuid.UUID, id uuid.UUID) (*models.OfficeLocation, error) {
+	return s.locationRepo.GetLocationByID(tenantID, id)
+}
+
+func (s *officeLocationServiceImpl) GetLocations(tenantID uuid.UUID) ([]models.OfficeLocation, error) {
+	return s.locationRepo.GetLocations(tenantID)
+}
+
+func (s *officeLocationServiceImpl) UpdateLocation(location *models.OfficeLocation) error {
+	if err := prepareGeofence(location); err != nil {
+		return err
+	}
+	return s.locationRepo.UpdateLocation(location)
+}
+
+// prepareGeofence validates the geofence shape and timezone. Polygon offices without an
+// explicit pin coordinate get the polygon centroid so distance reporting still works.
+func prepareGeofence(location *models.OfficeLocation) error {
+	location.Timezone = strings.TrimSpace(location.Timezone)
+	if location.Timezone != "" {
+		if _, err := utils.LoadTimezone(location.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if location.Boundary == nil {
+		if location.Radius <= 0 {
+			return ErrInvalidGeofence
+		}
+		return nil
+	}
+
+	if err := utils.NormalizePolygon(location.Boundary); err != nil {
+		return ErrInvalidGeofence
+	}
+	if location.Latitude == 0 && location.Longitude == 0 {
+		location.Latitude, location.Longitude = utils.PolygonCentroid(location.Boundary)
+	}
+	return nil
+}
+
+func (s *officeLocationServiceImpl) DeleteLocation(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.locationRepo.DeleteLocation(tenantID, id)
+}
+
+// GetGeofences returns the tenant's active offices for attendance validation.
+// Tenants that have not registered any office fall back to the env-configured office
+// so existing deployments keep working without data migration. A lookup failure is
+// returned instead, validating against the wrong geofence is worse than failing the request.
+func (s *officeLocationServiceImpl) GetGeofences(tenantID uuid.UUID) ([]models.OfficeLocation, error) {
+	locations, err := s.locationRepo.GetActiveLocations(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return []models.OfficeLocation{utils.DefaultOfficeLocation()}, nil
+	}
+	return locations, nil
+}
+
+// CreateSignal registers a Wi-Fi BSSID or BLE beacon for an existing office.
+// Identifiers are normalized so the device can report them in any common format.
+func (s *officeLocationServiceImpl) CreateSignal(signal *models.OfficePresenceSignal) error {
+	identifier, err := utils.NormalizeSignalIdentifier(signal.SignalType, signal.Identifier)
+	if err != nil {
+		return err
+	}
+	signal.Identifier = identifier
+
+	if _, err := s.locationRepo.GetLocationByID(signal.TenantID, signal.OfficeLocationID); err != nil {
+		return err
+	}
+	return s.locationRepo.CreateSignal(signal)
+}
+
+func (s *officeLocationServiceImpl) GetSignals(tenantID uuid.UUID, officeLocationID uuid.UUID) ([]models.OfficePresenceSignal, error) {
+	if _, err := s.locationRepo.GetLocationByID(tenantID, officeLocationID); err != nil {
+		return nil, err
+	}
+	return s.locationRepo.GetSignals(tenantID, officeLocationID)
+}
+
+func (s *officeLocationServiceImpl) DeleteSignal(tenantID uuid.UUID, officeLocationID uuid.UUID, id uuid.UUID) error {
+	return s.locationRepo.DeleteSignal(tenantID, officeLocationID, id)
+}
+
+// MatchPresence returns the office proven by the Wi-Fi/BLE signals seen by the device,
+// or nil when no registered signal matches. Invalid identifiers are ignored and a
+// lookup failure falls back to GPS validation instead of blocking attendance.
+func (s *officeLocationServiceImpl) MatchPresence(tenantID uuid.UUID, proof models.PresenceProof) *models.PresenceMatch {
+	var bssids, beaconIDs []string
+	for _, value := range proof.WifiBSSIDs {
+		if bssid, err := utils.NormalizeBSSID(value); err == nil {
+			bssids = append(bssids, bssid)
+		}
+	}
+	for _, value := range proof.BeaconIDs {
+		if beaconID, err := utils.NormalizeBeaconID(value); err == nil {
+			beaconIDs = append(beaconIDs, beaconID)
+		}
+	}
+	if len(bssids) == 0 && len(beaconIDs) == 0 {
+		return nil
+	}
+
+	match, err := s.locationRepo.FindPresenceMatch(tenantID, bssids, beaconIDs)
+	if err != nil {
+		return nil
+	}
+	return match
+}
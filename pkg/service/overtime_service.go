@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// overtimeSettingKey is the tenants.settings key of the overtime multipliers
+const overtimeSettingKey = "overtime"
+
+// DefaultOvertimePolicy applies to tenants that did not configure overtime multipliers
+var DefaultOvertimePolicy = models.OvertimePolicy{
+	WeekdayMultiplier: 1.5,
+	WeekendMultiplier: 2,
+	HolidayMultiplier: 3,
+}
+
+var (
+	ErrOvertimeOverlap         = errors.New("another pending or approved overtime request already covers part of this time")
+	ErrSelfOvertimeApproval    = errors.New("you cannot approve or reject your own overtime request")
+	ErrOvertimeReportForbidden = errors.New("you can only view the overtime of yourself or your direct reports")
+)
+
+// OvertimeService defines business logic for overtime requests and compensation
+type OvertimeService interface {
+	GetPolicy(tenantID uuid.UUID) (models.OvertimePolicy, error)
+	UpdatePolicy(tenantID uuid.UUID, policy models.OvertimePolicy) error
+	SubmitOvertime(request *models.OvertimeRequest, now time.Time) error
+	GetOvertimeRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.OvertimeRequest, error)
+	CancelOvertimeRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error
+	GetPendingOvertimeRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.OvertimeRequest, error)
+	DecideOvertime(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error)
+	ReconcileSession(tenantID uuid.UUID, userID uuid.UUID, attendanceID uuid.UUID, checkIn, checkOut time.Time) error
+	GetOvertimeReport(tenantID uuid.UUID, approver models.AttendanceApprover, employeeID *uuid.UUID, start, end time.Time) (*models.OvertimeReport, error)
+}
+
+type overtimeServiceImpl struct {
+	overtimeRepo     repository.OvertimeRepository
+	calendarRepo     repository.CalendarRepository
+	scheduleRepo     repository.ScheduleRepository
+	settingsRepo     repository.TenantSettingsRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewOvertimeService(overtimeRepo repository.OvertimeRepository, calendarRepo repository.CalendarRepository, scheduleRepo repository.ScheduleRepository, settingsRepo repository.TenantSettingsRepository, notificationRepo repository.NotificationRepository) OvertimeService {
+	return &overtimeServiceImpl{overtimeRepo: overtimeRepo, calendarRepo: calendarRepo, scheduleRepo: scheduleRepo, settingsRepo: settingsRepo, notificationRepo: notificationRepo}
+}
+
+func (s *overtimeServiceImpl) GetPolicy(tenantID uuid.UUID) (models.OvertimePolicy, error) {
+	return loadOvertimePolicy(s.settingsRepo, tenantID)
+}
+
+func (s *overtimeServiceImpl) UpdatePolicy(tenantID uuid.UUID, policy models.OvertimePolicy) error {
+	return s.settingsRepo.SaveSetting(tenantID, overtimeSettingKey, policy)
+}
+
+func (s *overtimeServiceImpl) tenantLocation(tenantID uuid.UUID) *time.Location {
+	timezone, _ := s.settingsRepo.GetTimezone(tenantID)
+	return utils.ResolveTimezone(timezone)
+}
+
+// SubmitOvertime validates and stores a pending overtime request made ahead of time
+func (s *overtimeServiceImpl) SubmitOvertime(request *models.OvertimeRequest, now time.Time) error {
+	if err := utils.ValidateOvertimeWindow(request.StartTime, request.EndTime, now); err != nil {
+		return err
+	}
+
+	overlap, err := s.overtimeRepo.HasOverlappingRequest(request.TenantID, request.EmployeeID, request.StartTime, request.EndTime)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrOvertimeOverlap
+	}
+
+	// Jam lembur disimpan dalam jam server seperti clock in/out, tanggalnya mengikuti timezone tenant
+	request.OvertimeDate = request.StartTime.In(s.tenantLocation(request.TenantID)).Format("2006-01-02")
+	request.StartTime = request.StartTime.In(time.Local)
+	request.EndTime = request.EndTime.In(time.Local)
+	request.PlannedMinutes = request.EndTime.Sub(request.StartTime).Minutes()
+	request.Reason = strings.TrimSpace(request.Reason)
+	request.Status = models.OvertimeStatusPending
+	return s.overtimeRepo.CreateOvertimeRequest(request)
+}
+
+func (s *overtimeServiceImpl) GetOvertimeRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.OvertimeRequest, error) {
+	return s.overtimeRepo.GetOvertimeRequests(tenantID, employeeID)
+}
+
+func (s *overtimeServiceImpl) CancelOvertimeRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error {
+	request, err := s.overtimeRepo.GetOvertimeRequestByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if request.EmployeeID != employeeID {
+		return repository.ErrOvertimeNotFound
+	}
+	return s.overtimeRepo.CancelOvertimeRequest(tenantID, id, employeeID)
+}
+
+func (s *overtimeServiceImpl) GetPendingOvertimeRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.OvertimeRequest, error) {
+	if approver.CanApproveAll {
+		return s.overtimeRepo.GetPendingOvertimeRequests(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.OvertimeRequest{}, nil
+	}
+	return s.overtimeRepo.GetPendingOvertimeRequests(tenantID, approver.EmployeeID)
+}
+
+// DecideOvertime approves or rejects a pending request and returns its new status. Approval
+// fixes the day type from the tenant calendar; overtime already clocked is reconciled right away.
+func (s *overtimeServiceImpl) DecideOvertime(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	request, err := s.overtimeRepo.GetOvertimeRequestByID(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if request.UserID == approver.UserID {
+		return "", ErrSelfOvertimeApproval
+	}
+	if request.Status != models.OvertimeStatusPending {
+		return "", repository.ErrOvertimeNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.overtimeRepo.IsEmployeeSupervisedBy(tenantID, request.EmployeeID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	if !approve {
+		if err := s.overtimeRepo.RejectOvertime(tenantID, id, approver.UserID, reason); err != nil {
+			return "", err
+		}
+		s.notifyDecision(request, models.OvertimeStatusRejected, reason)
+		return models.OvertimeStatusRejected, nil
+	}
+
+	request.DayType = s.dayType(tenantID, request.UserID, request.StartTime)
+	if err := s.overtimeRepo.ApproveOvertime(tenantID, id, approver.UserID, reason, request.DayType); err != nil {
+		return "", err
+	}
+	request.Status = models.OvertimeStatusApproved
+	s.notifyDecision(request, models.OvertimeStatusApproved, reason)
+
+	// Lembur yang sudah terlanjur di-clock (persetujuan terlambat) langsung direkonsiliasi,
+	// sisanya direkonsiliasi saat clock out
+	if policy, err := loadOvertimePolicy(s.settingsRepo, tenantID); err == nil {
+		reconcileOvertimeRequest(s.overtimeRepo, request, policy, time.Now())
+	}
+	return models.OvertimeStatusApproved, nil
+}
+
+// dayType menentukan jenis hari dari tanggal mulai lembur di timezone tenant. Hari kerja
+// mengikuti jadwal karyawan (hari kerja tenant jika tidak punya jadwal), seperti saat
+// menghitung hari cuti. Jika kalender gagal dibaca, lembur dihitung sebagai hari kerja biasa.
+func (s *overtimeServiceImpl) dayType(tenantID uuid.UUID, userID uuid.UUID, start time.Time) string {
+	date := start.In(s.tenantLocation(tenantID))
+	workingDays := 0
+	if schedule, err := s.scheduleRepo.GetScheduleForUser(tenantID, userID); err == nil && schedule != nil {
+		workingDays = schedule.WorkingDays
+	}
+	calendar, err := loadWorkCalendar(s.calendarRepo, tenantID, workingDays, date, date)
+	if err != nil {
+		return models.OvertimeDayWeekday
+	}
+	return utils.OvertimeDayType(calendar, date)
+}
+
+// ReconcileSession reconciles the approved overtime overlapping a clocked out attendance
+// session: worked minutes inside each approved window become compensable hours and the
+// approved minutes are recorded as overtime of the attendance
+func (s *overtimeServiceImpl) ReconcileSession(tenantID uuid.UUID, userID uuid.UUID, attendanceID uuid.UUID, checkIn, checkOut time.Time) error {
+	return reconcileOvertimeSession(s.overtimeRepo, s.settingsRepo, tenantID, userID, attendanceID, checkIn, checkOut)
+}
+
+// reconcileOvertimeSession dipakai setiap kali sesi attendance ditutup atau jamnya berubah:
+// clock out, auto clock-out dan koreksi attendance yang disetujui
+func reconcileOvertimeSession(overtimeRepo repository.OvertimeRepository, settingsRepo repository.TenantSettingsRepository,
+	tenantID uuid.UUID, userID uuid.UUID, attendanceID uuid.UUID, checkIn, checkOut time.Time) error {
+
+	requests, err := overtimeRepo.GetApprovedOvertimeInWindow(tenantID, userID, checkIn, checkOut)
+	if err != nil || len(requests) == 0 {
+		return err
+	}
+
+	policy, err := loadOvertimePolicy(settingsRepo, tenantID)
+	if err != nil {
+		return err
+	}
+
+	session := models.AttendanceSession{ID: attendanceID, TenantID: tenantID, UserID: userID, CheckInTime: checkIn, CheckOutTime: &checkOut}
+	now := time.Now()
+	var sessionMinutes float64
+	for i := range requests {
+		if err := reconcileOvertimeRequest(overtimeRepo, &requests[i], policy, now); err != nil {
+			return err
+		}
+		sessionMinutes += utils.OvertimeWorkedMinutes(requests[i].StartTime, requests[i].EndTime, []models.AttendanceSession{session})
+	}
+	return overtimeRepo.UpdateAttendanceOvertime(tenantID, attendanceID, sessionMinutes)
+}
+
+// reconcileOvertimeRequest menghitung ulang menit lembur yang benar-benar dikerjakan dari semua sesi
+// attendance yang sudah clock out di dalam jendela lembur
+func reconcileOvertimeRequest(overtimeRepo repository.OvertimeRepository, request *models.OvertimeRequest, policy models.OvertimePolicy, now time.Time) error {
+	sessions, err := overtimeRepo.GetClosedSessions(request.TenantID, request.UserID, request.StartTime, request.EndTime)
+	if err != nil || len(sessions) == 0 {
+		return err
+	}
+
+	request.AttendanceID = &sessions[len(sessions)-1].ID
+	request.ActualMinutes = utils.OvertimeWorkedMinutes(request.StartTime, request.EndTime, sessions)
+	request.Multiplier = utils.OvertimeMultiplier(policy, request.DayType)
+	request.CompensableHours = utils.CompensableHours(request.ActualMinutes, request.Multiplier)
+	request.ReconciledAt = &now
+	return overtimeRepo.SaveReconciliation(request)
+}
+
+// GetOvertimeReport sums the reconciled overtime between start and end (inclusive). Admin/HR
+// see the whole tenant, everyone else themselves and their direct reports.
+func (s *overtimeServiceImpl) GetOvertimeReport(tenantID uuid.UUID, approver models.AttendanceApprover, employeeID *uuid.UUID, start, end time.Time) (*models.OvertimeReport, error) {
+	if end.Before(start) || end.Sub(start) > 366*24*time.Hour {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	var viewerID *uuid.UUID
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return nil, ErrOvertimeReportForbidden
+		}
+		viewerID = approver.EmployeeID
+	}
+
+	policy, err := loadOvertimePolicy(s.settingsRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.OvertimeReport{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Policy:    policy,
+	}
+	report.Employees, err = s.overtimeRepo.GetOvertimeReport(tenantID, viewerID, employeeID, report.StartDate, report.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range report.Employees {
+		report.TotalCompensableHours += row.CompensableHours
+	}
+	return report, nil
+}
+
+// notifyDecision memberi tahu karyawan hasil pengajuan lemburnya. Keputusan sudah tersimpan,
+// sehingga kegagalan notifikasi tidak membatalkan keputusan.
+func (s *overtimeServiceImpl) notifyDecision(request *models.OvertimeRequest, status string, reason string) {
+	title := "Lembur disetujui"
+	message := fmt.Sprintf("Pengajuan lembur tanggal %s telah disetujui.", request.OvertimeDate)
+	if status == models.OvertimeStatusRejected {
+		title = "Lembur ditolak"
+		message = fmt.Sprintf("Pengajuan lembur tanggal %s ditolak: %s", request.OvertimeDate, reason)
+	}
+	s.notificationRepo.CreateNotification(&models.Notification{
+		TenantID:    request.TenantID,
+		UserID:      request.UserID,
+		Type:        models.NotificationTypeOvertime,
+		Title:       title,
+		Message:     message,
+		ReferenceID: &request.ID,
+	})
+}
+
+func loadOvertimePolicy(settingsRepo repository.TenantSettingsRepository, tenantID uuid.UUID) (models.OvertimePolicy, error) {
+	policy := DefaultOvertimePolicy
+	if _, err := settingsRepo.GetSetting(tenantID, overtimeSettingKey, &policy); err != nil {
+		return DefaultOvertimePolicy, err
+	}
+	return policy, nil
+}
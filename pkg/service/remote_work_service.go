@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// remoteWorkSettingKey is the tenants.settings key of the remote work policy
+const remoteWorkSettingKey = "remote_work"
+
+// defaultHomeRadius is the home geofence radius in meters when none is given
+const defaultHomeRadius = 150
+
+var (
+	ErrInvalidWFHPeriod   = errors.New("start_date and end_date must use YYYY-MM-DD, end_date must not be before start_date and the period must not exceed 31 days")
+	ErrWFHInPast          = errors.New("WFH can only be requested for today or later")
+	ErrWFHOverlap         = errors.New("another pending or approved WFH request already covers part of this period")
+	ErrSelfWFHApproval    = errors.New("you cannot approve or reject your own WFH request")
+	ErrHomeLocationNeeded = errors.New("register your home location before clocking in on a WFH day")
+)
+
+// RemoteWorkService defines business logic for WFH requests and home locations
+type RemoteWorkService interface {
+	GetPolicy(tenantID uuid.UUID) (models.RemoteWorkPolicy, error)
+	UpdatePolicy(tenantID uuid.UUID, policy models.RemoteWorkPolicy) error
+
+	GetHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) (*models.HomeLocation, error)
+	GetHomeLocations(tenantID uuid.UUID) ([]models.HomeLocation, error)
+	SaveHomeLocation(home *models.HomeLocation) error
+	DeleteHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) error
+
+	SubmitWFHRequest(request *models.WFHRequest) error
+	GetWFHRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.WFHRequest, error)
+	CancelWFHRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error
+	GetPendingWFHRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.WFHRequest, error)
+	DecideWFHRequest(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error)
+
+	GetRemoteWorkDay(tenantID uuid.UUID, userID uuid.UUID, date string) (*models.RemoteWorkDay, error)
+}
+
+type remoteWorkServiceImpl struct {
+	remoteWorkRepo repository.RemoteWorkRepository
+	settingsRepo   repository.TenantSettingsRepository
+}
+
+func NewRemoteWorkService(remoteWorkRepo repository.RemoteWorkRepository, settingsRepo repository.TenantSettingsRepository) RemoteWorkService {
+	return &remoteWorkServiceImpl{remoteWorkRepo: remoteWorkRepo, settingsRepo: settingsRepo}
+}
+
+func (s *remoteWorkServiceImpl) GetPolicy(tenantID uuid.UUID) (models.RemoteWorkPolicy, error) {
+	var policy models.RemoteWorkPolicy
+	if _, err := s.settingsRepo.GetSetting(tenantID, remoteWorkSettingKey, &policy); err != nil {
+		return models.RemoteWorkPolicy{}, err
+	}
+	return policy, nil
+}
+
+func (s *remoteWorkServiceImpl) UpdatePolicy(tenantID uuid.UUID, policy models.RemoteWorkPolicy) error {
+	return s.settingsRepo.SaveSetting(tenantID, remoteWorkSettingKey, policy)
+}
+
+func (s *remoteWorkServiceImpl) GetHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) (*models.HomeLocation, error) {
+	return s.remoteWorkRepo.GetHomeLocation(tenantID, employeeID)
+}
+
+func (s *remoteWorkServiceImpl) GetHomeLocations(tenantID uuid.UUID) ([]models.HomeLocation, error) {
+	return s.remoteWorkRepo.GetHomeLocations(tenantID)
+}
+
+func (s *remoteWorkServiceImpl) SaveHomeLocation(home *models.HomeLocation) error {
+	home.Name = strings.TrimSpace(home.Name)
+	if home.Name == "" {
+		home.Name = "Rumah"
+	}
+	if home.Radius == 0 {
+		home.Radius = defaultHomeRadius
+	}
+	return s.remoteWorkRepo.SaveHomeLocation(home)
+}
+
+func (s *remoteWorkServiceImpl) DeleteHomeLocation(tenantID uuid.UUID, employeeID uuid.UUID) error {
+	return s.remoteWorkRepo.DeleteHomeLocation(tenantID, employeeID)
+}
+
+// SubmitWFHRequest validates and stores a new pending request for today or later
+func (s *remoteWorkServiceImpl) SubmitWFHRequest(request *models.WFHRequest) error {
+	start, err := time.Parse("2006-01-02", request.StartDate)
+	if err != nil {
+		return ErrInvalidWFHPeriod
+	}
+	end, err := time.Parse("2006-01-02", request.EndDate)
+	if err != nil || end.Before(start) || end.Sub(start) > 30*24*time.Hour {
+		return ErrInvalidWFHPeriod
+	}
+	timezone, _ := s.settingsRepo.GetTimezone(request.TenantID)
+	if request.StartDate < time.Now().In(utils.ResolveTimezone(timezone)).Format("2006-01-02") {
+		return ErrWFHInPast
+	}
+
+	overlap, err := s.remoteWorkRepo.HasOverlappingWFH(request.TenantID, request.EmployeeID, request.StartDate, request.EndDate)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrWFHOverlap
+	}
+
+	request.Reason = strings.TrimSpace(request.Reason)
+	request.Status = models.WFHStatusPending
+	return s.remoteWorkRepo.CreateWFHRequest(request)
+}
+
+func (s *remoteWorkServiceImpl) GetWFHRequests(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.WFHRequest, error) {
+	return s.remoteWorkRepo.GetWFHRequests(tenantID, employeeID)
+}
+
+func (s *remoteWorkServiceImpl) CancelWFHRequest(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error {
+	request, err := s.remoteWorkRepo.GetWFHRequestByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if request.EmployeeID != employeeID {
+		return repository.ErrWFHRequestNotFound
+	}
+	return s.remoteWorkRepo.CancelWFHRequest(tenantID, id, employeeID)
+}
+
+func (s *remoteWorkServiceImpl) GetPendingWFHRequests(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.WFHRequest, error) {
+	if approver.CanApproveAll {
+		return s.remoteWorkRepo.GetPendingWFHRequests(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.WFHRequest{}, nil
+	}
+	return s.remoteWorkRepo.GetPendingWFHRequests(tenantID, approver.EmployeeID)
+}
+
+// DecideWFHRequest approves or rejects a pending WFH request and returns its new status.
+// The same supervisor rules as attendance approval apply.
+func (s *remoteWorkServiceImpl) DecideWFHRequest(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	request, err := s.remoteWorkRepo.GetWFHRequestByID(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if request.UserID == approver.UserID {
+		return "", ErrSelfWFHApproval
+	}
+	if request.Status != models.WFHStatusPending {
+		return "", repository.ErrWFHRequestNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.remoteWorkRepo.IsEmployeeSupervisedBy(tenantID, request.EmployeeID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	newStatus := models.WFHStatusRejected
+	if approve {
+		newStatus = models.WFHStatusApproved
+	}
+	if err := s.remoteWorkRepo.DecideWFHRequest(tenantID, id, newStatus, approver.UserID, reason); err != nil {
+		return "", err
+	}
+	return newStatus, nil
+}
+
+// GetRemoteWorkDay returns the approved WFH day of the user on the date with the home
+// geofence to validate against, or nil when the user works from the office that day
+func (s *remoteWorkServiceImpl) GetRemoteWorkDay(tenantID uuid.UUID, userID uuid.UUID, date string) (*models.RemoteWorkDay, error) {
+	request, err := s.remoteWorkRepo.GetApprovedWFHRequest(tenantID, userID, date)
+	if err != nil || request == nil {
+		return nil, err
+	}
+
+	day := &models.RemoteWorkDay{Request: *request}
+	home, err := s.remoteWorkRepo.GetHomeLocation(tenantID, request.EmployeeID)
+	if err != nil && err != repository.ErrHomeLocationNotFound {
+		return nil, err
+	}
+	day.Home = home
+
+	policy, err := s.GetPolicy(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	day.RequireHomeLocation = policy.RequireHomeLocation
+	return day, nil
+}
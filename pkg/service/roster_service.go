@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var (
+	ErrInvalidRosterDate      = errors.New("roster dates must use YYYY-MM-DD format")
+	ErrInvalidRosterPeriod    = errors.New("end_date must not be before start_date and the period must not exceed 62 days")
+	ErrShiftSwapWithSelf      = errors.New("you cannot swap a shift with yourself")
+	ErrShiftSwapInPast        = errors.New("only upcoming shifts can be swapped")
+	ErrNoShiftToSwap          = errors.New("you have no rostered shift on requester_date")
+	ErrShiftSwapTargetMissing = errors.New("target employee not found in this tenant")
+	ErrSelfShiftSwapApproval  = errors.New("you cannot approve or reject a shift swap you are part of")
+)
+
+// RosterService defines business logic for shift rosters and shift swaps
+type RosterService interface {
+	SaveRoster(tenantID uuid.UUID, createdBy uuid.UUID, entries []models.RosterEntryRequest) (int, error)
+	GetRoster(tenantID uuid.UUID, start, end time.Time, employeeID *uuid.UUID) ([]models.ShiftRoster, error)
+	DeleteRosterEntry(tenantID uuid.UUID, id uuid.UUID) error
+	FindShiftInstance(tenantID uuid.UUID, userID uuid.UUID, at time.Time) *models.RosteredShift
+	GetRosteredShift(tenantID uuid.UUID, rosterID uuid.UUID) (*models.RosteredShift, error)
+
+	RequestShiftSwap(swap *models.ShiftSwapRequest) error
+	GetShiftSwaps(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.ShiftSwapRequest, error)
+	CancelShiftSwap(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error
+	GetPendingShiftSwaps(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.ShiftSwapRequest, error)
+	DecideShiftSwap(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error)
+}
+
+type rosterServiceImpl struct {
+	rosterRepo   repository.RosterRepository
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewRosterService(rosterRepo repository.RosterRepository, settingsRepo repository.TenantSettingsRepository) RosterService {
+	return &rosterServiceImpl{rosterRepo: rosterRepo, settingsRepo: settingsRepo}
+}
+
+func (s *rosterServiceImpl) SaveRoster(tenantID uuid.UUID, createdBy uuid.UUID, entries []models.RosterEntryRequest) (int, error) {
+	for i := range entries {
+		date, err := time.Parse("2006-01-02", entries[i].Date)
+		if err != nil {
+			return 0, ErrInvalidRosterDate
+		}
+		entries[i].Date = date.Format("2006-01-02")
+	}
+	return s.rosterRepo.SaveRosterEntries(tenantID, createdBy, entries)
+}
+
+func (s *rosterServiceImpl) GetRoster(tenantID uuid.UUID, start, end time.Time, employeeID *uuid.UUID) ([]models.ShiftRoster, error) {
+	if end.Before(start) || end.Sub(start) > 62*24*time.Hour {
+		return nil, ErrInvalidRosterPeriod
+	}
+	return s.rosterRepo.GetRoster(tenantID, start.Format("2006-01-02"), end.Format("2006-01-02"), employeeID)
+}
+
+func (s *rosterServiceImpl) DeleteRosterEntry(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.rosterRepo.DeleteRosterEntry(tenantID, id)
+}
+
+// FindShiftInstance returns the rostered shift a clock in at the given time belongs to, or
+// nil when the user is not rostered around that time. Shifts starting yesterday (night
+// shifts still running) and tomorrow (early clock in before midnight) are considered.
+// Lookup errors are treated as "not rostered" so attendance never fails because of rosters.
+func (s *rosterServiceImpl) FindShiftInstance(tenantID uuid.UUID, userID uuid.UUID, at time.Time) *models.RosteredShift {
+	shifts, err := s.rosterRepo.GetRosteredShifts(tenantID, userID,
+		at.AddDate(0, 0, -1).Format("2006-01-02"), at.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		return nil
+	}
+	return utils.FindShiftInstance(shifts, at)
+}
+
+func (s *rosterServiceImpl) GetRosteredShift(tenantID uuid.UUID, rosterID uuid.UUID) (*models.RosteredShift, error) {
+	return s.rosterRepo.GetRosteredShift(tenantID, rosterID)
+}
+
+// RequestShiftSwap validates and stores a pending swap. The requester must be rostered on a
+// working shift on requester_date; the target may be off, in which case they take it over.
+func (s *rosterServiceImpl) RequestShiftSwap(swap *models.ShiftSwapRequest) error {
+	if swap.TargetDate == "" {
+		swap.TargetDate = swap.RequesterDate
+	}
+	requesterDate, err := time.Parse("2006-01-02", swap.RequesterDate)
+	if err != nil {
+		return ErrInvalidRosterDate
+	}
+	targetDate, err := time.Parse("2006-01-02", swap.TargetDate)
+	if err != nil {
+		return ErrInvalidRosterDate
+	}
+	swap.RequesterDate, swap.TargetDate = requesterDate.Format("2006-01-02"), targetDate.Format("2006-01-02")
+
+	if swap.RequesterID == swap.TargetEmployeeID {
+		return ErrShiftSwapWithSelf
+	}
+	today := s.tenantToday(swap.TenantID)
+	if swapDateInPast(requesterDate, today) || swapDateInPast(targetDate, today) {
+		return ErrShiftSwapInPast
+	}
+
+	exists, err := s.rosterRepo.EmployeeExists(swap.TenantID, swap.TargetEmployeeID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrShiftSwapTargetMissing
+	}
+
+	entry, err := s.rosterRepo.GetRosterEntry(swap.TenantID, swap.RequesterID, swap.RequesterDate)
+	if err == repository.ErrRosterEntryNotFound || (err == nil && entry.IsDayOff) {
+		return ErrNoShiftToSwap
+	}
+	if err != nil {
+		return err
+	}
+
+	swap.Status = models.ShiftSwapStatusPending
+	return s.rosterRepo.CreateShiftSwap(swap)
+}
+
+func (s *rosterServiceImpl) GetShiftSwaps(tenantID uuid.UUID, employeeID uuid.UUID) ([]models.ShiftSwapRequest, error) {
+	return s.rosterRepo.GetShiftSwaps(tenantID, employeeID)
+}
+
+func (s *rosterServiceImpl) CancelShiftSwap(tenantID uuid.UUID, employeeID uuid.UUID, id uuid.UUID) error {
+	swap, err := s.rosterRepo.GetShiftSwapByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if swap.RequesterID != employeeID {
+		return repository.ErrShiftSwapNotFound
+	}
+	return s.rosterRepo.CancelShiftSwap(tenantID, id, employeeID)
+}
+
+func (s *rosterServiceImpl) GetPendingShiftSwaps(tenantID uuid.UUID, approver models.AttendanceApprover) ([]models.ShiftSwapRequest, error) {
+	if approver.CanApproveAll {
+		return s.rosterRepo.GetPendingShiftSwaps(tenantID, nil)
+	}
+	if approver.EmployeeID == nil {
+		return []models.ShiftSwapRequest{}, nil
+	}
+	return s.rosterRepo.GetPendingShiftSwaps(tenantID, approver.EmployeeID)
+}
+
+// DecideShiftSwap approves or rejects a pending swap and returns its new status. Only the
+// requester's supervisor (or admin/HR) decides, and approved swaps are applied to the roster.
+func (s *rosterServiceImpl) DecideShiftSwap(tenantID uuid.UUID, approver models.AttendanceApprover, id uuid.UUID, approve bool, reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return "", ErrRejectionReasonRequired
+	}
+
+	swap, err := s.rosterRepo.GetShiftSwapByID(tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if approver.EmployeeID != nil && (*approver.EmployeeID == swap.RequesterID || *approver.EmployeeID == swap.TargetEmployeeID) {
+		return "", ErrSelfShiftSwapApproval
+	}
+	if swap.Status != models.ShiftSwapStatusPending {
+		return "", repository.ErrShiftSwapNotPending
+	}
+
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return "", ErrNotSupervisor
+		}
+		supervised, err := s.rosterRepo.IsEmployeeSupervisedBy(tenantID, swap.RequesterID, *approver.EmployeeID)
+		if err != nil {
+			return "", err
+		}
+		if !supervised {
+			return "", ErrNotSupervisor
+		}
+	}
+
+	newStatus := models.ShiftSwapStatusRejected
+	if approve {
+		requesterDate, _ := time.Parse("2006-01-02", swap.RequesterDate)
+		targetDate, _ := time.Parse("2006-01-02", swap.TargetDate)
+		today := s.tenantToday(tenantID)
+		if swapDateInPast(requesterDate, today) || swapDateInPast(targetDate, today) {
+			return "", ErrShiftSwapInPast
+		}
+		newStatus = models.ShiftSwapStatusApproved
+	}
+
+	if err := s.rosterRepo.DecideShiftSwap(tenantID, id, newStatus, approver.UserID, reason); err != nil {
+		return "", err
+	}
+	return newStatus, nil
+}
+
+// tenantToday adalah tanggal hari ini (YYYY-MM-DD) dalam timezone tenant, bukan timezone server
+func (s *rosterServiceImpl) tenantToday(tenantID uuid.UUID) string {
+	timezone, _ := s.settingsRepo.GetTimezone(tenantID)
+	return time.Now().In(utils.ResolveTimezone(timezone)).Format("2006-01-02")
+}
+
+func swapDateInPast(date time.Time, today string) bool {
+	return date.Format("2006-01-02") < today
+}
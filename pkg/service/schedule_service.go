@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+var ErrInvalidSchedule = errors.New("schedule start_time and end_time must use HH:MM format and differ")
+
+// ScheduleService defines business logic for work schedules
+type ScheduleService interface {
+	CreateSchedule(schedule *models.AttendanceSchedule) error
+	GetScheduleByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceSchedule, error)
+	GetSchedules(tenantID uuid.UUID) ([]models.AttendanceSchedule, error)
+	UpdateSchedule(schedule *models.AttendanceSchedule) error
+	DeleteSchedule(tenantID uuid.UUID, id uuid.UUID) error
+	AssignSchedule(tenantID uuid.UUID, scheduleID uuid.UUID, employeeIDs []uuid.UUID) (int64, error)
+	GetScheduleForUser(tenantID uuid.UUID, userID uuid.UUID) *models.AttendanceSchedule
+	SessionTimeStats(tenantID uuid.UUID, rosterID, scheduleID uuid.NullUUID, attendanceDate string, loc *time.Location, checkIn time.Time, checkOut *time.Time) models.AttendanceTimeStats
+}
+
+type scheduleServiceImpl struct {
+	scheduleRepo repository.ScheduleRepository
+	rosterRepo   repository.RosterRepository
+	calendarRepo repository.CalendarRepository
+}
+
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, rosterRepo repository.RosterRepository, calendarRepo repository.CalendarRepository) ScheduleService {
+	return &scheduleServiceImpl{scheduleRepo: scheduleRepo, rosterRepo: rosterRepo, calendarRepo: calendarRepo}
+}
+
+func (s *scheduleServiceImpl) CreateSchedule(schedule *models.AttendanceSchedule) error {
+	if err := validateSchedule(schedule); err != nil {
+		return err
+	}
+	return s.scheduleRepo.CreateSchedule(schedule)
+}
+
+func (s *scheduleServiceImpl) GetScheduleByID(tenantID uuid.UUID, id uuid.UUID) (*models.AttendanceSchedule, error) {
+	return s.scheduleRepo.GetScheduleByID(tenantID, id)
+}
+
+func (s *scheduleServiceImpl) GetSchedules(tenantID uuid.UUID) ([]models.AttendanceSchedule, error) {
+	return s.scheduleRepo.GetSchedules(tenantID)
+}
+
+func (s *scheduleServiceImpl) UpdateSchedule(schedule *models.AttendanceSchedule) error {
+	if err := validateSchedule(schedule); err != nil {
+		return err
+	}
+	return s.scheduleRepo.UpdateSchedule(schedule)
+}
+
+func (s *scheduleServiceImpl) DeleteSchedule(tenantID uuid.UUID, id uuid.UUID) error {
+	return s.scheduleRepo.DeleteSchedule(tenantID, id)
+}
+
+func (s *scheduleServiceImpl) AssignSchedule(tenantID uuid.UUID, scheduleID uuid.UUID, employeeIDs []uuid.UUID) (int64, error) {
+	if _, err := s.scheduleRepo.GetScheduleByID(tenantID, scheduleID); err != nil {
+		return 0, err
+	}
+	return s.scheduleRepo.AssignSchedule(tenantID, scheduleID, employeeIDs)
+}
+
+// GetScheduleForUser returns the schedule used for lateness/overtime, or nil when the
+// user has no assigned schedule and the tenant has no default. Lookup errors are treated
+// as "no schedule" so attendance itself never fails because of schedules.
+func (s *scheduleServiceImpl) GetScheduleForUser(tenantID uuid.UUID, userID uuid.UUID) *models.AttendanceSchedule {
+	schedule, err := s.scheduleRepo.GetScheduleForUser(tenantID, userID)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+func validateSchedule(schedule *models.AttendanceSchedule) error {
+	start, err := utils.ParseClock(schedule.StartTime)
+	if err != nil {
+		return ErrInvalidSchedule
+	}
+	end, err := utils.ParseClock(schedule.EndTime)
+	if err != nil || start == end {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+func (s *scheduleServiceImpl) SessionTimeStats(tenantID uuid.UUID, rosterID, scheduleID uuid.NullUUID, attendanceDate string, loc *time.Location, checkIn time.Time, checkOut *time.Time) models.AttendanceTimeStats {
+	return sessionTimeStats(s.scheduleRepo, s.rosterRepo, s.calendarRepo, tenantID, rosterID, scheduleID, attendanceDate, loc, checkIn, checkOut)
+}
+
+// sessionTimeStats computes late, early leave and overtime minutes of a session against the
+// schedule used at clock in, in the attendance timezone. Rostered shifts are measured from the
+// shift start date (also night shifts); on a tenant holiday every worked minute is overtime.
+// A session without check out only gets its late minutes. Schedule lookup errors give zero
+// minutes so clock out and corrections never fail because of schedules.
+func sessionTimeStats(scheduleRepo repository.ScheduleRepository, rosterRepo repository.RosterRepository, calendarRepo repository.CalendarRepository,
+	tenantID uuid.UUID, rosterID, scheduleID uuid.NullUUID, attendanceDate string, loc *time.Location, checkIn time.Time, checkOut *time.Time) models.AttendanceTimeStats {
+
+	checkIn = checkIn.In(loc)
+	var stats models.AttendanceTimeStats
+	if rosterID.Valid {
+		shift, err := rosterRepo.GetRosteredShift(tenantID, rosterID.UUID)
+		if err != nil {
+			return stats
+		}
+		start, err := utils.ParseShiftDate(attendanceDate, loc)
+		if err != nil {
+			return stats
+		}
+		if checkOut == nil {
+			stats.LateMinutes = utils.CalculateShiftLateMinutes(&shift.Schedule, start, checkIn)
+			return stats
+		}
+		return utils.CalculateShiftTimeStats(&shift.Schedule, start, checkIn, checkOut.In(loc))
+	}
+
+	if !scheduleID.Valid {
+		return stats
+	}
+	schedule, err := scheduleRepo.GetScheduleByID(tenantID, scheduleID.UUID)
+	if err != nil {
+		return stats
+	}
+	resolved, workingDay := resolveSchedule(calendarRepo, tenantID, schedule, checkIn)
+	switch {
+	case checkOut == nil && workingDay:
+		stats.LateMinutes = utils.CalculateLateMinutes(resolved, checkIn)
+	case checkOut == nil:
+	case workingDay:
+		stats = utils.CalculateAttendanceTimeStats(resolved, checkIn, checkOut.In(loc))
+	default:
+		stats = utils.NonWorkingDayTimeStats(checkIn, checkOut.In(loc))
+	}
+	return stats
+}
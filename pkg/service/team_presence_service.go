@@ -0,0 +1,54 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// TeamPresenceService builds the live presence board of a manager's team
+type TeamPresenceService interface {
+	GetTeamBoard(tenantID uuid.UUID, approver models.AttendanceApprover, now time.Time) (*models.TeamPresenceBoard, error)
+}
+
+type teamPresenceServiceImpl struct {
+	presenceRepo repository.TeamPresenceRepository
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewTeamPresenceService(presenceRepo repository.TeamPresenceRepository, settingsRepo repository.TenantSettingsRepository) TeamPresenceService {
+	return &teamPresenceServiceImpl{presenceRepo: presenceRepo, settingsRepo: settingsRepo}
+}
+
+// GetTeamBoard returns the presence of the approver's direct reports on today's date in the
+// tenant timezone. Admin/HR see the whole tenant.
+func (s *teamPresenceServiceImpl) GetTeamBoard(tenantID uuid.UUID, approver models.AttendanceApprover, now time.Time) (*models.TeamPresenceBoard, error) {
+	timezone, _ := s.settingsRepo.GetTimezone(tenantID)
+	loc := utils.ResolveTimezone(timezone)
+	board := &models.TeamPresenceBoard{
+		Date:     now.In(loc).Format("2006-01-02"),
+		Timezone: loc.String(),
+		Members:  []models.TeamMemberPresence{},
+	}
+
+	var supervisorID *uuid.UUID
+	if !approver.CanApproveAll {
+		if approver.EmployeeID == nil {
+			return board, nil
+		}
+		supervisorID = approver.EmployeeID
+	}
+
+	records, err := s.presenceRepo.GetTeamPresence(tenantID, supervisorID, board.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		board.Members = append(board.Members, utils.ResolvePresence(record, board.Date, loc))
+	}
+	board.Summary = utils.SummarizePresence(board.Members)
+	return board, nil
+}
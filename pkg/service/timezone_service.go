@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+	"github.com/nepskuy/be-godplan/pkg/repository"
+	"github.com/nepskuy/be-godplan/pkg/utils"
+)
+
+// TimezoneService resolves the local time zone of tenants and their offices
+type TimezoneService interface {
+	GetTenantTimezone(tenantID uuid.UUID) (string, error)
+	UpdateTenantTimezone(tenantID uuid.UUID, timezone string) error
+	TenantLocation(tenantID uuid.UUID) *time.Location
+	OfficeLocation(tenantID uuid.UUID, office *models.OfficeLocation) *time.Location
+}
+
+type timezoneServiceImpl struct {
+	settingsRepo repository.TenantSettingsRepository
+}
+
+func NewTimezoneService(settingsRepo repository.TenantSettingsRepository) TimezoneService {
+	return &timezoneServiceImpl{settingsRepo: settingsRepo}
+}
+
+func (s *timezoneServiceImpl) GetTenantTimezone(tenantID uuid.UUID) (string, error) {
+	return s.settingsRepo.GetTimezone(tenantID)
+}
+
+func (s *timezoneServiceImpl) UpdateTenantTimezone(tenantID uuid.UUID, timezone string) error {
+	timezone = strings.TrimSpace(timezone)
+	if _, err := utils.LoadTimezone(timezone); err != nil {
+		return err
+	}
+	return s.settingsRepo.SaveTimezone(tenantID, timezone)
+}
+
+// TenantLocation returns the tenant time zone. Lookup errors fall back to
+// utils.DefaultTimezone so attendance never fails because of the timezone.
+func (s *timezoneServiceImpl) TenantLocation(tenantID uuid.UUID) *time.Location {
+	timezone, _ := s.settingsRepo.GetTimezone(tenantID)
+	return utils.ResolveTimezone(timezone)
+}
+
+// OfficeLocation returns the time zone of the office, or of the tenant when the office
+// (or the env-configured default office) has none
+func (s *timezoneServiceImpl) OfficeLocation(tenantID uuid.UUID, office *models.OfficeLocation) *time.Location {
+	if office != nil && office.Timezone != "" {
+		if loc, err := utils.LoadTimezone(office.Timezone); err == nil {
+			return loc
+		}
+	}
+	return s.TenantLocation(tenantID)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalFilesPath is the route prefix that serves signed local files
+const LocalFilesPath = "/api/v1/files/"
+
+// LocalStorage stores objects on the local filesystem and signs URLs with HMAC-SHA256.
+// Files are served by the handlers.ServeFile endpoint after the signature is verified.
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+	secret  []byte
+}
+
+func NewLocalStorage(baseDir, baseURL, secret string) *LocalStorage {
+	return &LocalStorage{
+		baseDir: baseDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}
+}
+
+// Path resolves a key to its file path inside the storage directory
+func (s *LocalStorage) Path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	target, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// Tulis ke file sementara lalu rename agar pembaca tidak melihat file setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	target, err := s.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(target)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(key string, expiry time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+
+	return s.baseURL + LocalFilesPath + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+// Verify checks a signature produced by SignedURL and that it has not expired
+func (s *LocalStorage) Verify(key, expires, signature string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(s.sign(key, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapeKey URL-encodes each path segment of the key, keeping the slashes
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
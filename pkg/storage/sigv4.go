@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Minimal AWS Signature Version 4 implementation for the S3 operations we need
+// (PUT/GET/DELETE object and presigned GET URLs).
+
+const (
+	sigV4Algorithm   = "AWS4-HMAC-SHA256"
+	sigV4DateFormat  = "20060102T150405Z"
+	sigV4ShortFormat = "20060102"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+type sigV4Signer struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+	service         string
+}
+
+func (s sigV4Signer) scope(t time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", t.Format(sigV4ShortFormat), s.region, s.service)
+}
+
+func (s sigV4Signer) signingKey(t time.Time) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), t.Format(sigV4ShortFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// signRequest adds the Authorization header to req. payloadHash is the hex SHA-256 of the body.
+func (s sigV4Signer) signRequest(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(sigV4DateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headerNames := []string{"host"}
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "content-md5" || strings.HasPrefix(lower, "x-amz-") {
+			headerNames = append(headerNames, lower)
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	signature := s.signature(canonicalRequest, t)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.accessKeyID, s.scope(t), signedHeaders, signature))
+}
+
+// presign returns u with query string authentication valid for expiry
+func (s sigV4Signer) presign(method string, u *url.URL, expiry time.Duration, t time.Time) string {
+	t = t.UTC()
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+s.scope(t))
+	query.Set("X-Amz-Date", t.Format(sigV4DateFormat))
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int64(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(canonicalRequest, t))
+
+	signed := *u
+	signed.RawQuery = canonicalQuery(query)
+	return signed.String()
+}
+
+func (s sigV4Signer) signature(canonicalRequest string, t time.Time) string {
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		t.Format(sigV4DateFormat),
+		s.scope(t),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(s.signingKey(t), stringToSign))
+}
+
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+	// Re-encode with the stricter AWS rules
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			decoded = segment
+		}
+		segments[i] = awsURIEncode(decoded)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), values[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, awsURIEncode(k)+"="+awsURIEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode percent-encodes everything except unreserved characters (RFC 3986)
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/config"
+)
+
+var (
+	ErrObjectNotFound   = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Storage is a blob store for uploaded files such as attendance selfies.
+// Keys are slash separated relative paths, e.g. "attendance/<tenant>/2025/01/06/<file>.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a short-lived URL that can be used without authentication
+	SignedURL(key string, expiry time.Duration) (string, error)
+}
+
+// New creates the storage configured by STORAGE_DRIVER. Local storage is refused on
+// serverless deployments, where uploaded files would not persist.
+func New(cfg *config.Config) (Storage, error) {
+	if err := cfg.ValidateStorage(); err != nil {
+		return nil, err
+	}
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.StorageLocalPath, cfg.StoragePublicBaseURL, cfg.StorageSigningSecret), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+// ValidateKey rejects absolute paths, traversal segments and empty keys
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	if path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// AttendancePhotoPrefix prefixes every attendance selfie key. Photo columns holding a
+// value without this prefix still contain legacy inline base64 data.
+const AttendancePhotoPrefix = "attendance/"
+
+// AttendancePhotoKey builds a unique key for an attendance selfie, grouped per tenant and day
+func AttendancePhotoKey(tenantID, userID uuid.UUID, kind string, takenAt time.Time, ext string) string {
+	return fmt.Sprintf("%s%s/%s/%s-%s-%s%s", AttendancePhotoPrefix, tenantID, takenAt.Format("2006/01/02"),
+		userID, kind, uuid.New(), ext)
+}
+
+// IsAttendancePhotoKey reports whether a photo column value is a storage key
+func IsAttendancePhotoKey(value string) bool {
+	return strings.HasPrefix(value, AttendancePhotoPrefix)
+}
+
+// LeaveAttachmentPrefix prefixes every leave request attachment key (e.g. sick notes)
+const LeaveAttachmentPrefix = "leave/"
+
+// LeaveAttachmentKey builds a unique key for a leave request attachment, grouped per tenant and day
+func LeaveAttachmentKey(tenantID, employeeID uuid.UUID, uploadedAt time.Time, ext string) string {
+	return fmt.Sprintf("%s%s/%s/%s-%s%s", LeaveAttachmentPrefix, tenantID, uploadedAt.Format("2006/01/02"),
+		employeeID, uuid.New(), ext)
+}
+
+// ClientVisitPhotoPrefix prefixes every client visit photo key
+const ClientVisitPhotoPrefix = "visits/"
+
+// ClientVisitPhotoKey builds a unique key for a client visit photo, grouped per tenant and day
+func ClientVisitPhotoKey(tenantID, employeeID uuid.UUID, kind string, takenAt time.Time, ext string) string {
+	return fmt.Sprintf("%s%s/%s/%s-%s-%s%s", ClientVisitPhotoPrefix, tenantID, takenAt.Format("2006/01/02"),
+		employeeID, kind, uuid.New(), ext)
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// DefaultAttendancePolicy mengembalikan aturan attendance untuk tenant yang belum mengatur policy.
+// Location check mengikuti ENABLE_LOCATION_CHECK, sisanya sama dengan aturan lama yang hardcoded.
+func DefaultAttendancePolicy() models.AttendancePolicy {
+	cfg := config.Load()
+	return models.AttendancePolicy{
+		LocationCheckEnabled:     cfg.EnableLocationCheck,
+		RequireSelfie:            false,
+		AllowForce:               true,
+		AutoApproveMarginPercent: 10,
+		AccuracyMultipliers: models.AccuracyMultipliers{
+			Excellent: 0.5,
+			Good:      1.0,
+			Fair:      1.5,
+			Poor:      2.0,
+		},
+		MaxThresholdFactor: 3,
+		MinShiftMinutes:    0,
+	}
+}
+
+// EarliestClockOut returns the first moment a shift started at checkIn may be clocked out,
+// and whether now is still before it
+func EarliestClockOut(policy models.AttendancePolicy, checkIn, now time.Time) (time.Time, bool) {
+	earliest := checkIn.Add(time.Duration(policy.MinShiftMinutes) * time.Minute)
+	if policy.MinShiftMinutes <= 0 {
+		return earliest, false
+	}
+	return earliest, now.Before(earliest)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestDefaultAttendancePolicy_MatchesLegacyRules(t *testing.T) {
+	policy := DefaultAttendancePolicy()
+
+	for _, accuracy := range []float64{0, 5, 20, 40, 80} {
+		if got, want := AccuracyBufferWithPolicy(policy, accuracy), AccuracyBuffer(accuracy); got != want {
+			t.Errorf("accuracy %.0f: expected buffer %.1f, got %.1f", accuracy, want, got)
+		}
+	}
+	if got := AdaptiveThresholdWithPolicy(policy, 100, 200); got != 300 {
+		t.Errorf("Expected threshold capped at 300m, got %.1f", got)
+	}
+	if !policy.AllowForce || policy.RequireSelfie {
+		t.Error("Expected force allowed and selfie optional by default")
+	}
+}
+
+func TestAdaptiveThresholdWithPolicy(t *testing.T) {
+	policy := DefaultAttendancePolicy()
+	policy.AccuracyMultipliers.Poor = 1
+	policy.MaxThresholdFactor = 1.5
+
+	// 100m radius + 80m * 1 = 180m, capped at 150m
+	if got := AdaptiveThresholdWithPolicy(policy, 100, 80); got != 150 {
+		t.Errorf("Expected 150m, got %.1f", got)
+	}
+}
+
+func TestValidateLocationWithPolicy_AutoApproveMargin(t *testing.T) {
+	office := models.OfficeLocation{ID: uuid.New(), Name: "HQ", Latitude: -6.2000, Longitude: 106.8000, Radius: 100, IsActive: true}
+	offices := []models.OfficeLocation{office}
+
+	// ~115m north of the office, 15% over the radius
+	lat := -6.2000 + 115.0/111195.0
+
+	policy := DefaultAttendancePolicy()
+	policy.LocationCheckEnabled = true
+	if result := ValidateLocationWithPolicy(policy, offices, lat, 106.8000, 0); !result.NeedForce {
+		t.Errorf("Expected force needed with default 10%% margin, distance %.1fm", result.Distance)
+	}
+
+	policy.AutoApproveMarginPercent = 20
+	if result := ValidateLocationWithPolicy(policy, offices, lat, 106.8000, 0); result.NeedForce {
+		t.Errorf("Expected auto-approve with 20%% margin, distance %.1fm", result.Distance)
+	}
+
+	policy.LocationCheckEnabled = false
+	if match := FindNearestOfficeWithPolicy(policy, offices, -6.3000, 106.8000, 0); !match.InRange {
+		t.Error("Expected in range when location check is disabled")
+	}
+}
+
+func TestEarliestClockOut(t *testing.T) {
+	checkIn := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	policy := DefaultAttendancePolicy()
+
+	if _, tooEarly := EarliestClockOut(policy, checkIn, checkIn.Add(time.Minute)); tooEarly {
+		t.Error("Expected no minimum shift by default")
+	}
+
+	policy.MinShiftMinutes = 240
+	earliest, tooEarly := EarliestClockOut(policy, checkIn, checkIn.Add(3*time.Hour))
+	if !tooEarly || !earliest.Equal(checkIn.Add(4*time.Hour)) {
+		t.Errorf("Expected clock out blocked until 12:00, got %s (too early: %v)", earliest.Format("15:04"), tooEarly)
+	}
+	if _, tooEarly := EarliestClockOut(policy, checkIn, checkIn.Add(4*time.Hour)); tooEarly {
+		t.Error("Expected clock out allowed after the minimum shift")
+	}
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// WorkCalendar combines working weekdays with non-working dates (holidays, company days off)
+type WorkCalendar struct {
+	WorkingDays int             // bitmask, 0 = DefaultWorkingDays
+	Holidays    map[string]bool // keyed by YYYY-MM-DD
+}
+
+// NewWorkCalendar builds a calendar from a weekday bitmask and YYYY-MM-DD holiday dates
+func NewWorkCalendar(workingDays int, holidays []string) WorkCalendar {
+	calendar := WorkCalendar{WorkingDays: workingDays, Holidays: make(map[string]bool, len(holidays))}
+	for _, date := range holidays {
+		calendar.Holidays[date] = true
+	}
+	return calendar
+}
+
+// IsHoliday reports whether the date is a holiday or company day off
+func (c WorkCalendar) IsHoliday(date time.Time) bool {
+	return c.Holidays[date.Format("2006-01-02")]
+}
+
+// IsWorkingDate reports whether the date is a working weekday and not a holiday
+func (c WorkCalendar) IsWorkingDate(date time.Time) bool {
+	return IsWorkingDay(c.WorkingDays, date.Weekday()) && !c.IsHoliday(date)
+}
+
+// CountWorkingDays counts working dates between start and end (inclusive)
+func (c WorkCalendar) CountWorkingDays(start, end time.Time) int {
+	count := 0
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	for !day.After(last) {
+		if c.IsWorkingDate(day) {
+			count++
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return count
+}
+
+// NationalHoliday is a bundled Indonesian public holiday
+type NationalHoliday struct {
+	Date string // YYYY-MM-DD
+	Name string
+}
+
+// indonesianHolidays berisi hari libur nasional sesuai SKB 3 Menteri. Cuti bersama tidak
+// termasuk karena tidak wajib bagi perusahaan swasta, tambahkan sebagai libur perusahaan.
+var indonesianHolidays = map[int][]NationalHoliday{
+	2025: {
+		{"2025-01-01", "Tahun Baru 2025 Masehi"},
+		{"2025-01-27", "Isra Mikraj Nabi Muhammad SAW"},
+		{"2025-01-29", "Tahun Baru Imlek 2576 Kongzili"},
+		{"2025-03-29", "Hari Suci Nyepi (Tahun Baru Saka 1947)"},
+		{"2025-03-31", "Idul Fitri 1446 Hijriah"},
+		{"2025-04-01", "Idul Fitri 1446 Hijriah"},
+		{"2025-04-18", "Wafat Yesus Kristus"},
+		{"2025-04-20", "Kebangkitan Yesus Kristus (Paskah)"},
+		{"2025-05-01", "Hari Buruh Internasional"},
+		{"2025-05-12", "Hari Raya Waisak 2569 BE"},
+		{"2025-05-29", "Kenaikan Yesus Kristus"},
+		{"2025-06-01", "Hari Lahir Pancasila"},
+		{"2025-06-06", "Idul Adha 1446 Hijriah"},
+		{"2025-06-27", "1 Muharam Tahun Baru Islam 1447 Hijriah"},
+		{"2025-08-17", "Proklamasi Kemerdekaan"},
+		{"2025-09-05", "Maulid Nabi Muhammad SAW"},
+		{"2025-12-25", "Kelahiran Yesus Kristus"},
+	},
+	2026: {
+		{"2026-01-01", "Tahun Baru 2026 Masehi"},
+		{"2026-01-16", "Isra Mikraj Nabi Muhammad SAW"},
+		{"2026-02-17", "Tahun Baru Imlek 2577 Kongzili"},
+		{"2026-03-19", "Hari Suci Nyepi (Tahun Baru Saka 1948)"},
+		{"2026-03-20", "Idul Fitri 1447 Hijriah"},
+		{"2026-03-21", "Idul Fitri 1447 Hijriah"},
+		{"2026-04-03", "Wafat Yesus Kristus"},
+		{"2026-04-05", "Kebangkitan Yesus Kristus (Paskah)"},
+		{"2026-05-01", "Hari Buruh Internasional"},
+		{"2026-05-14", "Kenaikan Yesus Kristus"},
+		{"2026-05-27", "Idul Adha 1447 Hijriah"},
+		{"2026-05-31", "Hari Raya Waisak 2570 BE"},
+		{"2026-06-01", "Hari Lahir Pancasila"},
+		{"2026-06-16", "1 Muharam Tahun Baru Islam 1448 Hijriah"},
+		{"2026-08-17", "Proklamasi Kemerdekaan"},
+		{"2026-08-25", "Maulid Nabi Muhammad SAW"},
+		{"2026-12-25", "Kelahiran Yesus Kristus"},
+	},
+}
+
+// IndonesianHolidays returns the bundled national holidays of a year
+func IndonesianHolidays(year int) ([]NationalHoliday, bool) {
+	holidays, ok := indonesianHolidays[year]
+	return holidays, ok
+}
+
+// IndonesianHolidayYears returns the years with a bundled holiday set, oldest first
+func IndonesianHolidayYears() []int {
+	years := make([]int, 0, len(indonesianHolidays))
+	for year := range indonesianHolidays {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkCalendarCountWorkingDays(t *testing.T) {
+	// Senin 30 Maret - Minggu 5 April 2026, Jumat 3 April adalah Wafat Yesus Kristus
+	start := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 5, 0, 0, 0, 0, time.UTC)
+
+	calendar := NewWorkCalendar(0, []string{"2026-04-03"})
+	if got := calendar.CountWorkingDays(start, end); got != 4 {
+		t.Errorf("expected 4 working days, got %d", got)
+	}
+	if calendar.IsWorkingDate(time.Date(2026, 4, 3, 10, 0, 0, 0, time.UTC)) {
+		t.Error("expected holiday not to be a working date")
+	}
+
+	// Senin-Sabtu
+	sixDays := NewWorkCalendar(DefaultWorkingDays|1<<time.Saturday, []string{"2026-04-03"})
+	if got := sixDays.CountWorkingDays(start, end); got != 5 {
+		t.Errorf("expected 5 working days for a six-day week, got %d", got)
+	}
+}
+
+func TestIndonesianHolidays(t *testing.T) {
+	for _, year := range IndonesianHolidayYears() {
+		holidays, ok := IndonesianHolidays(year)
+		if !ok || len(holidays) == 0 {
+			t.Fatalf("expected bundled holidays for %d", year)
+		}
+		seen := map[string]bool{}
+		for _, holiday := range holidays {
+			date, err := time.Parse("2006-01-02", holiday.Date)
+			if err != nil || date.Year() != year {
+				t.Errorf("holiday %q has invalid date %q", holiday.Name, holiday.Date)
+			}
+			if seen[holiday.Date] {
+				t.Errorf("duplicate holiday date %s", holiday.Date)
+			}
+			seen[holiday.Date] = true
+		}
+	}
+
+	if _, ok := IndonesianHolidays(1999); ok {
+		t.Error("expected no bundled holidays for 1999")
+	}
+}
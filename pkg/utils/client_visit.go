@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// VisitDurationMinutes menghitung lama kunjungan dalam menit (dibulatkan 1 desimal)
+func VisitDurationMinutes(checkIn, checkOut time.Time) float64 {
+	if checkOut.Before(checkIn) {
+		return 0
+	}
+	return math.Round(checkOut.Sub(checkIn).Minutes()*10) / 10
+}
+
+// SummarizeVisits menyusun laporan kunjungan harian seorang sales. Kunjungan yang masih
+// berlangsung dihitung sampai now, jam ditampilkan dalam loc.
+func SummarizeVisits(date string, employeeID uuid.UUID, visits []models.ClientVisit, now time.Time, loc *time.Location) models.DailyVisitReport {
+	report := models.DailyVisitReport{
+		Date:       date,
+		EmployeeID: employeeID,
+		Visits:     []models.ClientVisit{},
+	}
+
+	clients := map[string]bool{}
+	for _, visit := range visits {
+		visit.CheckInTime = visit.CheckInTime.In(loc)
+		visit.CheckOutTime = inLocation(visit.CheckOutTime, loc)
+		if visit.CheckOutTime == nil {
+			visit.DurationMinutes = VisitDurationMinutes(visit.CheckInTime, now)
+		} else {
+			report.CompletedVisits++
+		}
+
+		report.TotalVisits++
+		report.TotalDurationMinutes += visit.DurationMinutes
+		clients[strings.ToLower(strings.TrimSpace(visit.Client))] = true
+		report.Visits = append(report.Visits, visit)
+	}
+	report.Clients = len(clients)
+	report.TotalDurationMinutes = math.Round(report.TotalDurationMinutes*10) / 10
+	return report
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestVisitDurationMinutes(t *testing.T) {
+	checkIn := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if got := VisitDurationMinutes(checkIn, checkIn.Add(95*time.Minute+30*time.Second)); got != 95.5 {
+		t.Errorf("expected 95.5 minutes, got %v", got)
+	}
+	if got := VisitDurationMinutes(checkIn, checkIn.Add(-time.Minute)); got != 0 {
+		t.Errorf("expected 0 for check-out before check-in, got %v", got)
+	}
+}
+
+func TestSummarizeVisits(t *testing.T) {
+	loc := ResolveTimezone("Asia/Jakarta")
+	base := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC) // 09:00 WIB
+	firstOut := base.Add(45 * time.Minute)
+	now := base.Add(3 * time.Hour)
+
+	visits := []models.ClientVisit{
+		{Client: "PT Maju Jaya", CheckInTime: base, CheckOutTime: &firstOut, DurationMinutes: 45},
+		{Client: "pt maju jaya ", CheckInTime: base.Add(2 * time.Hour)},
+	}
+	report := SummarizeVisits("2026-03-02", uuid.New(), visits, now, loc)
+
+	if report.TotalVisits != 2 || report.CompletedVisits != 1 {
+		t.Errorf("expected 2 visits with 1 completed, got %d/%d", report.TotalVisits, report.CompletedVisits)
+	}
+	if report.Clients != 1 {
+		t.Errorf("expected 1 distinct client, got %d", report.Clients)
+	}
+	// Ongoing visit counts until now: 45 + 60 minutes
+	if report.TotalDurationMinutes != 105 {
+		t.Errorf("expected 105 minutes, got %v", report.TotalDurationMinutes)
+	}
+	if got := report.Visits[0].CheckInTime.Hour(); got != 9 {
+		t.Errorf("expected check-in shown at 09 WIB, got %d", got)
+	}
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// Fraud flags recorded on godplan.attendances.fraud_flags
+const (
+	FraudFlagMockLocation      = "mock_location"
+	FraudFlagPerfectAccuracy   = "perfect_accuracy"
+	FraudFlagZeroAltitude      = "zero_altitude"
+	FraudFlagExcessiveSpeed    = "excessive_speed"
+	FraudFlagImpossibleTravel  = "impossible_travel"
+	FraudFlagReusedCoordinates = "reused_coordinates"
+	FraudFlagClockDrift        = "clock_drift"
+	FraudFlagReusedPhoto       = "reused_photo"   // selfie matches an earlier selfie of the same user
+	FraudFlagSharedPhoto       = "shared_photo"   // selfie matches a selfie of another user in the tenant
+	FraudFlagStalePhoto        = "stale_photo"    // EXIF capture time is far from the clock event
+	FraudFlagRepeatedPhoto     = "repeated_photo" // clock out selfie matches the clock in selfie of the same attendance
+)
+
+const (
+	// FraudSuspiciousScore is the score from which a clock event goes to supervisor approval
+	FraudSuspiciousScore = 50
+
+	// Real GPS fixes practically never report sub-meter accuracy
+	perfectAccuracyMeters = 1.0
+	// ~200 km/h reported by the device while clocking in
+	maxDeviceSpeedMps = 55.0
+	// ~250 km/h between two consecutive clock events
+	maxTravelSpeedMps = 70.0
+	// Ignore GPS drift and nearby offices when checking travel speed
+	minTravelDistanceMeters = 1000.0
+	// Selfie taken from the camera moments before the clock event, not picked from the gallery
+	maxPhotoAge = 10 * time.Minute
+)
+
+var fraudFlagScores = map[string]int{
+	FraudFlagMockLocation:      60,
+	FraudFlagPerfectAccuracy:   25,
+	FraudFlagZeroAltitude:      15,
+	FraudFlagExcessiveSpeed:    30,
+	FraudFlagImpossibleTravel:  50,
+	FraudFlagReusedCoordinates: 40,
+	FraudFlagClockDrift:        50,
+	FraudFlagReusedPhoto:       50,
+	FraudFlagSharedPhoto:       60,
+	FraudFlagStalePhoto:        50,
+	FraudFlagRepeatedPhoto:     50,
+}
+
+// FraudSignals collects everything known about a clock event for fraud scoring
+type FraudSignals struct {
+	Location      LocationCheckWithAccuracy
+	Time          time.Time
+	PreviousEvent *models.ClockEvent // latest earlier clock in/out of the same user
+	ReusedOnDays  int                // other days where the exact same coordinates were recorded
+}
+
+// AssessFraudRisk scores a clock event for GPS spoofing. Each signal adds a fixed weight;
+// strong signals (mock flag, impossible travel) are suspicious on their own while weak
+// ones (perfect accuracy, zero altitude) only matter in combination.
+func AssessFraudRisk(signals FraudSignals) models.FraudAssessment {
+	assessment := models.FraudAssessment{Flags: []string{}}
+	add := func(flag string) {
+		assessment.Flags = append(assessment.Flags, flag)
+		assessment.Score += fraudFlagScores[flag]
+	}
+
+	loc := signals.Location
+	if loc.IsMocked {
+		add(FraudFlagMockLocation)
+	}
+	if loc.Accuracy > 0 && loc.Accuracy < perfectAccuracyMeters {
+		add(FraudFlagPerfectAccuracy)
+	}
+	if loc.Altitude != nil && *loc.Altitude == 0 {
+		add(FraudFlagZeroAltitude)
+	}
+	if loc.Speed != nil && *loc.Speed > maxDeviceSpeedMps {
+		add(FraudFlagExcessiveSpeed)
+	}
+
+	if prev := signals.PreviousEvent; prev != nil {
+		distance := CalculateDistance(prev.Latitude, prev.Longitude, loc.Latitude, loc.Longitude)
+		if distance > minTravelDistanceMeters {
+			elapsed := signals.Time.Sub(prev.Time).Seconds()
+			if elapsed <= 0 || distance/elapsed > maxTravelSpeedMps {
+				add(FraudFlagImpossibleTravel)
+			}
+		}
+	}
+
+	if signals.ReusedOnDays > 0 {
+		add(FraudFlagReusedCoordinates)
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	assessment.Suspicious = assessment.Score >= FraudSuspiciousScore
+
+	if len(assessment.Flags) > 0 {
+		log.Printf("🛡️ [Fraud] Score: %d | Flags: %v | Suspicious: %v", assessment.Score, assessment.Flags, assessment.Suspicious)
+	}
+	return assessment
+}
+
+// MergeFraudAssessments combines assessments of one attendance (clock in and clock out, GPS,
+// selfie and clock drift). The score is the sum of the weights of the union of flags, capped
+// at 100, so weak signals from different checks add up like they do in AssessFraudRisk.
+func MergeFraudAssessments(a, b models.FraudAssessment) models.FraudAssessment {
+	merged := models.FraudAssessment{Flags: []string{}}
+	for _, flag := range append(append([]string{}, a.Flags...), b.Flags...) {
+		exists := false
+		for _, f := range merged.Flags {
+			if f == flag {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			merged.Flags = append(merged.Flags, flag)
+			merged.Score += fraudFlagScores[flag]
+		}
+	}
+	if merged.Score > 100 {
+		merged.Score = 100
+	}
+	merged.Suspicious = merged.Score >= FraudSuspiciousScore
+	return merged
+}
+
+// AssessClockDrift flags an offline clock event synced from a device whose clock differed
+// from the server clock by more than MaxClockDrift. The device time cannot be trusted, so
+// the event is suspicious on its own.
+func AssessClockDrift(drift time.Duration) models.FraudAssessment {
+	assessment := models.FraudAssessment{Flags: []string{}}
+	if ClockDriftExceeded(drift) {
+		assessment.Flags = append(assessment.Flags, FraudFlagClockDrift)
+		assessment.Score = fraudFlagScores[FraudFlagClockDrift]
+		log.Printf("🛡️ [Fraud] Device clock drift: %s", drift)
+	}
+	assessment.Suspicious = assessment.Score >= FraudSuspiciousScore
+	return assessment
+}
+
+// SelfieSignals collects the reuse signals of a clock in/out selfie
+type SelfieSignals struct {
+	UserID     uuid.UUID
+	Time       time.Time
+	Match      *models.PhotoMatch // closest earlier selfie of the tenant, nil when none is similar
+	CapturedAt *time.Time         // EXIF capture time, nil when the photo has none
+	// SameAsCheckIn is set when a clock out selfie matches the clock in selfie of the same attendance
+	SameAsCheckIn bool
+}
+
+// AssessSelfie flags a selfie that was uploaded before (by the same user or by a colleague),
+// a clock out selfie that repeats the clock in selfie, or a selfie whose EXIF capture time is
+// far from the clock event. Each signal is suspicious on its own so the attendance goes to
+// supervisor review.
+func AssessSelfie(signals SelfieSignals) models.FraudAssessment {
+	assessment := models.FraudAssessment{Flags: []string{}}
+	add := func(flag string) {
+		assessment.Flags = append(assessment.Flags, flag)
+		assessment.Score += fraudFlagScores[flag]
+	}
+
+	if match := signals.Match; match != nil {
+		if match.UserID == signals.UserID {
+			add(FraudFlagReusedPhoto)
+		} else {
+			add(FraudFlagSharedPhoto)
+		}
+	}
+	if signals.SameAsCheckIn {
+		add(FraudFlagRepeatedPhoto)
+	}
+	if captured := signals.CapturedAt; captured != nil {
+		if age := signals.Time.Sub(*captured); age > maxPhotoAge || age < -maxPhotoAge {
+			add(FraudFlagStalePhoto)
+		}
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	assessment.Suspicious = assessment.Score >= FraudSuspiciousScore
+
+	if len(assessment.Flags) > 0 {
+		log.Printf("🛡️ [Fraud] Selfie score: %d | Flags: %v", assessment.Score, assessment.Flags)
+	}
+	return assessment
+}
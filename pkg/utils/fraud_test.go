@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestAssessFraudRiskCleanEvent(t *testing.T) {
+	now := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	assessment := AssessFraudRisk(FraudSignals{
+		Location: LocationCheckWithAccuracy{
+			Latitude: -6.2088, Longitude: 106.8456, Accuracy: 12,
+			Altitude: floatPtr(34.2), Speed: floatPtr(0.4),
+		},
+		Time:          now,
+		PreviousEvent: &models.ClockEvent{Latitude: -6.3000, Longitude: 106.7000, Time: now.Add(-14 * time.Hour)},
+	})
+
+	if assessment.Suspicious || assessment.Score != 0 || len(assessment.Flags) != 0 {
+		t.Errorf("expected clean event, got %+v", assessment)
+	}
+}
+
+func TestAssessFraudRiskImpossibleTravel(t *testing.T) {
+	now := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	// Jakarta -> Surabaya (~660km) in 30 minutes
+	assessment := AssessFraudRisk(FraudSignals{
+		Location:      LocationCheckWithAccuracy{Latitude: -7.2575, Longitude: 112.7521, Accuracy: 15},
+		Time:          now,
+		PreviousEvent: &models.ClockEvent{Latitude: -6.2088, Longitude: 106.8456, Time: now.Add(-30 * time.Minute)},
+	})
+
+	if !assessment.Suspicious || len(assessment.Flags) != 1 || assessment.Flags[0] != FraudFlagImpossibleTravel {
+		t.Errorf("expected impossible travel to be suspicious, got %+v", assessment)
+	}
+}
+
+func TestAssessFraudRiskWeakSignalsCombine(t *testing.T) {
+	loc := LocationCheckWithAccuracy{Latitude: -6.2088, Longitude: 106.8456, Accuracy: 0.5, Altitude: floatPtr(0)}
+
+	weak := AssessFraudRisk(FraudSignals{Location: loc, Time: time.Now()})
+	if weak.Suspicious || weak.Score != 40 {
+		t.Errorf("expected perfect accuracy + zero altitude alone to stay below threshold, got %+v", weak)
+	}
+
+	reused := AssessFraudRisk(FraudSignals{Location: loc, Time: time.Now(), ReusedOnDays: 3})
+	if !reused.Suspicious {
+		t.Errorf("expected reused coordinates with weak signals to be suspicious, got %+v", reused)
+	}
+}
+
+func TestMergeFraudAssessments(t *testing.T) {
+	merged := MergeFraudAssessments(
+		models.FraudAssessment{Score: 25, Flags: []string{FraudFlagPerfectAccuracy}},
+		models.FraudAssessment{Score: 60, Flags: []string{FraudFlagMockLocation, FraudFlagPerfectAccuracy}},
+	)
+	if merged.Score != 85 || !merged.Suspicious || len(merged.Flags) != 2 {
+		t.Errorf("unexpected merge result %+v", merged)
+	}
+
+	// Two weak signals, each below the threshold on its own, add up to a flag
+	combined := MergeFraudAssessments(
+		models.FraudAssessment{Score: 25, Flags: []string{FraudFlagPerfectAccuracy}},
+		models.FraudAssessment{Score: 30, Flags: []string{FraudFlagExcessiveSpeed}},
+	)
+	if combined.Score != 55 || !combined.Suspicious {
+		t.Errorf("expected sub-threshold assessments to cross the threshold together, got %+v", combined)
+	}
+
+	capped := MergeFraudAssessments(
+		models.FraudAssessment{Score: 60, Flags: []string{FraudFlagMockLocation}},
+		models.FraudAssessment{Score: 100, Flags: []string{FraudFlagSharedPhoto, FraudFlagClockDrift}},
+	)
+	if capped.Score != 100 {
+		t.Errorf("expected merged score capped at 100, got %d", capped.Score)
+	}
+}
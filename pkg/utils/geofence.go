@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"errors"
+	"math"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+const (
+	earthRadiusMeters = 6371000
+	// minPolygonAreaSquareMeters menolak ring dengan titik kembar atau segaris (luas nol)
+	minPolygonAreaSquareMeters = 1.0
+)
+
+var ErrInvalidPolygon = errors.New("boundary must be a GeoJSON Polygon with at least 3 distinct, non-collinear positions per ring")
+
+// point adalah koordinat dalam meter pada proyeksi lokal (equirectangular)
+type point struct {
+	x, y float64
+}
+
+// localProjection memproyeksikan lat/lng ke bidang datar dalam meter di sekitar titik referensi.
+// Cukup akurat untuk geofence kantor (skala ratusan meter sampai beberapa km).
+type localProjection struct {
+	lat0, lng0, cosLat0 float64
+}
+
+func newLocalProjection(lat0, lng0 float64) localProjection {
+	return localProjection{lat0: lat0, lng0: lng0, cosLat0: math.Cos(lat0 * math.Pi / 180)}
+}
+
+func (p localProjection) project(lat, lng float64) point {
+	return point{
+		x: (lng - p.lng0) * math.Pi / 180 * earthRadiusMeters * p.cosLat0,
+		y: (lat - p.lat0) * math.Pi / 180 * earthRadiusMeters,
+	}
+}
+
+// NormalizePolygon memvalidasi GeoJSON Polygon dan menutup ring yang belum tertutup
+func NormalizePolygon(polygon *models.GeoJSONPolygon) error {
+	if polygon == nil || polygon.Type != "Polygon" || len(polygon.Coordinates) == 0 {
+		return ErrInvalidPolygon
+	}
+
+	for i, ring := range polygon.Coordinates {
+		for _, position := range ring {
+			if len(position) < 2 || position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+				return ErrInvalidPolygon
+			}
+		}
+
+		if len(ring) > 0 {
+			first, last := ring[0], ring[len(ring)-1]
+			if first[0] != last[0] || first[1] != last[1] {
+				ring = append(ring, []float64{first[0], first[1]})
+				polygon.Coordinates[i] = ring
+			}
+		}
+
+		// Ring tertutup butuh minimal 3 titik berbeda + titik penutup, dan luasnya tidak nol.
+		// Ring tanpa luas membuat PolygonEquivalentRadius 0 sehingga kantor tidak pernah cocok.
+		if distinctPositions(ring) < 3 {
+			return ErrInvalidPolygon
+		}
+		proj := newLocalProjection(ring[0][1], ring[0][0])
+		if math.Abs(ringSignedArea(proj, ring)) < minPolygonAreaSquareMeters {
+			return ErrInvalidPolygon
+		}
+	}
+
+	// Hole yang menutupi seluruh outer ring juga menyisakan luas nol
+	if PolygonArea(polygon) < minPolygonAreaSquareMeters {
+		return ErrInvalidPolygon
+	}
+
+	return nil
+}
+
+// distinctPositions menghitung titik berbeda pada ring tertutup (titik penutup tidak dihitung)
+func distinctPositions(ring [][]float64) int {
+	seen := make(map[[2]float64]bool, len(ring))
+	for _, position := range ring[:len(ring)-1] {
+		seen[[2]float64{position[0], position[1]}] = true
+	}
+	return len(seen)
+}
+
+// PolygonCentroid mengembalikan titik tengah (rata-rata vertex) outer ring sebagai lat, lng
+func PolygonCentroid(polygon *models.GeoJSONPolygon) (float64, float64) {
+	outer := polygon.Coordinates[0]
+	n := len(outer) - 1 // abaikan titik penutup
+	var sumLat, sumLng float64
+	for _, position := range outer[:n] {
+		sumLng += position[0]
+		sumLat += position[1]
+	}
+	return sumLat / float64(n), sumLng / float64(n)
+}
+
+// PolygonArea menghitung luas polygon dalam meter persegi (outer ring dikurangi holes)
+func PolygonArea(polygon *models.GeoJSONPolygon) float64 {
+	lat0, lng0 := PolygonCentroid(polygon)
+	proj := newLocalProjection(lat0, lng0)
+
+	area := 0.0
+	for i, ring := range polygon.Coordinates {
+		ringArea := math.Abs(ringSignedArea(proj, ring))
+		if i == 0 {
+			area += ringArea
+		} else {
+			area -= ringArea
+		}
+	}
+	return math.Max(area, 0)
+}
+
+// PolygonEquivalentRadius mengembalikan radius lingkaran dengan luas yang sama dengan polygon.
+// Dipakai sebagai skala pembanding agar aturan adaptive radius berlaku sama untuk polygon.
+func PolygonEquivalentRadius(polygon *models.GeoJSONPolygon) float64 {
+	return math.Sqrt(PolygonArea(polygon) / math.Pi)
+}
+
+// PolygonDistanceToEdge mengecek apakah titik berada di dalam polygon (di luar semua hole)
+// dan mengembalikan jarak terdekat ke batas polygon dalam meter
+func PolygonDistanceToEdge(polygon *models.GeoJSONPolygon, lat, lng float64) (bool, float64) {
+	proj := newLocalProjection(lat, lng)
+	p := point{}
+
+	inside := false
+	edgeDistance := math.Inf(1)
+	for i, ring := range polygon.Coordinates {
+		projected := make([]point, len(ring))
+		for j, position := range ring {
+			projected[j] = proj.project(position[1], position[0])
+		}
+
+		inRing := pointInRing(p, projected)
+		if i == 0 {
+			inside = inRing
+		} else if inRing {
+			// Titik berada di dalam hole
+			inside = false
+		}
+
+		for j := 0; j < len(projected)-1; j++ {
+			edgeDistance = math.Min(edgeDistance, distanceToSegment(p, projected[j], projected[j+1]))
+		}
+	}
+
+	return inside, edgeDistance
+}
+
+// pointInRing menggunakan algoritma ray casting
+func pointInRing(p point, ring []point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.y > p.y) != (b.y > p.y) && p.x < (b.x-a.x)*(p.y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func distanceToSegment(p, a, b point) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return math.Hypot(p.x-a.x, p.y-a.y)
+	}
+
+	t := ((p.x-a.x)*dx + (p.y-a.y)*dy) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p.x-(a.x+t*dx), p.y-(a.y+t*dy))
+}
+
+func ringSignedArea(proj localProjection, ring [][]float64) float64 {
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		a := proj.project(ring[i][1], ring[i][0])
+		b := proj.project(ring[i+1][1], ring[i+1][0])
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// gpsQualityOrder adalah urutan bucket GPS quality di laporan simulasi
+var gpsQualityOrder = []string{"excellent", "good", "fair", "poor", "unknown"}
+
+// ApplyRadiusOverrides mengembalikan salinan kantor dengan radius usulan. Override per kantor
+// menang atas radius global; kantor polygon tidak punya radius sehingga tidak berubah.
+func ApplyRadiusOverrides(offices []models.OfficeLocation, radius int, overrides []models.GeofenceRadiusOverride) []models.OfficeLocation {
+	byOffice := make(map[uuid.UUID]int, len(overrides))
+	for _, o := range overrides {
+		byOffice[o.OfficeLocationID] = o.Radius
+	}
+
+	proposed := make([]models.OfficeLocation, len(offices))
+	for i, office := range offices {
+		proposed[i] = office
+		if office.Boundary != nil {
+			continue
+		}
+		if r, ok := byOffice[office.ID]; ok {
+			proposed[i].Radius = r
+		} else if radius > 0 {
+			proposed[i].Radius = radius
+		}
+	}
+	return proposed
+}
+
+// SimulateGeofence me-replay clock in GPS dengan konfigurasi geofence saat ini dan usulan,
+// lalu menghitung berapa yang berpindah outcome per kantor dan per bucket GPS quality.
+// Kantor di breakdown adalah kantor terdekat menurut konfigurasi saat ini.
+func SimulateGeofence(samples []models.GeofenceReplaySample,
+	currentOffices []models.OfficeLocation, currentPolicy models.AttendancePolicy,
+	proposedOffices []models.OfficeLocation, proposedPolicy models.AttendancePolicy) models.GeofenceSimulationReport {
+
+	var report models.GeofenceSimulationReport
+	total := newSimulationTally()
+	byOffice := map[string]*simulationTally{}
+	var officeKeys []string
+	byQuality := map[string]*simulationTally{}
+
+	for _, s := range samples {
+		currentMatch := nearestOffice(currentPolicy, currentOffices, s.Latitude, s.Longitude, s.Accuracy)
+		current := GeofenceOutcome(currentPolicy, currentMatch)
+		proposed := GeofenceOutcome(proposedPolicy,
+			nearestOffice(proposedPolicy, proposedOffices, s.Latitude, s.Longitude, s.Accuracy))
+
+		officeKey := ""
+		if id := currentMatch.OfficeID(); id != nil {
+			officeKey = id.String()
+		}
+		office, ok := byOffice[officeKey]
+		if !ok {
+			office = newSimulationTally()
+			office.name = currentMatch.OfficeName()
+			byOffice[officeKey] = office
+			officeKeys = append(officeKeys, officeKey)
+		}
+
+		quality := GetGPSQuality(s.Accuracy)
+		bucket, ok := byQuality[quality]
+		if !ok {
+			bucket = newSimulationTally()
+			bucket.name = quality
+			byQuality[quality] = bucket
+		}
+
+		for _, tally := range []*simulationTally{total, office, bucket} {
+			tally.add(current, proposed)
+		}
+
+		// Clock in tanpa accuracy tercatat di-replay tanpa toleransi GPS, flip-nya belum tentu nyata
+		if s.Accuracy <= 0 {
+			report.MissingAccuracy++
+			if current != proposed {
+				report.MissingAccuracyChanged++
+			}
+		}
+	}
+
+	group := total.group("", "")
+	report.Total, report.Changed = group.Total, group.Changed
+	report.Current, report.Proposed, report.Transitions = group.Current, group.Proposed, group.Transitions
+
+	sort.SliceStable(officeKeys, func(i, j int) bool { return byOffice[officeKeys[i]].name < byOffice[officeKeys[j]].name })
+	report.ByOffice = make([]models.GeofenceSimulationGroup, 0, len(officeKeys))
+	for _, key := range officeKeys {
+		report.ByOffice = append(report.ByOffice, byOffice[key].group(key, byOffice[key].name))
+	}
+
+	report.ByGPSQuality = make([]models.GeofenceSimulationGroup, 0, len(byQuality))
+	for _, quality := range gpsQualityOrder {
+		if bucket, ok := byQuality[quality]; ok {
+			report.ByGPSQuality = append(report.ByGPSQuality, bucket.group(quality, quality))
+		}
+	}
+
+	return report
+}
+
+// simulationTally mengumpulkan outcome replay untuk satu grup
+type simulationTally struct {
+	name        string
+	total       int
+	changed     int
+	current     models.GeofenceOutcomeCounts
+	proposed    models.GeofenceOutcomeCounts
+	transitions map[[2]string]int
+}
+
+func newSimulationTally() *simulationTally {
+	return &simulationTally{transitions: map[[2]string]int{}}
+}
+
+func (t *simulationTally) add(current, proposed string) {
+	t.total++
+	countOutcome(&t.current, current)
+	countOutcome(&t.proposed, proposed)
+	if current != proposed {
+		t.changed++
+		t.transitions[[2]string{current, proposed}]++
+	}
+}
+
+func (t *simulationTally) group(key, name string) models.GeofenceSimulationGroup {
+	transitions := make([]models.GeofenceTransition, 0, len(t.transitions))
+	for flip, count := range t.transitions {
+		transitions = append(transitions, models.GeofenceTransition{From: flip[0], To: flip[1], Count: count})
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if transitions[i].Count != transitions[j].Count {
+			return transitions[i].Count > transitions[j].Count
+		}
+		if transitions[i].From != transitions[j].From {
+			return transitions[i].From < transitions[j].From
+		}
+		return transitions[i].To < transitions[j].To
+	})
+
+	return models.GeofenceSimulationGroup{
+		Key:         key,
+		Name:        name,
+		Total:       t.total,
+		Changed:     t.changed,
+		Current:     t.current,
+		Proposed:    t.proposed,
+		Transitions: transitions,
+	}
+}
+
+func countOutcome(counts *models.GeofenceOutcomeCounts, outcome string) {
+	switch outcome {
+	case models.GeofenceOutcomeApproved:
+		counts.Approved++
+	case models.GeofenceOutcomeAutoApproved:
+		counts.AutoApproved++
+	default:
+		counts.NeedsForce++
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestSimulateGeofence_TighterRadius(t *testing.T) {
+	hq := models.OfficeLocation{ID: uuid.New(), Name: "HQ", Latitude: -6.2000, Longitude: 106.8000, Radius: 200, IsActive: true}
+	branch := models.OfficeLocation{ID: uuid.New(), Name: "Branch", Latitude: -6.3000, Longitude: 106.9000, Radius: 100, IsActive: true}
+	offices := []models.OfficeLocation{hq, branch}
+
+	metersNorth := func(office models.OfficeLocation, meters float64) (float64, float64) {
+		return office.Latitude + meters/111195.0, office.Longitude
+	}
+	sample := func(office models.OfficeLocation, meters, accuracy float64) models.GeofenceReplaySample {
+		lat, lng := metersNorth(office, meters)
+		return models.GeofenceReplaySample{AttendanceID: uuid.New(), Latitude: lat, Longitude: lng, Accuracy: accuracy}
+	}
+	samples := []models.GeofenceReplaySample{
+		sample(hq, 50, 5),      // stays approved
+		sample(hq, 150, 0),     // approved -> needs force with 100m
+		sample(hq, 105, 0),     // approved -> auto-approved (5% over 100m)
+		sample(branch, 50, 30), // branch keeps its radius
+	}
+
+	policy := DefaultAttendancePolicy()
+	policy.LocationCheckEnabled = true
+	proposed := ApplyRadiusOverrides(offices, 0, []models.GeofenceRadiusOverride{{OfficeLocationID: hq.ID, Radius: 100}})
+	if offices[0].Radius != 200 {
+		t.Fatal("Expected ApplyRadiusOverrides to copy the offices")
+	}
+
+	report := SimulateGeofence(samples, offices, policy, proposed, policy)
+	if report.Total != 4 || report.Changed != 2 {
+		t.Fatalf("Expected 2 of 4 clock ins to change, got %d of %d", report.Changed, report.Total)
+	}
+	if report.Current.Approved != 4 || report.Proposed.Approved != 2 || report.Proposed.AutoApproved != 1 || report.Proposed.NeedsForce != 1 {
+		t.Errorf("Unexpected outcome counts: current %+v, proposed %+v", report.Current, report.Proposed)
+	}
+	if report.MissingAccuracy != 2 || report.MissingAccuracyChanged != 2 {
+		t.Errorf("Expected 2 changed clock ins without accuracy, got %d of %d", report.MissingAccuracyChanged, report.MissingAccuracy)
+	}
+
+	if len(report.ByOffice) != 2 || report.ByOffice[1].Name != "HQ" || report.ByOffice[1].Changed != 2 || report.ByOffice[0].Changed != 0 {
+		t.Errorf("Unexpected office breakdown: %+v", report.ByOffice)
+	}
+	if len(report.ByGPSQuality) != 3 || report.ByGPSQuality[2].Key != "unknown" || report.ByGPSQuality[2].Changed != 2 {
+		t.Errorf("Unexpected GPS quality breakdown: %+v", report.ByGPSQuality)
+	}
+}
+
+func TestSimulateGeofence_AccuracyMultipliers(t *testing.T) {
+	office := models.OfficeLocation{ID: uuid.New(), Name: "HQ", Latitude: -6.2000, Longitude: 106.8000, Radius: 100, IsActive: true}
+	offices := []models.OfficeLocation{office}
+	// 160m away with poor 60m accuracy: 100 + 60*2 = 220m today, 100 + 60*0.5 = 130m proposed
+	samples := []models.GeofenceReplaySample{{AttendanceID: uuid.New(), Latitude: -6.2000 + 160.0/111195.0, Longitude: 106.8000, Accuracy: 60}}
+
+	current := DefaultAttendancePolicy()
+	current.LocationCheckEnabled = true
+	proposed := current
+	proposed.AccuracyMultipliers.Poor = 0.5
+
+	report := SimulateGeofence(samples, offices, current, offices, proposed)
+	if len(report.Transitions) != 1 || report.Transitions[0].From != models.GeofenceOutcomeApproved || report.Transitions[0].To != models.GeofenceOutcomeNeedsForce {
+		t.Errorf("Expected approved -> needs_force, got %+v", report.Transitions)
+	}
+}
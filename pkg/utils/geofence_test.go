@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// campusPolygon is a ~222m x ~221m square with a ~44m square hole in the middle
+func campusPolygon() *models.GeoJSONPolygon {
+	return &models.GeoJSONPolygon{
+		Type: "Polygon",
+		Coordinates: [][][]float64{
+			{{106.799, -6.201}, {106.801, -6.201}, {106.801, -6.199}, {106.799, -6.199}, {106.799, -6.201}},
+			{{106.7998, -6.2002}, {106.8002, -6.2002}, {106.8002, -6.1998}, {106.7998, -6.1998}, {106.7998, -6.2002}},
+		},
+	}
+}
+
+func TestPolygonDistanceToEdge(t *testing.T) {
+	polygon := campusPolygon()
+
+	inside, edge := PolygonDistanceToEdge(polygon, -6.2005, 106.8)
+	if !inside {
+		t.Error("Expected point between outer ring and hole to be inside")
+	}
+	if math.Abs(edge-33) > 3 {
+		t.Errorf("Expected ~33m to the hole edge, got %.1fm", edge)
+	}
+
+	inside, _ = PolygonDistanceToEdge(polygon, -6.2, 106.8)
+	if inside {
+		t.Error("Expected point in hole to be outside")
+	}
+
+	inside, edge = PolygonDistanceToEdge(polygon, -6.2, 106.8015)
+	if inside {
+		t.Error("Expected point east of polygon to be outside")
+	}
+	if math.Abs(edge-55) > 3 {
+		t.Errorf("Expected ~55m to the east edge, got %.1fm", edge)
+	}
+}
+
+func TestNormalizePolygon(t *testing.T) {
+	open := &models.GeoJSONPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{106.799, -6.201}, {106.801, -6.201}, {106.801, -6.199}}},
+	}
+	if err := NormalizePolygon(open); err != nil {
+		t.Fatalf("Expected open ring to be closed, got %v", err)
+	}
+	if len(open.Coordinates[0]) != 4 {
+		t.Errorf("Expected closing position to be appended, got %d positions", len(open.Coordinates[0]))
+	}
+
+	invalid := []*models.GeoJSONPolygon{
+		nil,
+		{Type: "Point", Coordinates: [][][]float64{{{106.8, -6.2}}}},
+		{Type: "Polygon", Coordinates: [][][]float64{{{106.8, -6.2}, {106.9, -6.2}}}},
+		{Type: "Polygon", Coordinates: [][][]float64{{{200, -6.2}, {106.9, -6.2}, {106.9, -6.3}}}},
+		// Titik kembar: 4 posisi tapi hanya 2 yang berbeda
+		{Type: "Polygon", Coordinates: [][][]float64{{{106.8, -6.2}, {106.9, -6.2}, {106.8, -6.2}, {106.9, -6.2}}}},
+		// Titik segaris: luas nol
+		{Type: "Polygon", Coordinates: [][][]float64{{{106.8, -6.2}, {106.85, -6.2}, {106.9, -6.2}}}},
+	}
+	for i, polygon := range invalid {
+		if err := NormalizePolygon(polygon); err == nil {
+			t.Errorf("Case %d: expected invalid polygon error", i)
+		}
+	}
+}
+
+func TestFindNearestOffice_Polygon(t *testing.T) {
+	lat, lng := PolygonCentroid(campusPolygon())
+	campus := models.OfficeLocation{
+		ID: uuid.New(), Name: "Campus", Latitude: lat, Longitude: lng, Boundary: campusPolygon(), IsActive: true,
+	}
+	offices := []models.OfficeLocation{campus}
+
+	match := FindNearestOffice(offices, -6.2005, 106.8, 0)
+	if !match.InRange || match.GeofenceType() != "polygon" {
+		t.Errorf("Expected in range of polygon, got in range %v (%s)", match.InRange, match.GeofenceType())
+	}
+	if match.DistanceToEdge >= 0 {
+		t.Errorf("Expected negative distance to edge when inside, got %.1f", match.DistanceToEdge)
+	}
+
+	// 11m outside the east edge: rejected with no accuracy, accepted with good GPS buffer (15m * 1.0)
+	if FindNearestOffice(offices, -6.2, 106.8011, 0).InRange {
+		t.Error("Expected out of range without GPS buffer")
+	}
+	if !FindNearestOffice(offices, -6.2, 106.8011, 15).InRange {
+		t.Error("Expected GPS accuracy buffer to cover a point just outside the edge")
+	}
+
+	// Standing in the hole is out of range
+	if FindNearestOffice(offices, -6.2, 106.8, 0).InRange {
+		t.Error("Expected point in hole to be out of range")
+	}
+}
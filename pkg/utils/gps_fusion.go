@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxGPSSamples adalah jumlah maksimal reading GPS dalam satu request
+	MaxGPSSamples = 10
+	// GPSSampleWindow membatasi umur reading terhadap reading terbaru, fix lama diabaikan
+	GPSSampleWindow = 60 * time.Second
+	// MaxGPSSampleOffset adalah selisih maksimal timestamp reading terhadap waktu clock event,
+	// reading lama atau yang dikarang tidak dipakai
+	MaxGPSSampleOffset = 5 * time.Minute
+
+	// unknownSampleAccuracy dipakai untuk reading tanpa accuracy dari device
+	unknownSampleAccuracy = 50.0
+	// minFusedAccuracy mencegah fused accuracy yang terlalu optimistis
+	minFusedAccuracy = 3.0
+	// outlierSigma: reading yang lebih jauh dari outlierSigma x accuracy-nya dari centroid dibuang
+	outlierSigma = 2.0
+)
+
+var (
+	ErrNoValidGPSSamples = errors.New("samples must contain at least one valid GPS reading")
+	ErrStaleGPSSamples   = errors.New("samples must be recorded within 5 minutes of the clock event")
+)
+
+// GPSSample adalah satu reading GPS dari device beserta waktunya
+type GPSSample struct {
+	Latitude  float64   `json:"latitude" example:"-6.2088"`
+	Longitude float64   `json:"longitude" example:"106.8456"`
+	Accuracy  float64   `json:"accuracy" example:"15.5"` // meter
+	Timestamp time.Time `json:"timestamp" example:"2026-01-05T08:00:03+07:00"`
+}
+
+// GPSFusion adalah posisi hasil penggabungan beberapa reading GPS
+type GPSFusion struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy"` // estimasi accuracy posisi gabungan (meter)
+
+	// Confidence 0-1, turun jika banyak reading dibuang atau posisi gabungan masih kurang akurat
+	Confidence      float64 `json:"confidence"`
+	SampleCount     int     `json:"sample_count"`
+	RejectedSamples int     `json:"rejected_samples"`
+
+	// MinRawAccuracy adalah accuracy terbaik yang dilaporkan device di antara semua reading
+	// (0 jika tidak ada), dipakai fraud scoring karena fused accuracy dibatasi minimal 3m
+	MinRawAccuracy float64 `json:"-"`
+}
+
+// FuseGPSSamples menggabungkan reading GPS menjadi satu posisi: reading yang timestamp-nya
+// lebih dari MaxGPSSampleOffset dari waktu clock event (at, menurut jam device) dan reading di
+// luar GPSSampleWindow dari reading terbaru dibuang, lalu dihitung centroid berbobot 1/accuracy²
+// dan reading yang menyimpang jauh dari centroid (outlier) dibuang satu per satu sebelum
+// centroid dihitung ulang.
+func FuseGPSSamples(samples []GPSSample, at time.Time) (GPSFusion, error) {
+	var minRawAccuracy float64
+	valid := make([]GPSSample, 0, len(samples))
+	outOfTime := 0
+	for _, s := range samples {
+		if s.Accuracy > 0 && (minRawAccuracy == 0 || s.Accuracy < minRawAccuracy) {
+			minRawAccuracy = s.Accuracy
+		}
+		if s.Latitude < -90 || s.Latitude > 90 || s.Longitude < -180 || s.Longitude > 180 ||
+			(s.Latitude == 0 && s.Longitude == 0) {
+			continue
+		}
+		if offset := s.Timestamp.Sub(at); offset > MaxGPSSampleOffset || offset < -MaxGPSSampleOffset {
+			outOfTime++
+			continue
+		}
+		if s.Accuracy <= 0 {
+			s.Accuracy = unknownSampleAccuracy
+		}
+		valid = append(valid, s)
+	}
+	if len(valid) == 0 {
+		if outOfTime > 0 {
+			return GPSFusion{}, ErrStaleGPSSamples
+		}
+		return GPSFusion{}, ErrNoValidGPSSamples
+	}
+
+	// Hanya reading dalam window terakhir yang dipakai, fix pertama yang basi tidak ikut dihitung
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].Timestamp.Before(valid[j].Timestamp) })
+	newest := valid[len(valid)-1].Timestamp
+	recent := valid[:0]
+	for _, s := range valid {
+		if newest.Sub(s.Timestamp) <= GPSSampleWindow {
+			recent = append(recent, s)
+		}
+	}
+	rejected := len(samples) - len(recent)
+
+	// Buang outlier terburuk satu per satu, minimal setengah reading tetap dipakai.
+	// Dengan 3 reading atau lebih outlier pertama diukur dari median, agar satu fix yang
+	// mengklaim accuracy sangat baik tidak menarik centroid ke arahnya.
+	inliers := recent
+	lat, lng := weightedCentroid(inliers)
+	if len(inliers) >= 3 {
+		lat, lng = medianPosition(inliers)
+	}
+	for len(inliers) > 1 && len(inliers)*2 > len(recent) {
+		worst, worstScore := -1, outlierSigma
+		for i, s := range inliers {
+			if score := CalculateDistance(lat, lng, s.Latitude, s.Longitude) / s.Accuracy; score > worstScore {
+				worst, worstScore = i, score
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		inliers = append(append([]GPSSample{}, inliers[:worst]...), inliers[worst+1:]...)
+		rejected++
+		lat, lng = weightedCentroid(inliers)
+	}
+	lat, lng = weightedCentroid(inliers)
+
+	// Accuracy gabungan: inverse-variance, tapi tidak lebih baik dari sebaran reading itu sendiri
+	var weightSum, spread float64
+	for _, s := range inliers {
+		w := 1 / (s.Accuracy * s.Accuracy)
+		d := CalculateDistance(lat, lng, s.Latitude, s.Longitude)
+		weightSum += w
+		spread += w * d * d
+	}
+	accuracy := math.Max(1/math.Sqrt(weightSum), math.Sqrt(spread/weightSum))
+	accuracy = math.Max(accuracy, minFusedAccuracy)
+
+	inlierRatio := float64(len(inliers)) / float64(len(samples))
+	confidence := inlierRatio / (1 + accuracy/25)
+
+	return GPSFusion{
+		Latitude:        lat,
+		Longitude:       lng,
+		Accuracy:        math.Round(accuracy*10) / 10,
+		Confidence:      math.Round(confidence*100) / 100,
+		SampleCount:     len(samples),
+		RejectedSamples: rejected,
+		MinRawAccuracy:  minRawAccuracy,
+	}, nil
+}
+
+// weightedCentroid menghitung rata-rata posisi berbobot 1/accuracy². Reading berdekatan
+// (puluhan meter) sehingga rata-rata derajat cukup akurat.
+func weightedCentroid(samples []GPSSample) (float64, float64) {
+	var lat, lng, weightSum float64
+	for _, s := range samples {
+		w := 1 / (s.Accuracy * s.Accuracy)
+		lat += s.Latitude * w
+		lng += s.Longitude * w
+		weightSum += w
+	}
+	return lat / weightSum, lng / weightSum
+}
+
+// medianPosition mengembalikan median latitude dan longitude secara terpisah
+func medianPosition(samples []GPSSample) (float64, float64) {
+	lats := make([]float64, len(samples))
+	lngs := make([]float64, len(samples))
+	for i, s := range samples {
+		lats[i], lngs[i] = s.Latitude, s.Longitude
+	}
+	return median(lats), median(lngs)
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
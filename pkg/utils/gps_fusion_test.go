@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFuseGPSSamples_RejectsBadFirstFix(t *testing.T) {
+	start := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	officeLat, officeLng := -6.2000, 106.8000
+	samples := []GPSSample{
+		// First fix ~300m away but claiming 20m accuracy
+		{Latitude: officeLat + 300.0/111195.0, Longitude: officeLng, Accuracy: 20, Timestamp: start},
+		{Latitude: officeLat + 0.00002, Longitude: officeLng, Accuracy: 12, Timestamp: start.Add(2 * time.Second)},
+		{Latitude: officeLat - 0.00001, Longitude: officeLng + 0.00001, Accuracy: 8, Timestamp: start.Add(4 * time.Second)},
+		{Latitude: officeLat, Longitude: officeLng - 0.00001, Accuracy: 6, Timestamp: start.Add(6 * time.Second)},
+	}
+
+	fused, err := FuseGPSSamples(samples, start.Add(6*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fused.RejectedSamples != 1 || fused.SampleCount != 4 {
+		t.Errorf("Expected 1 of 4 samples rejected, got %d of %d", fused.RejectedSamples, fused.SampleCount)
+	}
+	if d := CalculateDistance(officeLat, officeLng, fused.Latitude, fused.Longitude); d > 5 {
+		t.Errorf("Expected fused position within 5m of the office, got %.1fm", d)
+	}
+	if fused.Accuracy > 8 {
+		t.Errorf("Expected fused accuracy better than the best sample, got %.1fm", fused.Accuracy)
+	}
+	if fused.Confidence <= 0 || fused.Confidence >= 1 {
+		t.Errorf("Expected confidence between 0 and 1, got %.2f", fused.Confidence)
+	}
+}
+
+func TestFuseGPSSamples_WeightsByAccuracy(t *testing.T) {
+	start := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	samples := []GPSSample{
+		{Latitude: -6.2000 + 60.0/111195.0, Longitude: 106.8000, Accuracy: 80, Timestamp: start},
+		{Latitude: -6.2000, Longitude: 106.8000, Accuracy: 8, Timestamp: start.Add(3 * time.Second)},
+	}
+
+	fused, err := FuseGPSSamples(samples, start.Add(3*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := CalculateDistance(-6.2000, 106.8000, fused.Latitude, fused.Longitude); d > 2 {
+		t.Errorf("Expected fused position dominated by the accurate fix, got %.1fm away", d)
+	}
+}
+
+func TestFuseGPSSamples_DropsStaleAndInvalid(t *testing.T) {
+	start := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	samples := []GPSSample{
+		{Latitude: -6.3000, Longitude: 106.8000, Accuracy: 5, Timestamp: start},
+		{Latitude: 0, Longitude: 0, Accuracy: 5, Timestamp: start.Add(2 * time.Minute)},
+		{Latitude: -6.2000, Longitude: 106.8000, Accuracy: 0, Timestamp: start.Add(2 * time.Minute)},
+	}
+
+	fused, err := FuseGPSSamples(samples, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(fused.Latitude+6.2000) > 1e-9 || fused.RejectedSamples != 2 {
+		t.Errorf("Expected only the latest valid sample to be used, got %.4f with %d rejected", fused.Latitude, fused.RejectedSamples)
+	}
+	if fused.Accuracy != unknownSampleAccuracy {
+		t.Errorf("Expected unknown accuracy to default to %.0fm, got %.1f", unknownSampleAccuracy, fused.Accuracy)
+	}
+
+	if _, err := FuseGPSSamples([]GPSSample{{Latitude: 91, Longitude: 0, Timestamp: start}}, start); err != ErrNoValidGPSSamples {
+		t.Errorf("Expected ErrNoValidGPSSamples, got %v", err)
+	}
+}
+
+func TestFuseGPSSamples_RejectsSamplesFarFromClockEvent(t *testing.T) {
+	now := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	samples := []GPSSample{
+		{Latitude: -6.3000, Longitude: 106.8000, Accuracy: 5, Timestamp: now.Add(-time.Hour)},
+		{Latitude: -6.2000, Longitude: 106.8000, Accuracy: 0.5, Timestamp: now.Add(-2 * time.Second)},
+		{Latitude: -6.2000, Longitude: 106.8000, Accuracy: 6, Timestamp: now},
+	}
+
+	fused, err := FuseGPSSamples(samples, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(fused.Latitude+6.2000) > 1e-9 || fused.RejectedSamples != 1 {
+		t.Errorf("Expected the hour old sample to be rejected, got %.4f with %d rejected", fused.Latitude, fused.RejectedSamples)
+	}
+	if fused.Accuracy < minFusedAccuracy || fused.MinRawAccuracy != 0.5 {
+		t.Errorf("Expected fused accuracy clamped and raw accuracy kept, got %.1f and %.1f", fused.Accuracy, fused.MinRawAccuracy)
+	}
+
+	if _, err := FuseGPSSamples(samples[:1], now); err != ErrStaleGPSSamples {
+		t.Errorf("Expected ErrStaleGPSSamples, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// MaxPhotoSize is the maximum decoded size of an uploaded selfie
+const MaxPhotoSize = 5 << 20
+
+// MaxAttachmentSize is the maximum decoded size of an uploaded document such as a sick note
+const MaxAttachmentSize = 10 << 20
+
+var (
+	ErrInvalidImage       = errors.New("photo must be a base64 encoded JPEG or PNG image")
+	ErrImageTooLarge      = errors.New("photo exceeds the 5MB size limit")
+	ErrInvalidAttachment  = errors.New("attachment must be a base64 encoded JPEG, PNG or PDF file")
+	ErrAttachmentTooLarge = errors.New("attachment exceeds the 10MB size limit")
+)
+
+// DecodeBase64Image decodes a base64 photo (optionally as a data URI) and returns the
+// image bytes with their detected content type. Only JPEG and PNG are accepted.
+func DecodeBase64Image(value string) ([]byte, string, error) {
+	data, err := decodeBase64File(value, MaxPhotoSize, ErrInvalidImage, ErrImageTooLarge)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, "", ErrInvalidImage
+	}
+	return data, contentType, nil
+}
+
+// DecodeBase64Attachment decodes a base64 document (optionally as a data URI).
+// JPEG, PNG and PDF are accepted, e.g. a photo or scan of a doctor's note.
+func DecodeBase64Attachment(value string) ([]byte, string, error) {
+	data, err := decodeBase64File(value, MaxAttachmentSize, ErrInvalidAttachment, ErrAttachmentTooLarge)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "application/pdf":
+		return data, contentType, nil
+	default:
+		return nil, "", ErrInvalidAttachment
+	}
+}
+
+func decodeBase64File(value string, maxSize int, errInvalid, errTooLarge error) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "data:") {
+		comma := strings.Index(value, ",")
+		if comma < 0 || !strings.Contains(value[:comma], ";base64") {
+			return nil, errInvalid
+		}
+		value = value[comma+1:]
+	}
+
+	if base64.StdEncoding.DecodedLen(len(value)) > maxSize+3 {
+		return nil, errTooLarge
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		// Beberapa client mengirim base64 tanpa padding
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return nil, errInvalid
+		}
+	}
+	if len(data) > maxSize {
+		return nil, errTooLarge
+	}
+	return data, nil
+}
+
+// ImageExtension returns the file extension for a supported image content type
+func ImageExtension(contentType string) string {
+	if contentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// AttachmentExtension returns the file extension for a supported attachment content type
+func AttachmentExtension(contentType string) string {
+	if contentType == "application/pdf" {
+		return ".pdf"
+	}
+	return ImageExtension(contentType)
+}
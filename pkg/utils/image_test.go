@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeBase64Image(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	for _, input := range []string{encoded, "data:image/png;base64," + encoded} {
+		data, contentType, err := DecodeBase64Image(input)
+		if err != nil {
+			t.Fatalf("DecodeBase64Image(%q) failed: %v", input[:20], err)
+		}
+		if contentType != "image/png" || len(data) != len(png) {
+			t.Errorf("expected %d bytes of image/png, got %d bytes of %s", len(png), len(data), contentType)
+		}
+	}
+
+	if _, _, err := DecodeBase64Image(base64.StdEncoding.EncodeToString([]byte("not an image"))); err != ErrInvalidImage {
+		t.Errorf("expected ErrInvalidImage for text payload, got %v", err)
+	}
+	if _, _, err := DecodeBase64Image("%%%"); err != ErrInvalidImage {
+		t.Errorf("expected ErrInvalidImage for invalid base64, got %v", err)
+	}
+}
+
+func TestDecodeBase64Attachment(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n1 0 obj")
+	data, contentType, err := DecodeBase64Attachment("data:application/pdf;base64," + base64.StdEncoding.EncodeToString(pdf))
+	if err != nil {
+		t.Fatalf("DecodeBase64Attachment failed: %v", err)
+	}
+	if contentType != "application/pdf" || len(data) != len(pdf) || AttachmentExtension(contentType) != ".pdf" {
+		t.Errorf("expected %d bytes of application/pdf, got %d bytes of %s", len(pdf), len(data), contentType)
+	}
+
+	if _, _, err := DecodeBase64Attachment(base64.StdEncoding.EncodeToString([]byte("plain text note"))); err != ErrInvalidAttachment {
+		t.Errorf("expected ErrInvalidAttachment for text payload, got %v", err)
+	}
+}
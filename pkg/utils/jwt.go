@@ -119,6 +119,55 @@ func (j *JWTUtil) ValidateRefreshToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// StreamTokenTTL is the lifetime of a stream token. It only has to outlive opening the
+// connection, the client requests a new one before reconnecting.
+const StreamTokenTTL = time.Minute
+
+// GenerateStreamToken creates a short-lived token for server-sent event streams. Browser
+// EventSource cannot send an Authorization header, so the token travels in the query string
+// and is only accepted by stream endpoints (ValidateToken rejects it).
+func (j *JWTUtil) GenerateStreamToken(userID uuid.UUID, role string, tenantID uuid.UUID) (string, error) {
+	claims := &Claims{
+		UserID:   userID,
+		Role:     role,
+		TenantID: tenantID,
+		Type:     "stream",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(StreamTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(j.SecretKey))
+}
+
+// ValidateStreamToken validates a stream token
+func (j *JWTUtil) ValidateStreamToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(j.SecretKey), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if claims.Type != "stream" {
+		return nil, errors.New("invalid token type")
+	}
+
+	return claims, nil
+}
+
 // GetUserIDFromToken extracts user ID from token string
 func (j *JWTUtil) GetUserIDFromToken(tokenString string) (uuid.UUID, error) {
 	// Use ParseWithClaims directly to avoid the "access" type check in ValidateToken
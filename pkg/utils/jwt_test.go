@@ -2,13 +2,17 @@ package utils
 
 import (
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 func TestJWTUtil(t *testing.T) {
 	jwtUtil := NewJWTUtil("test-secret-key")
+	userID := uuid.New()
+	tenantID := uuid.New()
 
 	// Test GenerateToken
-	token, err := jwtUtil.GenerateToken(1, "test@example.com", "employee")
+	token, err := jwtUtil.GenerateToken(userID, "test@example.com", "employee", tenantID)
 	if err != nil {
 		t.Fatalf("GenerateToken failed: %v", err)
 	}
@@ -21,8 +25,11 @@ func TestJWTUtil(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ValidateToken failed: %v", err)
 	}
-	if claims.UserID != 1 {
-		t.Errorf("Expected UserID 1, got %d", claims.UserID)
+	if claims.UserID != userID {
+		t.Errorf("Expected UserID %s, got %s", userID, claims.UserID)
+	}
+	if claims.TenantID != tenantID {
+		t.Errorf("Expected TenantID %s, got %s", tenantID, claims.TenantID)
 	}
 	if claims.Email != "test@example.com" {
 		t.Errorf("Expected Email test@example.com, got %s", claims.Email)
@@ -43,9 +50,37 @@ func TestJWTUtil_InvalidToken(t *testing.T) {
 
 	// Test with different secret key
 	otherJWTUtil := NewJWTUtil("different-secret-key")
-	token, _ := jwtUtil.GenerateToken(1, "test@example.com", "employee")
+	token, _ := jwtUtil.GenerateToken(uuid.New(), "test@example.com", "employee", uuid.New())
 	_, err = otherJWTUtil.ValidateToken(token)
 	if err == nil {
 		t.Error("Expected error for token with different secret, but got none")
 	}
 }
+
+func TestJWTUtil_StreamToken(t *testing.T) {
+	jwtUtil := NewJWTUtil("test-secret-key")
+	userID := uuid.New()
+	tenantID := uuid.New()
+
+	token, err := jwtUtil.GenerateStreamToken(userID, "manager", tenantID)
+	if err != nil {
+		t.Fatalf("GenerateStreamToken failed: %v", err)
+	}
+
+	claims, err := jwtUtil.ValidateStreamToken(token)
+	if err != nil {
+		t.Fatalf("ValidateStreamToken failed: %v", err)
+	}
+	if claims.UserID != userID || claims.TenantID != tenantID || claims.Role != "manager" {
+		t.Errorf("Unexpected stream token claims: %+v", claims)
+	}
+
+	// Stream token hanya untuk endpoint stream, bukan pengganti access token
+	if _, err := jwtUtil.ValidateToken(token); err == nil {
+		t.Error("Expected stream token to be rejected as access token")
+	}
+	access, _ := jwtUtil.GenerateToken(userID, "test@example.com", "manager", tenantID)
+	if _, err := jwtUtil.ValidateStreamToken(access); err == nil {
+		t.Error("Expected access token to be rejected as stream token")
+	}
+}
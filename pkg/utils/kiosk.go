@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KioskCodePeriod adalah lama satu QR code kiosk berlaku sebelum berganti
+const KioskCodePeriod = 30 * time.Second
+
+var ErrInvalidKioskCode = errors.New("invalid or expired kiosk QR code")
+
+// GenerateKioskCode membuat QR code kiosk untuk periode 30 detik yang mencakup waktu at.
+// Format: <kiosk id>.<periode>.<HMAC-SHA256 terpotong 128 bit>, ditandatangani secret kiosk.
+func GenerateKioskCode(secret []byte, kioskID uuid.UUID, at time.Time) (string, time.Time) {
+	step := kioskCodeStep(at)
+	expiresAt := time.Unix((step+1)*int64(KioskCodePeriod/time.Second), 0)
+	return fmt.Sprintf("%s.%d.%s", kioskID, step, kioskCodeSignature(secret, kioskID, step)), expiresAt
+}
+
+// ParseKioskCodeID mengambil kiosk ID dari QR code agar secret kiosk bisa dicari
+func ParseKioskCodeID(code string) (uuid.UUID, error) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 3 {
+		return uuid.Nil, ErrInvalidKioskCode
+	}
+	kioskID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, ErrInvalidKioskCode
+	}
+	return kioskID, nil
+}
+
+// VerifyKioskCode memvalidasi QR code kiosk pada waktu at. Code periode sebelumnya
+// masih diterima agar scan di detik terakhir tidak gagal karena latency jaringan.
+func VerifyKioskCode(secret []byte, code string, at time.Time) bool {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 3 {
+		return false
+	}
+	kioskID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return false
+	}
+	step, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false
+	}
+
+	current := kioskCodeStep(at)
+	if step != current && step != current-1 {
+		return false
+	}
+
+	expected := kioskCodeSignature(secret, kioskID, step)
+	return hmac.Equal([]byte(parts[2]), []byte(expected))
+}
+
+// GenerateRandomToken menghasilkan string hex acak dari n byte crypto/rand
+func GenerateRandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken mengembalikan SHA-256 hex dari token, dipakai agar token kiosk tidak disimpan mentah
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func kioskCodeStep(at time.Time) int64 {
+	return at.Unix() / int64(KioskCodePeriod/time.Second)
+}
+
+func kioskCodeSignature(secret []byte, kioskID uuid.UUID, step int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s.%d", kioskID, step)
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKioskCode(t *testing.T) {
+	secret := []byte("kiosk-secret")
+	kioskID := uuid.New()
+	issuedAt := time.Date(2025, 1, 6, 8, 0, 10, 0, time.UTC)
+
+	code, expiresAt := GenerateKioskCode(secret, kioskID, issuedAt)
+	if !expiresAt.Equal(time.Date(2025, 1, 6, 8, 0, 30, 0, time.UTC)) {
+		t.Errorf("unexpected expiry %v", expiresAt)
+	}
+
+	parsedID, err := ParseKioskCodeID(code)
+	if err != nil || parsedID != kioskID {
+		t.Fatalf("ParseKioskCodeID = %v, %v", parsedID, err)
+	}
+
+	if !VerifyKioskCode(secret, code, issuedAt) {
+		t.Error("expected code to be valid in its own period")
+	}
+	if !VerifyKioskCode(secret, code, issuedAt.Add(KioskCodePeriod)) {
+		t.Error("expected code to be valid one period later")
+	}
+	if VerifyKioskCode(secret, code, issuedAt.Add(2*KioskCodePeriod)) {
+		t.Error("expected code to expire after two periods")
+	}
+	if VerifyKioskCode([]byte("other-secret"), code, issuedAt) {
+		t.Error("expected code signed with another secret to fail")
+	}
+
+	tampered := strings.Replace(code, kioskID.String(), uuid.New().String(), 1)
+	if VerifyKioskCode(secret, tampered, issuedAt) {
+		t.Error("expected code with another kiosk ID to fail")
+	}
+}
+
+func TestParseKioskCodeIDInvalid(t *testing.T) {
+	for _, code := range []string{"", "not-a-code", "x.1.abc"} {
+		if _, err := ParseKioskCodeID(code); err == nil {
+			t.Errorf("expected ParseKioskCodeID(%q) to fail", code)
+		}
+	}
+}
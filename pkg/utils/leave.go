@@ -0,0 +1,28 @@
+package utils
+
+import "time"
+
+// ProrateLeaveQuota menghitung jatah cuti tahunan untuk tahun tertentu. Karyawan yang
+// bergabung di tahun tersebut mendapat jatah proporsional dari bulan bergabung
+// (bulan bergabung ikut dihitung), dibulatkan ke bawah.
+func ProrateLeaveQuota(annualQuota int, joinDate *time.Time, year int) int {
+	if joinDate == nil || joinDate.Year() < year {
+		return annualQuota
+	}
+	if joinDate.Year() > year {
+		return 0
+	}
+	months := 12 - int(joinDate.Month()) + 1
+	return annualQuota * months / 12
+}
+
+// CarryOverLeaveDays mengembalikan sisa cuti tahun lalu yang boleh dibawa, maksimal maxCarryOver hari
+func CarryOverLeaveDays(previousRemaining, maxCarryOver int) int {
+	if previousRemaining <= 0 || maxCarryOver <= 0 {
+		return 0
+	}
+	if previousRemaining > maxCarryOver {
+		return maxCarryOver
+	}
+	return previousRemaining
+}
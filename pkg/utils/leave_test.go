@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProrateLeaveQuota(t *testing.T) {
+	joined := time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		joinDate *time.Time
+		year     int
+		expected int
+	}{
+		{"no join date", nil, 2025, 12},
+		{"joined in an earlier year", &joined, 2026, 12},
+		{"joined in April", &joined, 2025, 9},
+		{"joined in a later year", &joined, 2024, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProrateLeaveQuota(12, tt.joinDate, tt.year); got != tt.expected {
+				t.Errorf("expected %d days, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCarryOverLeaveDays(t *testing.T) {
+	if got := CarryOverLeaveDays(8, 5); got != 5 {
+		t.Errorf("expected carry over capped at 5, got %d", got)
+	}
+	if got := CarryOverLeaveDays(3, 5); got != 3 {
+		t.Errorf("expected full remaining 3 days, got %d", got)
+	}
+	if got := CarryOverLeaveDays(-2, 5); got != 0 {
+		t.Errorf("expected no carry over for negative remaining, got %d", got)
+	}
+}
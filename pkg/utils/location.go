@@ -5,9 +5,14 @@ import (
 	"log"
 	"math"
 
+	"github.com/google/uuid"
 	"github.com/nepskuy/be-godplan/pkg/config"
+	"github.com/nepskuy/be-godplan/pkg/models"
 )
 
+// DefaultOfficeName dipakai untuk kantor dari env var (OFFICE_LATITUDE/OFFICE_LONGITUDE)
+const DefaultOfficeName = "Kantor Pusat Godplan"
+
 // CalculateDistance menghitung jarak dalam meter antara dua koordinat menggunakan rumus Haversine
 func CalculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const R = 6371000 // Radius bumi dalam meter
@@ -34,67 +39,91 @@ type GPSAccuracy int
 
 const (
 	AccuracyExcellent GPSAccuracy = iota // < 10m
-	AccuracyGood                          // 10-25m
-	AccuracyFair                          // 25-50m
-	AccuracyPoor                          // > 50m
+	AccuracyGood                         // 10-25m
+	AccuracyFair                         // 25-50m
+	AccuracyPoor                         // > 50m
 )
 
 // LocationCheckRequest with accuracy information from device
 type LocationCheckWithAccuracy struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Accuracy  float64 `json:"accuracy"` // GPS accuracy in meters dari device
-	Altitude  float64 `json:"altitude,omitempty"`
-	Heading   float64 `json:"heading,omitempty"`
-	Speed     float64 `json:"speed,omitempty"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Accuracy  float64  `json:"accuracy"`            // GPS accuracy in meters dari device
+	Altitude  *float64 `json:"altitude,omitempty"`  // meter, nil jika device tidak mengirim
+	Heading   *float64 `json:"heading,omitempty"`   // derajat
+	Speed     *float64 `json:"speed,omitempty"`     // meter per detik
+	IsMocked  bool     `json:"is_mocked,omitempty"` // flag mock location dari OS (Android)
 }
 
-// AdaptiveThreshold calculates dynamic threshold based on GPS accuracy
-// Semakin buruk GPS accuracy, semakin besar threshold yang diberikan
-func AdaptiveThreshold(baseRadius float64, gpsAccuracy float64) float64 {
-	// Base threshold
-	threshold := baseRadius
+// AccuracyBuffer menghitung toleransi tambahan (meter) berdasarkan GPS accuracy
+// Formula: buffer = gpsAccuracy * multiplier, multiplier menurun seiring GPS accuracy membaik
+func AccuracyBuffer(gpsAccuracy float64) float64 {
+	return AccuracyBufferWithPolicy(DefaultAttendancePolicy(), gpsAccuracy)
+}
 
-	// Jika GPS accuracy buruk, tambahkan tolerance
-	// Formula: threshold = baseRadius + (gpsAccuracy * multiplier)
-	// multiplier menurun seiring GPS accuracy membaik
-	
+// AccuracyBufferWithPolicy menghitung toleransi GPS dengan multiplier akurasi milik tenant
+func AccuracyBufferWithPolicy(policy models.AttendancePolicy, gpsAccuracy float64) float64 {
+	multipliers := policy.AccuracyMultipliers
 	switch {
 	case gpsAccuracy == 0:
-		// No accuracy data provided, use base radius only
-		return threshold
-		
+		// No accuracy data provided, no adjustment
+		return 0
 	case gpsAccuracy < 10:
 		// Excellent GPS - minimal adjustment
-		threshold += gpsAccuracy * 0.5
-		log.Printf("📍 [GPS] Excellent accuracy (%.1fm) - threshold: %.1fm", gpsAccuracy, threshold)
-		
+		return gpsAccuracy * multipliers.Excellent
 	case gpsAccuracy < 25:
-		// Good GPS - moderate adjustment  
-		threshold += gpsAccuracy * 1.0
-		log.Printf("📍 [GPS] Good accuracy (%.1fm) - threshold: %.1fm", gpsAccuracy, threshold)
-		
+		// Good GPS - moderate adjustment
+		return gpsAccuracy * multipliers.Good
 	case gpsAccuracy < 50:
 		// Fair GPS - significant adjustment
-		threshold += gpsAccuracy * 1.5
-		log.Printf("⚠️ [GPS] Fair accuracy (%.1fm) - threshold: %.1fm", gpsAccuracy, threshold)
-		
+		return gpsAccuracy * multipliers.Fair
 	default:
 		// Poor GPS - maximum adjustment
-		threshold += gpsAccuracy * 2.0
-		log.Printf("⚠️ [GPS] Poor accuracy (%.1fm) - threshold: %.1fm", gpsAccuracy, threshold)
+		return gpsAccuracy * multipliers.Poor
 	}
+}
 
-	// Cap maximum threshold untuk keamanan
-	maxThreshold := baseRadius * 3 // Maksimal 3x radius dasar
-	if threshold > maxThreshold {
-		log.Printf("⚠️ [GPS] Threshold capped from %.1fm to %.1fm", threshold, maxThreshold)
-		threshold = maxThreshold
+// AdaptiveThreshold calculates dynamic threshold based on GPS accuracy
+// Semakin buruk GPS accuracy, semakin besar threshold yang diberikan
+func AdaptiveThreshold(baseRadius float64, gpsAccuracy float64) float64 {
+	return AdaptiveThresholdWithPolicy(DefaultAttendancePolicy(), baseRadius, gpsAccuracy)
+}
+
+// AdaptiveThresholdWithPolicy calculates the threshold with the tenant accuracy multipliers,
+// capped at MaxThresholdFactor times the base radius
+func AdaptiveThresholdWithPolicy(policy models.AttendancePolicy, baseRadius float64, gpsAccuracy float64) float64 {
+	threshold, uncapped := adaptiveThreshold(policy, baseRadius, gpsAccuracy)
+	if gpsAccuracy == 0 {
+		return threshold
+	}
+
+	if gpsAccuracy < 25 {
+		log.Printf("📍 [GPS] %s accuracy (%.1fm) - threshold: %.1fm", GetGPSQuality(gpsAccuracy), gpsAccuracy, uncapped)
+	} else {
+		log.Printf("⚠️ [GPS] %s accuracy (%.1fm) - threshold: %.1fm", GetGPSQuality(gpsAccuracy), gpsAccuracy, uncapped)
+	}
+	if threshold < uncapped {
+		log.Printf("⚠️ [GPS] Threshold capped from %.1fm to %.1fm", uncapped, threshold)
 	}
 
 	return threshold
 }
 
+// adaptiveThreshold adalah perhitungan AdaptiveThresholdWithPolicy tanpa logging,
+// dipakai juga saat me-replay ribuan attendance di simulasi geofence
+func adaptiveThreshold(policy models.AttendancePolicy, baseRadius float64, gpsAccuracy float64) (threshold, uncapped float64) {
+	// Jika GPS accuracy buruk, tambahkan tolerance
+	// Formula: threshold = baseRadius + AccuracyBuffer(gpsAccuracy)
+	uncapped = baseRadius + AccuracyBufferWithPolicy(policy, gpsAccuracy)
+	if gpsAccuracy == 0 {
+		return uncapped, uncapped
+	}
+
+	// Cap maximum threshold untuk keamanan
+	maxThreshold := baseRadius * policy.MaxThresholdFactor // Default maksimal 3x radius dasar
+	return math.Min(uncapped, maxThreshold), uncapped
+}
+
 // IsWithinOfficeRange mengecek apakah koordinat berada dalam jangkauan kantor
 // DEPRECATED: Use IsWithinOfficeRangeAdaptive for better accuracy
 func IsWithinOfficeRange(userLat, userLon float64) (bool, float64) {
@@ -112,25 +141,194 @@ func IsWithinOfficeRange(userLat, userLon float64) (bool, float64) {
 }
 
 // IsWithinOfficeRangeAdaptive - Enhanced version dengan adaptive threshold
+// Hanya mengecek kantor default dari env var, gunakan FindNearestOffice untuk multi-office
 func IsWithinOfficeRangeAdaptive(userLat, userLon, gpsAccuracy float64) (bool, float64, float64) {
+	match := FindNearestOffice([]models.OfficeLocation{DefaultOfficeLocation()}, userLat, userLon, gpsAccuracy)
+	return match.InRange, match.Distance, match.AdaptiveRadius
+}
+
+// DefaultOfficeLocation mengembalikan kantor dari env var sebagai fallback
+// untuk tenant yang belum mendaftarkan office_locations
+func DefaultOfficeLocation() models.OfficeLocation {
 	cfg := config.Load()
+	return models.OfficeLocation{
+		Name:      DefaultOfficeName,
+		Latitude:  cfg.OfficeLatitude,
+		Longitude: cfg.OfficeLongitude,
+		Radius:    int(cfg.AttendanceRadiusMeters),
+		IsActive:  true,
+	}
+}
 
-	// Jika location check dimatikan, selalu return true
-	if !cfg.EnableLocationCheck {
-		return true, 0, cfg.AttendanceRadiusMeters
+// OfficeMatch adalah hasil resolve koordinat user terhadap daftar kantor
+type OfficeMatch struct {
+	Office   *models.OfficeLocation
+	InRange  bool
+	Distance float64 // Jarak ke titik pusat kantor
+
+	// AdaptiveRadius adalah radius setelah toleransi GPS. Untuk polygon, nilainya
+	// radius ekuivalen (lingkaran dengan luas sama) ditambah buffer GPS.
+	AdaptiveRadius float64
+
+	// DistanceToEdge adalah jarak ke batas geofence: positif = di luar, negatif = di dalam
+	DistanceToEdge float64
+
+	// margin adalah jarak ke batas geofence setelah buffer GPS (positif = di luar jangkauan)
+	margin float64
+}
+
+// IsPolygon menandakan kantor ter-resolve memakai geofence polygon
+func (m OfficeMatch) IsPolygon() bool {
+	return m.Office != nil && m.Office.Boundary != nil
+}
+
+// Overage mengembalikan seberapa jauh (meter) user berada di luar jangkauan adaptive
+func (m OfficeMatch) Overage() float64 {
+	return m.margin
+}
+
+// OveragePercentage mengembalikan overage relatif terhadap adaptive radius dalam persen
+func (m OfficeMatch) OveragePercentage() float64 {
+	if m.AdaptiveRadius <= 0 {
+		return math.Inf(1)
 	}
+	return (m.margin / m.AdaptiveRadius) * 100
+}
 
-	distance := CalculateDistance(userLat, userLon, cfg.OfficeLatitude, cfg.OfficeLongitude)
-	
-	// Hitung adaptive threshold berdasarkan GPS accuracy
-	adaptiveRadius := AdaptiveThreshold(cfg.AttendanceRadiusMeters, gpsAccuracy)
-	
-	inRange := distance <= adaptiveRadius
+// OfficeID mengembalikan ID kantor untuk disimpan di attendance.
+// Kantor default dari env var tidak punya row sehingga dikembalikan nil.
+func (m OfficeMatch) OfficeID() *uuid.UUID {
+	if m.Office == nil || m.Office.ID == uuid.Nil {
+		return nil
+	}
+	id := m.Office.ID
+	return &id
+}
+
+// OfficeName mengembalikan nama kantor yang ter-resolve
+func (m OfficeMatch) OfficeName() string {
+	if m.Office == nil {
+		return DefaultOfficeName
+	}
+	return m.Office.Name
+}
+
+// BaseRadius mengembalikan radius dasar kantor yang ter-resolve dalam meter
+// (radius ekuivalen untuk geofence polygon)
+func (m OfficeMatch) BaseRadius() float64 {
+	if m.Office == nil {
+		return 0
+	}
+	if m.Office.Boundary != nil {
+		return PolygonEquivalentRadius(m.Office.Boundary)
+	}
+	return float64(m.Office.Radius)
+}
 
-	log.Printf("📍 [Location] Distance: %.1fm | Base Radius: %.1fm | Adaptive Radius: %.1fm | In Range: %v",
-		distance, cfg.AttendanceRadiusMeters, adaptiveRadius, inRange)
+// GeofenceType mengembalikan "polygon" atau "circle"
+func (m OfficeMatch) GeofenceType() string {
+	if m.IsPolygon() {
+		return "polygon"
+	}
+	return "circle"
+}
 
-	return inRange, distance, adaptiveRadius
+// FindNearestOffice mencari kantor aktif terdekat untuk koordinat user.
+// Kantor yang jangkauannya mencakup user selalu diprioritaskan (yang terdekat),
+// jika tidak ada maka dipilih kantor dengan selisih jarak ke batas jangkauan terkecil.
+func FindNearestOffice(offices []models.OfficeLocation, userLat, userLon, gpsAccuracy float64) OfficeMatch {
+	return FindNearestOfficeWithPolicy(DefaultAttendancePolicy(), offices, userLat, userLon, gpsAccuracy)
+}
+
+// FindNearestOfficeWithPolicy mencari kantor terdekat memakai attendance policy tenant
+func FindNearestOfficeWithPolicy(policy models.AttendancePolicy, offices []models.OfficeLocation, userLat, userLon, gpsAccuracy float64) OfficeMatch {
+	best := nearestOffice(policy, offices, userLat, userLon, gpsAccuracy)
+
+	if best.Office != nil {
+		if gpsAccuracy > 0 {
+			log.Printf("📍 [GPS] %s accuracy (%.1fm) - threshold: %.1fm", GetGPSQuality(gpsAccuracy), gpsAccuracy, best.AdaptiveRadius)
+		}
+		log.Printf("📍 [Location] Office: %s | Distance: %.1fm | Distance to edge: %.1fm | Adaptive Radius: %.1fm | In Range: %v",
+			best.Office.Name, best.Distance, best.DistanceToEdge, best.AdaptiveRadius, best.InRange)
+	}
+
+	return best
+}
+
+// nearestOffice adalah FindNearestOfficeWithPolicy tanpa logging
+func nearestOffice(policy models.AttendancePolicy, offices []models.OfficeLocation, userLat, userLon, gpsAccuracy float64) OfficeMatch {
+	var best OfficeMatch
+
+	for i := range offices {
+		office := &offices[i]
+		if !office.IsActive {
+			continue
+		}
+
+		match := evaluateOffice(policy, office, userLat, userLon, gpsAccuracy)
+
+		better := false
+		switch {
+		case best.Office == nil:
+			better = true
+		case match.InRange && !best.InRange:
+			better = true
+		case match.InRange && best.InRange:
+			better = match.Distance < best.Distance
+		case !match.InRange && !best.InRange:
+			better = match.margin < best.margin
+		}
+
+		if better {
+			best = match
+		}
+	}
+
+	// Jika location check dimatikan, selalu in range (kantor terdekat tetap dicatat)
+	if !policy.LocationCheckEnabled {
+		best.InRange = true
+	}
+
+	return best
+}
+
+// evaluateOffice menghitung posisi user terhadap satu geofence (lingkaran atau polygon)
+func evaluateOffice(policy models.AttendancePolicy, office *models.OfficeLocation, userLat, userLon, gpsAccuracy float64) OfficeMatch {
+	distance := CalculateDistance(userLat, userLon, office.Latitude, office.Longitude)
+
+	if office.Boundary != nil {
+		inside, edgeDistance := PolygonDistanceToEdge(office.Boundary, userLat, userLon)
+		if inside {
+			edgeDistance = -edgeDistance
+		}
+
+		// Buffer GPS mengikuti aturan AdaptiveThreshold: total jangkauan maksimal MaxThresholdFactor x ukuran dasar
+		equivalentRadius := PolygonEquivalentRadius(office.Boundary)
+		buffer := math.Min(AccuracyBufferWithPolicy(policy, gpsAccuracy), equivalentRadius*(policy.MaxThresholdFactor-1))
+		margin := edgeDistance - buffer
+
+		return OfficeMatch{
+			Office:         office,
+			InRange:        margin <= 0,
+			Distance:       distance,
+			AdaptiveRadius: equivalentRadius + buffer,
+			DistanceToEdge: edgeDistance,
+			margin:         margin,
+		}
+	}
+
+	baseRadius := float64(office.Radius)
+	adaptiveRadius, _ := adaptiveThreshold(policy, baseRadius, gpsAccuracy)
+	margin := distance - adaptiveRadius
+
+	return OfficeMatch{
+		Office:         office,
+		InRange:        margin <= 0,
+		Distance:       distance,
+		AdaptiveRadius: adaptiveRadius,
+		DistanceToEdge: distance - baseRadius,
+		margin:         margin,
+	}
 }
 
 // LocationValidationResponse adalah response untuk validasi lokasi
@@ -145,6 +343,17 @@ type LocationValidationResponse struct {
 	GPSAccuracy     float64 `json:"gps_accuracy,omitempty"`
 	GPSQuality      string  `json:"gps_quality,omitempty"`
 	Recommendation  string  `json:"recommendation,omitempty"`
+
+	OfficeID   *uuid.UUID `json:"office_id,omitempty"`
+	OfficeName string     `json:"office_name,omitempty"`
+
+	// GeofenceType adalah "circle" atau "polygon"
+	GeofenceType string `json:"geofence_type,omitempty"`
+	// DistanceToEdge adalah jarak ke batas geofence: positif = di luar, negatif = di dalam
+	DistanceToEdge float64 `json:"distance_to_edge"`
+
+	// FusedPosition adalah posisi gabungan beserta confidence-nya jika request mengirim beberapa reading GPS
+	FusedPosition *GPSFusion `json:"fused_position,omitempty"`
 }
 
 // GetGPSQuality returns a human-readable GPS quality assessment
@@ -203,40 +412,71 @@ func ValidateLocation(userLat, userLon float64) LocationValidationResponse {
 }
 
 // ValidateLocationAdaptive - Enhanced validation dengan GPS accuracy consideration
+// Hanya mengecek kantor default dari env var, gunakan ValidateLocationForOffices untuk multi-office
 func ValidateLocationAdaptive(userLat, userLon, gpsAccuracy float64) LocationValidationResponse {
-	cfg := config.Load()
+	return ValidateLocationForOffices([]models.OfficeLocation{DefaultOfficeLocation()}, userLat, userLon, gpsAccuracy)
+}
+
+// ValidateLocationForOffices memvalidasi lokasi user terhadap kantor aktif terdekat
+func ValidateLocationForOffices(offices []models.OfficeLocation, userLat, userLon, gpsAccuracy float64) LocationValidationResponse {
+	return ValidateLocationWithPolicy(DefaultAttendancePolicy(), offices, userLat, userLon, gpsAccuracy)
+}
+
+// ValidateLocationWithPolicy memvalidasi lokasi user terhadap kantor aktif terdekat memakai
+// attendance policy tenant (toleransi GPS dan margin auto-approve)
+func ValidateLocationWithPolicy(policy models.AttendancePolicy, offices []models.OfficeLocation, userLat, userLon, gpsAccuracy float64) LocationValidationResponse {
+	// Gunakan adaptive range checking terhadap kantor terdekat
+	match := FindNearestOfficeWithPolicy(policy, offices, userLat, userLon, gpsAccuracy)
 
 	// Jika location check dimatikan
-	if !cfg.EnableLocationCheck {
+	if !policy.LocationCheckEnabled {
 		return LocationValidationResponse{
-			InRange:   true,
-			Message:   "Location check is disabled",
-			NeedForce: false,
-			Distance:  0,
-			MaxRadius: cfg.AttendanceRadiusMeters,
+			InRange:    true,
+			Message:    "Location check is disabled",
+			NeedForce:  false,
+			Distance:   0,
+			MaxRadius:  match.BaseRadius(),
+			OfficeID:   match.OfficeID(),
+			OfficeName: match.OfficeName(),
+		}
+	}
+
+	if match.Office == nil {
+		return LocationValidationResponse{
+			InRange:   false,
+			Message:   "Belum ada lokasi kantor aktif yang terdaftar",
+			NeedForce: true,
 		}
 	}
 
-	// Gunakan adaptive range checking
-	inRange, distance, adaptiveRadius := IsWithinOfficeRangeAdaptive(userLat, userLon, gpsAccuracy)
+	inRange, distance, adaptiveRadius := match.InRange, match.Distance, match.AdaptiveRadius
+	baseRadius := match.BaseRadius()
 	gpsQuality := GetGPSQuality(gpsAccuracy)
 
 	response := LocationValidationResponse{
 		InRange:        inRange,
 		Distance:       distance,
-		MaxRadius:      cfg.AttendanceRadiusMeters,
+		MaxRadius:      baseRadius,
 		AdaptiveRadius: adaptiveRadius,
 		GPSAccuracy:    gpsAccuracy,
 		GPSQuality:     gpsQuality,
+		OfficeID:       match.OfficeID(),
+		OfficeName:     match.OfficeName(),
+		GeofenceType:   match.GeofenceType(),
+		DistanceToEdge: match.DistanceToEdge,
 	}
 
 	// Generate detailed messages
+	// Untuk polygon, jarak ke titik pusat tidak bermakna sehingga dipakai jarak ke batas area
 	distanceStr := FormatDistance(distance)
-	baseRadiusStr := FormatDistance(cfg.AttendanceRadiusMeters)
+	if match.IsPolygon() {
+		distanceStr = FormatDistance(math.Abs(match.DistanceToEdge)) + " dari batas area"
+	}
+	baseRadiusStr := FormatDistance(baseRadius)
 	adaptiveRadiusStr := FormatDistance(adaptiveRadius)
 
 	if inRange {
-		response.Message = "Lokasi valid, dalam jangkauan kantor"
+		response.Message = fmt.Sprintf("Lokasi valid, dalam jangkauan %s", match.OfficeName())
 		response.DetailedMessage = fmt.Sprintf("Jarak: %s | Jangkauan: %s | Akurasi GPS: %s (%.0fm)",
 			distanceStr, adaptiveRadiusStr, gpsQuality, gpsAccuracy)
 		response.NeedForce = false
@@ -246,19 +486,22 @@ func ValidateLocationAdaptive(userLat, userLon, gpsAccuracy float64) LocationVal
 			response.Recommendation = "Untuk akurasi lebih baik, coba pindah ke area dengan sinyal GPS lebih kuat (dekat jendela atau outdoor)"
 		}
 	} else {
-		response.Message = fmt.Sprintf("Anda berada %s dari lokasi kantor", distanceStr)
-		
+		response.Message = fmt.Sprintf("Anda berada %s dari %s", distanceStr, match.OfficeName())
+		if match.IsPolygon() {
+			response.Message = fmt.Sprintf("Anda berada %s di luar batas area %s", FormatDistance(match.DistanceToEdge), match.OfficeName())
+		}
+
 		// Detailed explanation
-		marginStr := FormatDistance(distance - adaptiveRadius)
+		marginStr := FormatDistance(match.Overage())
 		response.DetailedMessage = fmt.Sprintf(
 			"Jarak: %s | Jangkauan base: %s | Jangkauan adaptive: %s | Margin: %s | Akurasi GPS: %s (%.0fm)",
 			distanceStr, baseRadiusStr, adaptiveRadiusStr, marginStr, gpsQuality, gpsAccuracy,
 		)
 
 		// Check if just barely out of range
-		overagePercentage := ((distance - adaptiveRadius) / adaptiveRadius) * 100
-		
-		if overagePercentage < 10 {
+		overagePercentage := match.OveragePercentage()
+
+		if WithinAutoApproveMargin(policy, match) {
 			// Very close - likely GPS error
 			response.NeedForce = false // Auto-approve
 			response.Message += " - Kemungkinan error GPS signal, attendance di-approve otomatis"
@@ -277,6 +520,26 @@ func ValidateLocationAdaptive(userLat, userLon, gpsAccuracy float64) LocationVal
 	return response
 }
 
+// WithinAutoApproveMargin melaporkan apakah user di luar jangkauan dengan overage di bawah
+// AutoApproveMarginPercent policy tenant (kemungkinan error GPS), sehingga clock in/out
+// di-approve otomatis tanpa force
+func WithinAutoApproveMargin(policy models.AttendancePolicy, match OfficeMatch) bool {
+	return match.Office != nil && !match.InRange && match.OveragePercentage() < policy.AutoApproveMarginPercent
+}
+
+// GeofenceOutcome mengklasifikasikan hasil geofence GPS clock in/out: in range, auto-approve
+// (overage di bawah margin) atau butuh force. Dipakai clock in/out dan geofence simulation.
+func GeofenceOutcome(policy models.AttendancePolicy, match OfficeMatch) string {
+	switch {
+	case match.InRange:
+		return models.GeofenceOutcomeApproved
+	case WithinAutoApproveMargin(policy, match):
+		return models.GeofenceOutcomeAutoApproved
+	default:
+		return models.GeofenceOutcomeNeedsForce
+	}
+}
+
 // FormatDistance memformat jarak menjadi string yang mudah dibaca
 func FormatDistance(meters float64) string {
 	if meters < 1 {
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestFindNearestOffice(t *testing.T) {
+	headOffice := models.OfficeLocation{
+		ID: uuid.New(), Name: "Head Office", Latitude: -6.305881, Longitude: 106.678055, Radius: 100, IsActive: true,
+	}
+	branch := models.OfficeLocation{
+		ID: uuid.New(), Name: "Branch", Latitude: -6.2088, Longitude: 106.8456, Radius: 200, IsActive: true,
+	}
+	offices := []models.OfficeLocation{headOffice, branch}
+
+	// Inside branch radius
+	match := FindNearestOffice(offices, -6.2089, 106.8457, 0)
+	if !match.InRange {
+		t.Fatalf("Expected in range, distance %.1fm", match.Distance)
+	}
+	if match.OfficeName() != "Branch" {
+		t.Errorf("Expected Branch, got %s", match.OfficeName())
+	}
+	if id := match.OfficeID(); id == nil || *id != branch.ID {
+		t.Errorf("Expected office ID %s, got %v", branch.ID, id)
+	}
+
+	// Out of every range - still resolves the closest office
+	match = FindNearestOffice(offices, -6.3000, 106.6800, 0)
+	if match.InRange {
+		t.Errorf("Expected out of range, distance %.1fm", match.Distance)
+	}
+	if match.OfficeName() != "Head Office" {
+		t.Errorf("Expected Head Office, got %s", match.OfficeName())
+	}
+}
+
+func TestFindNearestOffice_PrefersInRangeOffice(t *testing.T) {
+	// Small office is closer, but only the large campus actually covers the user
+	small := models.OfficeLocation{ID: uuid.New(), Name: "Kiosk", Latitude: -6.2000, Longitude: 106.8000, Radius: 10, IsActive: true}
+	campus := models.OfficeLocation{ID: uuid.New(), Name: "Campus", Latitude: -6.2050, Longitude: 106.8000, Radius: 1000, IsActive: true}
+
+	match := FindNearestOffice([]models.OfficeLocation{small, campus}, -6.2010, 106.8000, 0)
+	if !match.InRange || match.OfficeName() != "Campus" {
+		t.Errorf("Expected in range of Campus, got %s (in range: %v)", match.OfficeName(), match.InRange)
+	}
+}
+
+func TestFindNearestOffice_SkipsInactive(t *testing.T) {
+	inactive := models.OfficeLocation{ID: uuid.New(), Name: "Closed", Latitude: -6.2088, Longitude: 106.8456, Radius: 100, IsActive: false}
+
+	match := FindNearestOffice([]models.OfficeLocation{inactive}, -6.2088, 106.8456, 0)
+	if match.Office != nil {
+		t.Errorf("Expected no office, got %s", match.OfficeName())
+	}
+	if match.OfficeID() != nil {
+		t.Error("Expected nil office ID")
+	}
+}
+
+func TestDefaultOfficeLocation_HasNoID(t *testing.T) {
+	match := FindNearestOffice([]models.OfficeLocation{DefaultOfficeLocation()}, -6.305881, 106.678055, 0)
+	if match.OfficeID() != nil {
+		t.Error("Default office must not be stored as office_location_id")
+	}
+	if match.OfficeName() != DefaultOfficeName {
+		t.Errorf("Expected %s, got %s", DefaultOfficeName, match.OfficeName())
+	}
+}
+
+func TestWithinAutoApproveMargin(t *testing.T) {
+	policy := models.AttendancePolicy{LocationCheckEnabled: true, AutoApproveMarginPercent: 10}
+	office := models.OfficeLocation{ID: uuid.New(), Name: "Head Office", Latitude: -6.2000, Longitude: 106.8000, Radius: 100, IsActive: true}
+	offices := []models.OfficeLocation{office}
+
+	// ~105m from a 100m geofence: 5% over, likely GPS error
+	match := FindNearestOfficeWithPolicy(policy, offices, -6.200944, 106.8000, 0)
+	if match.InRange || !WithinAutoApproveMargin(policy, match) {
+		t.Errorf("Expected auto-approve at %.1fm (overage %.1f%%)", match.Distance, match.OveragePercentage())
+	}
+
+	// ~150m: 50% over needs force
+	match = FindNearestOfficeWithPolicy(policy, offices, -6.201349, 106.8000, 0)
+	if WithinAutoApproveMargin(policy, match) {
+		t.Errorf("Expected force at %.1fm (overage %.1f%%)", match.Distance, match.OveragePercentage())
+	}
+
+	// In range is already approved, not auto-approved
+	match = FindNearestOfficeWithPolicy(policy, offices, -6.2000, 106.8000, 0)
+	if WithinAutoApproveMargin(policy, match) {
+		t.Error("Expected in range match not to be reported as auto-approved")
+	}
+
+	// A zero margin disables auto-approve
+	noMargin := models.AttendancePolicy{LocationCheckEnabled: true}
+	match = FindNearestOfficeWithPolicy(noMargin, offices, -6.200944, 106.8000, 0)
+	if WithinAutoApproveMargin(noMargin, match) {
+		t.Error("Expected no auto-approve with a zero margin")
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MaxClockDrift is the device clock difference from which offline clock events are flagged
+	MaxClockDrift = 5 * time.Minute
+	// MaxOfflineEventAge is how long an offline clock event may wait on the device for sync
+	MaxOfflineEventAge = 72 * time.Hour
+	// offlineFutureTolerance absorbs network latency between device_time and the server clock
+	offlineFutureTolerance = time.Minute
+)
+
+var (
+	ErrOfflineEventInFuture = errors.New("clock event time is in the future")
+	ErrOfflineEventTooOld   = errors.New("clock event is older than 72 hours and can no longer be synced")
+)
+
+// ClockDrift returns how far the device clock is ahead of the server clock (negative = behind),
+// measured from the device time sent with a sync batch
+func ClockDrift(deviceNow, serverNow time.Time) time.Duration {
+	return deviceNow.Sub(serverNow)
+}
+
+// ClockDriftExceeded reports whether the drift is larger than MaxClockDrift in either direction
+func ClockDriftExceeded(drift time.Duration) bool {
+	return drift > MaxClockDrift || drift < -MaxClockDrift
+}
+
+// OfflineEventTime converts the device timestamp of an offline clock event to server time by
+// removing the clock drift measured at sync. Events after serverNow or older than
+// MaxOfflineEventAge are rejected. The result is in the location of serverNow, like the
+// timestamps of online clock events.
+func OfflineEventTime(recordedAt time.Time, drift time.Duration, serverNow time.Time) (time.Time, error) {
+	at := recordedAt.Add(-drift).In(serverNow.Location())
+	if at.After(serverNow.Add(offlineFutureTolerance)) {
+		return time.Time{}, ErrOfflineEventInFuture
+	}
+	if serverNow.Sub(at) > MaxOfflineEventAge {
+		return time.Time{}, ErrOfflineEventTooOld
+	}
+	if at.After(serverNow) {
+		at = serverNow
+	}
+	return at, nil
+}
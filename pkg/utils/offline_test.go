@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOfflineEventTime(t *testing.T) {
+	server := time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	// Device clock 10 minutes ahead, event recorded at 08:10 device time = 08:00 server time
+	drift := ClockDrift(server.Add(10*time.Minute), server)
+	at, err := OfflineEventTime(time.Date(2026, 1, 6, 8, 10, 0, 0, time.UTC), drift, server)
+	if err != nil || !at.Equal(time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected drift-corrected 08:00, got %v (%v)", at, err)
+	}
+	// Device timestamps carry the device offset, the event time uses the server location
+	wib := time.FixedZone("WIB", 7*60*60)
+	if at, err := OfflineEventTime(time.Date(2026, 1, 6, 15, 10, 0, 0, wib), drift, server); err != nil || at.Location() != time.UTC || at.Hour() != 8 {
+		t.Errorf("expected 08:00 UTC, got %v (%v)", at, err)
+	}
+	if !ClockDriftExceeded(drift) {
+		t.Errorf("expected 10 minute drift to be flagged")
+	}
+	if ClockDriftExceeded(ClockDrift(server.Add(-30*time.Second), server)) {
+		t.Errorf("expected 30 second drift to be tolerated")
+	}
+
+	if _, err := OfflineEventTime(server.Add(time.Hour), 0, server); err != ErrOfflineEventInFuture {
+		t.Errorf("expected future event to be rejected, got %v", err)
+	}
+	if _, err := OfflineEventTime(server.Add(-73*time.Hour), 0, server); err != ErrOfflineEventTooOld {
+		t.Errorf("expected stale event to be rejected, got %v", err)
+	}
+}
+
+func TestAssessClockDrift(t *testing.T) {
+	if a := AssessClockDrift(2 * time.Minute); a.Suspicious || len(a.Flags) != 0 {
+		t.Errorf("expected small drift to be clean, got %+v", a)
+	}
+	if a := AssessClockDrift(-20 * time.Minute); !a.Suspicious || a.Flags[0] != FraudFlagClockDrift {
+		t.Errorf("expected large drift to be suspicious, got %+v", a)
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// MaxOvertimeHours membatasi panjang satu pengajuan lembur
+const MaxOvertimeHours = 12
+
+var (
+	ErrInvalidOvertimeWindow = errors.New("end_time must be after start_time and at most 12 hours later")
+	ErrOvertimeNotAhead      = errors.New("overtime must be requested before it starts")
+)
+
+// ValidateOvertimeWindow checks that overtime is requested ahead of time with a sane window
+func ValidateOvertimeWindow(start, end, now time.Time) error {
+	if !end.After(start) || end.Sub(start) > MaxOvertimeHours*time.Hour {
+		return ErrInvalidOvertimeWindow
+	}
+	if start.Before(now) {
+		return ErrOvertimeNotAhead
+	}
+	return nil
+}
+
+// OvertimeDayType menentukan jenis hari lembur: hari libur, akhir pekan (bukan hari kerja)
+// atau hari kerja biasa
+func OvertimeDayType(calendar WorkCalendar, date time.Time) string {
+	if calendar.IsHoliday(date) {
+		return models.OvertimeDayHoliday
+	}
+	if !IsWorkingDay(calendar.WorkingDays, date.Weekday()) {
+		return models.OvertimeDayWeekend
+	}
+	return models.OvertimeDayWeekday
+}
+
+// OvertimeMultiplier returns the tenant multiplier of a day type
+func OvertimeMultiplier(policy models.OvertimePolicy, dayType string) float64 {
+	switch dayType {
+	case models.OvertimeDayHoliday:
+		return policy.HolidayMultiplier
+	case models.OvertimeDayWeekend:
+		return policy.WeekendMultiplier
+	default:
+		return policy.WeekdayMultiplier
+	}
+}
+
+// OvertimeWorkedMinutes counts the whole minutes of the approved window covered by closed
+// attendance sessions. Time clocked outside the window is not approved overtime.
+func OvertimeWorkedMinutes(start, end time.Time, sessions []models.AttendanceSession) float64 {
+	var worked time.Duration
+	for _, session := range sessions {
+		if session.CheckOutTime == nil {
+			continue
+		}
+		from, to := session.CheckInTime, *session.CheckOutTime
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			worked += to.Sub(from)
+		}
+	}
+	return wholeMinutes(worked)
+}
+
+// CompensableHours converts overtime minutes into paid hours (2 decimals)
+func CompensableHours(minutes, multiplier float64) float64 {
+	return math.Round(minutes/60*multiplier*100) / 100
+}
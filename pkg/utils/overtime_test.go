@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestValidateOvertimeWindow(t *testing.T) {
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	start := now.Add(5 * time.Hour)
+
+	if err := ValidateOvertimeWindow(start, start.Add(3*time.Hour), now); err != nil {
+		t.Errorf("expected valid window, got %v", err)
+	}
+	if err := ValidateOvertimeWindow(start, start, now); err != ErrInvalidOvertimeWindow {
+		t.Errorf("expected empty window to be rejected, got %v", err)
+	}
+	if err := ValidateOvertimeWindow(start, start.Add(13*time.Hour), now); err != ErrInvalidOvertimeWindow {
+		t.Errorf("expected window over 12 hours to be rejected, got %v", err)
+	}
+	if err := ValidateOvertimeWindow(now.Add(-time.Minute), start, now); err != ErrOvertimeNotAhead {
+		t.Errorf("expected overtime that already started to be rejected, got %v", err)
+	}
+}
+
+func TestOvertimeDayType(t *testing.T) {
+	calendar := NewWorkCalendar(0, []string{"2026-01-01"})
+	cases := map[string]string{
+		"2026-01-01": models.OvertimeDayHoliday, // Thursday, New Year
+		"2026-01-03": models.OvertimeDayWeekend, // Saturday
+		"2026-01-05": models.OvertimeDayWeekday, // Monday
+	}
+	for date, want := range cases {
+		d, _ := time.Parse("2006-01-02", date)
+		if got := OvertimeDayType(calendar, d); got != want {
+			t.Errorf("%s: expected %s, got %s", date, want, got)
+		}
+	}
+}
+
+func TestOvertimeWorkedMinutes(t *testing.T) {
+	start := time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+
+	clockOut := start.Add(90*time.Minute + 30*time.Second)
+	sessions := []models.AttendanceSession{
+		{CheckInTime: start.Add(-9 * time.Hour), CheckOutTime: &clockOut},
+		{CheckInTime: end.Add(-time.Hour)}, // still open, not counted yet
+	}
+	if got := OvertimeWorkedMinutes(start, end, sessions); got != 90 {
+		t.Errorf("expected 90 minutes inside the window, got %v", got)
+	}
+
+	lateOut := end.Add(2 * time.Hour)
+	sessions = []models.AttendanceSession{{CheckInTime: start.Add(-9 * time.Hour), CheckOutTime: &lateOut}}
+	if got := OvertimeWorkedMinutes(start, end, sessions); got != 180 {
+		t.Errorf("expected overtime capped at the approved window, got %v", got)
+	}
+}
+
+func TestCompensableHours(t *testing.T) {
+	policy := models.OvertimePolicy{WeekdayMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 3}
+	if got := CompensableHours(90, OvertimeMultiplier(policy, models.OvertimeDayWeekday)); got != 2.25 {
+		t.Errorf("expected 2.25 hours, got %v", got)
+	}
+	if got := CompensableHours(100, OvertimeMultiplier(policy, models.OvertimeDayHoliday)); got != 5 {
+		t.Errorf("expected 5 hours, got %v", got)
+	}
+}
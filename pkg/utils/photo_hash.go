@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"math/bits"
+	"strings"
+	"time"
+
+	// Decoder selfie yang diterima DecodeBase64Image
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// DuplicatePhotoDistance adalah Hamming distance maksimal antara dua perceptual hash yang
+// dianggap foto yang sama (termasuk yang di-resize, dikompres ulang atau sedikit di-crop)
+const DuplicatePhotoDistance = 6
+
+var ErrUnreadableImage = errors.New("image could not be decoded for hashing")
+
+// PerceptualHash menghitung difference hash (dHash) 64-bit: gambar diperkecil menjadi 9x8
+// grayscale, lalu tiap bit menyatakan apakah piksel lebih terang dari tetangga kanannya.
+// Foto yang sama setelah resize atau kompresi ulang menghasilkan hash yang (hampir) sama.
+func PerceptualHash(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, ErrUnreadableImage
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < 9 || bounds.Dy() < 8 {
+		return 0, ErrUnreadableImage
+	}
+
+	var gray [8][9]float64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 9; x++ {
+			gray[y][x] = cellLuminance(img, bounds, x, y)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// cellLuminance adalah rata-rata luminance satu sel grid 9x8. Foto kamera bisa 12MP,
+// sehingga tiap sel cukup diwakili maksimal 8x8 titik sampel.
+func cellLuminance(img image.Image, bounds image.Rectangle, cellX, cellY int) float64 {
+	x0 := bounds.Min.X + cellX*bounds.Dx()/9
+	x1 := bounds.Min.X + (cellX+1)*bounds.Dx()/9
+	y0 := bounds.Min.Y + cellY*bounds.Dy()/8
+	y1 := bounds.Min.Y + (cellY+1)*bounds.Dy()/8
+
+	stepX := max(1, (x1-x0)/8)
+	stepY := max(1, (y1-y0)/8)
+
+	var sum float64
+	var n int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// HammingDistance menghitung jumlah bit yang berbeda antara dua perceptual hash
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// EXIF tags yang dibaca PhotoCaptureTime
+const (
+	exifTagDateTime           = 0x0132
+	exifTagExifIFD            = 0x8769
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+)
+
+// PhotoCaptureTime membaca waktu pengambilan foto dari EXIF JPEG (DateTimeOriginal, atau
+// DateTime jika tidak ada). EXIF tanpa OffsetTimeOriginal diartikan dalam timezone loc.
+// Mengembalikan false untuk PNG, foto tanpa EXIF atau EXIF yang rusak.
+func PhotoCaptureTime(data []byte, loc *time.Location) (time.Time, bool) {
+	tiff := jpegExifSegment(data)
+	if tiff == nil {
+		return time.Time{}, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:8]))
+	value, offset := ifd0[exifTagDateTime], ""
+	if pointer, ok := ifd0[exifTagExifIFD]; ok && len(pointer) == 4 {
+		exif := readIFD(tiff, order, order.Uint32(pointer))
+		if original, ok := exif[exifTagDateTimeOriginal]; ok {
+			value = original
+		}
+		offset = exifString(exif[exifTagOffsetTimeOriginal])
+	}
+
+	raw := exifString(value)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if offset != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", raw+offset); err == nil {
+			return t, true
+		}
+	}
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", raw, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// jpegExifSegment mengembalikan isi TIFF dari segment APP1 Exif, atau nil
+func jpegExifSegment(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan / end of image: tidak ada metadata lagi setelah ini
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return nil
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) >= 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return segment[6:]
+		}
+		i += 2 + length
+	}
+	return nil
+}
+
+// readIFD membaca entry satu IFD menjadi map tag -> nilai mentah (ASCII dan LONG saja)
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16][]byte {
+	entries := map[uint16][]byte{}
+	if int(offset)+2 > len(tiff) {
+		return entries
+	}
+
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(tiff) {
+			break
+		}
+		entry := tiff[start : start+12]
+		tag, typ, n := order.Uint16(entry[0:2]), order.Uint16(entry[2:4]), int(order.Uint32(entry[4:8]))
+
+		switch typ {
+		case 2: // ASCII, nilai lebih dari 4 byte disimpan di offset
+			if n <= 4 {
+				entries[tag] = entry[8 : 8+n]
+				continue
+			}
+			at := int(order.Uint32(entry[8:12]))
+			if n > 64 || at+n > len(tiff) {
+				continue
+			}
+			entries[tag] = tiff[at : at+n]
+		case 4: // LONG, dipakai untuk pointer ke Exif IFD
+			entries[tag] = entry[8:12]
+		}
+	}
+	return entries
+}
+
+func exifString(value []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+}
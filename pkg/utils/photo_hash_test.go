@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// testSelfie draws a simple gradient scene; seed changes the layout like a different photo would
+func testSelfie(width, height, seed int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x*255/width + y*seed*97/height) % 256)
+			if (x*7/width+y*5/height+seed)%3 == 0 {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image, quality int) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPerceptualHash(t *testing.T) {
+	original := testSelfie(640, 480, 1)
+	hash, err := PerceptualHash(encodeJPEG(t, original, 90))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Same photo, re-encoded at lower quality and downscaled to PNG
+	small := image.NewRGBA(image.Rect(0, 0, 320, 240))
+	for y := 0; y < 240; y++ {
+		for x := 0; x < 320; x++ {
+			small.Set(x, y, original.At(x*2, y*2))
+		}
+	}
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, small); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"recompressed": encodeJPEG(t, original, 40), "resized": pngBuf.Bytes()} {
+		other, err := PerceptualHash(data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if d := HammingDistance(hash, other); d > DuplicatePhotoDistance {
+			t.Errorf("%s: expected duplicate, distance %d", name, d)
+		}
+	}
+
+	different, err := PerceptualHash(encodeJPEG(t, testSelfie(640, 480, 4), 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := HammingDistance(hash, different); d <= DuplicatePhotoDistance {
+		t.Errorf("expected a different photo, distance %d", d)
+	}
+
+	if _, err := PerceptualHash([]byte("not an image")); err != ErrUnreadableImage {
+		t.Errorf("expected ErrUnreadableImage, got %v", err)
+	}
+}
+
+// withExif inserts an APP1 Exif segment with IFD0 -> Exif IFD -> DateTimeOriginal (+ offset)
+func withExif(jpegData []byte, dateTime, offset string) []byte {
+	order := binary.LittleEndian
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+
+	entry := func(tag, typ uint16, count, value uint32) []byte {
+		b := make([]byte, 12)
+		order.PutUint16(b[0:], tag)
+		order.PutUint16(b[2:], typ)
+		order.PutUint32(b[4:], count)
+		order.PutUint32(b[8:], value)
+		return b
+	}
+
+	// IFD0 at 8: one entry pointing to the Exif IFD at 26
+	tiff = append(tiff, 1, 0)
+	tiff = append(tiff, entry(exifTagExifIFD, 4, 1, 26)...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	// Exif IFD at 26: DateTimeOriginal and OffsetTimeOriginal, strings after the IFD
+	entries := 1
+	if offset != "" {
+		entries = 2
+	}
+	dataStart := uint32(26 + 2 + entries*12 + 4)
+	tiff = append(tiff, byte(entries), 0)
+	tiff = append(tiff, entry(exifTagDateTimeOriginal, 2, 20, dataStart)...)
+	if offset != "" {
+		tiff = append(tiff, entry(exifTagOffsetTimeOriginal, 2, 7, dataStart+20)...)
+	}
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, append([]byte(dateTime), 0)...)
+	if offset != "" {
+		tiff = append(tiff, append([]byte(offset), 0)...)
+	}
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, app1...)
+	return append(out, jpegData[2:]...)
+}
+
+func TestPhotoCaptureTime(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	base := encodeJPEG(t, testSelfie(64, 48, 1), 80)
+
+	taken, ok := PhotoCaptureTime(withExif(base, "2026:01:05 07:55:10", ""), wib)
+	if !ok || !taken.Equal(time.Date(2026, 1, 5, 7, 55, 10, 0, wib)) {
+		t.Errorf("expected 07:55:10 WIB, got %v (ok: %v)", taken, ok)
+	}
+
+	taken, ok = PhotoCaptureTime(withExif(base, "2026:01:05 08:55:10", "+08:00"), wib)
+	if !ok || !taken.Equal(time.Date(2026, 1, 5, 7, 55, 10, 0, wib)) {
+		t.Errorf("expected the EXIF offset to win, got %v (ok: %v)", taken, ok)
+	}
+
+	if _, ok := PhotoCaptureTime(base, wib); ok {
+		t.Error("expected no capture time without EXIF")
+	}
+}
+
+func TestAssessSelfie(t *testing.T) {
+	now := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	fresh := now.Add(-time.Minute)
+
+	if a := AssessSelfie(SelfieSignals{UserID: userID, Time: now, CapturedAt: &fresh}); a.Suspicious || len(a.Flags) != 0 {
+		t.Errorf("expected a fresh selfie to be clean, got %+v", a)
+	}
+
+	own := AssessSelfie(SelfieSignals{UserID: userID, Time: now, Match: &models.PhotoMatch{UserID: userID}})
+	if !own.Suspicious || own.Flags[0] != FraudFlagReusedPhoto {
+		t.Errorf("expected reused photo to be suspicious, got %+v", own)
+	}
+
+	shared := AssessSelfie(SelfieSignals{UserID: userID, Time: now, Match: &models.PhotoMatch{UserID: uuid.New()}})
+	if !shared.Suspicious || shared.Flags[0] != FraudFlagSharedPhoto {
+		t.Errorf("expected shared photo to be suspicious, got %+v", shared)
+	}
+
+	repeated := AssessSelfie(SelfieSignals{UserID: userID, Time: now, SameAsCheckIn: true})
+	if !repeated.Suspicious || len(repeated.Flags) != 1 || repeated.Flags[0] != FraudFlagRepeatedPhoto {
+		t.Errorf("expected a clock out selfie repeating the clock in selfie to be suspicious, got %+v", repeated)
+	}
+
+	old := now.AddDate(0, 0, -3)
+	stale := AssessSelfie(SelfieSignals{UserID: userID, Time: now, CapturedAt: &old})
+	if !stale.Suspicious || len(stale.Flags) != 1 || stale.Flags[0] != FraudFlagStalePhoto {
+		t.Errorf("expected a gallery photo from days ago to be suspicious, got %+v", stale)
+	}
+}
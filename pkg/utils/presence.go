@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidSignalIdentifier = errors.New("invalid signal identifier: wifi needs a BSSID like aa:bb:cc:dd:ee:ff, ble needs an ID of hex digits, ':' or '-'")
+
+// NormalizeBSSID converts a Wi-Fi BSSID to lowercase colon separated form.
+// Accepts aa:bb:cc:dd:ee:ff, AA-BB-CC-DD-EE-FF and aabbccddeeff.
+func NormalizeBSSID(value string) (string, error) {
+	hex := strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.TrimSpace(value)))
+	if len(hex) != 12 || !isHex(hex) {
+		return "", ErrInvalidSignalIdentifier
+	}
+
+	parts := make([]string, 6)
+	for i := range parts {
+		parts[i] = hex[i*2 : i*2+2]
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// NormalizeBeaconID lowercases a BLE beacon identifier, e.g. an iBeacon
+// "<proximity uuid>:<major>:<minor>" or an Eddystone "<namespace>:<instance>"
+func NormalizeBeaconID(value string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(value))
+	if id == "" || len(id) > 100 {
+		return "", ErrInvalidSignalIdentifier
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && r != ':' && r != '-' {
+			return "", ErrInvalidSignalIdentifier
+		}
+	}
+	return id, nil
+}
+
+// NormalizeSignalIdentifier normalizes an identifier for the given signal type (wifi or ble)
+func NormalizeSignalIdentifier(signalType, value string) (string, error) {
+	if signalType == "wifi" {
+		return NormalizeBSSID(value)
+	}
+	return NormalizeBeaconID(value)
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestNormalizeBSSID(t *testing.T) {
+	for _, input := range []string{"A4:2B:B0:7C:11:90", "a4-2b-b0-7c-11-90", " a42bb07c1190 "} {
+		got, err := NormalizeBSSID(input)
+		if err != nil || got != "a4:2b:b0:7c:11:90" {
+			t.Errorf("NormalizeBSSID(%q) = %q, %v", input, got, err)
+		}
+	}
+
+	for _, input := range []string{"", "a4:2b:b0:7c:11", "zz:2b:b0:7c:11:90"} {
+		if _, err := NormalizeBSSID(input); err == nil {
+			t.Errorf("expected NormalizeBSSID(%q) to fail", input)
+		}
+	}
+}
+
+func TestNormalizeBeaconID(t *testing.T) {
+	got, err := NormalizeBeaconID("F7826DA6-4FA2-4E98-8024-BC5B71E0893E:100:1")
+	if err != nil || got != "f7826da6-4fa2-4e98-8024-bc5b71e0893e:100:1" {
+		t.Errorf("unexpected beacon ID %q, %v", got, err)
+	}
+	if _, err := NormalizeBeaconID("beacon; DROP TABLE"); err == nil {
+		t.Error("expected beacon ID with invalid characters to fail")
+	}
+}
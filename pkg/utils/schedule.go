@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// DefaultWorkingDays is Monday-Friday, used when a schedule has working_days = 0
+const DefaultWorkingDays = 1<<time.Monday | 1<<time.Tuesday | 1<<time.Wednesday | 1<<time.Thursday | 1<<time.Friday
+
+var ErrInvalidScheduleTime = errors.New("schedule time must use HH:MM format")
+
+// ParseClock parses an HH:MM (or HH:MM:SS) time of day into an offset from midnight
+func ParseClock(value string) (time.Duration, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, ErrInvalidScheduleTime
+}
+
+// IsWorkingDay checks the weekday against the schedule bitmask
+func IsWorkingDay(workingDays int, day time.Weekday) bool {
+	if workingDays == 0 {
+		workingDays = DefaultWorkingDays
+	}
+	return workingDays&(1<<day) != 0
+}
+
+// CountWorkingDays counts working days between start and end (inclusive) for the bitmask
+func CountWorkingDays(workingDays int, start, end time.Time) int {
+	count := 0
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	for !day.After(last) {
+		if IsWorkingDay(workingDays, day.Weekday()) {
+			count++
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return count
+}
+
+// ScheduleWindow returns the scheduled start and end on the given date, in the date's location.
+// Overnight schedules (end <= start) end on the following day.
+func ScheduleWindow(schedule *models.AttendanceSchedule, date time.Time) (time.Time, time.Time, error) {
+	startOffset, err := ParseClock(schedule.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endOffset, err := ParseClock(schedule.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	start := midnight.Add(startOffset)
+	end := midnight.Add(endOffset)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// CalculateLateMinutes returns minutes late counted from the scheduled start.
+// Clock ins within tolerance_late and clock ins on non-working days are not late.
+func CalculateLateMinutes(schedule *models.AttendanceSchedule, checkIn time.Time) float64 {
+	if schedule == nil || !IsWorkingDay(schedule.WorkingDays, checkIn.Weekday()) {
+		return 0
+	}
+	return lateMinutesOn(schedule, checkIn, checkIn)
+}
+
+// lateMinutesOn counts minutes late against the schedule window starting on shiftDate
+func lateMinutesOn(schedule *models.AttendanceSchedule, shiftDate, checkIn time.Time) float64 {
+	start, _, err := ScheduleWindow(schedule, shiftDate)
+	if err != nil {
+		return 0
+	}
+
+	tolerance := time.Duration(schedule.ToleranceLate) * time.Minute
+	if !checkIn.After(start.Add(tolerance)) {
+		return 0
+	}
+	return wholeMinutes(checkIn.Sub(start))
+}
+
+// CalculateAttendanceTimeStats computes late, early leave and overtime minutes for a
+// completed attendance. The schedule window is anchored on the clock in date, and all
+// time worked on a non-working day counts as overtime.
+func CalculateAttendanceTimeStats(schedule *models.AttendanceSchedule, checkIn, checkOut time.Time) models.AttendanceTimeStats {
+	if schedule == nil || !checkOut.After(checkIn) {
+		return models.AttendanceTimeStats{}
+	}
+
+	if !IsWorkingDay(schedule.WorkingDays, checkIn.Weekday()) {
+		return NonWorkingDayTimeStats(checkIn, checkOut)
+	}
+	return timeStatsOn(schedule, checkIn, checkIn, checkOut)
+}
+
+// timeStatsOn computes late, early leave and overtime minutes against the schedule window
+// starting on shiftDate
+func timeStatsOn(schedule *models.AttendanceSchedule, shiftDate, checkIn, checkOut time.Time) models.AttendanceTimeStats {
+	stats := models.AttendanceTimeStats{}
+	_, end, err := ScheduleWindow(schedule, shiftDate)
+	if err != nil {
+		return stats
+	}
+
+	stats.LateMinutes = lateMinutesOn(schedule, shiftDate, checkIn)
+
+	tolerance := time.Duration(schedule.ToleranceEarly) * time.Minute
+	if checkOut.Before(end.Add(-tolerance)) {
+		stats.EarlyLeaveMinutes = wholeMinutes(end.Sub(checkOut))
+	}
+	if checkOut.After(end) {
+		stats.OvertimeMinutes = wholeMinutes(checkOut.Sub(end))
+	}
+	return stats
+}
+
+// NonWorkingDayTimeStats counts all time worked on a day off or holiday as overtime
+func NonWorkingDayTimeStats(checkIn, checkOut time.Time) models.AttendanceTimeStats {
+	stats := models.AttendanceTimeStats{}
+	if checkOut.After(checkIn) {
+		stats.OvertimeMinutes = wholeMinutes(checkOut.Sub(checkIn))
+	}
+	return stats
+}
+
+func wholeMinutes(d time.Duration) float64 {
+	return math.Floor(d.Minutes())
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func testSchedule() *models.AttendanceSchedule {
+	return &models.AttendanceSchedule{
+		Name:           "Regular",
+		StartTime:      "09:00",
+		EndTime:        "17:00",
+		ToleranceLate:  15,
+		ToleranceEarly: 10,
+	}
+}
+
+func TestCalculateLateMinutes(t *testing.T) {
+	schedule := testSchedule()
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		checkIn time.Time
+		want    float64
+	}{
+		{"on time", monday.Add(8*time.Hour + 55*time.Minute), 0},
+		{"within tolerance", monday.Add(9*time.Hour + 15*time.Minute), 0},
+		{"late counted from start", monday.Add(9*time.Hour + 20*time.Minute + 30*time.Second), 20},
+		{"weekend is never late", monday.AddDate(0, 0, 5).Add(11 * time.Hour), 0},
+	}
+
+	for _, tc := range cases {
+		if got := CalculateLateMinutes(schedule, tc.checkIn); got != tc.want {
+			t.Errorf("%s: expected %.0f late minutes, got %.0f", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCalculateAttendanceTimeStats(t *testing.T) {
+	schedule := testSchedule()
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	stats := CalculateAttendanceTimeStats(schedule, monday.Add(9*time.Hour), monday.Add(16*time.Hour+30*time.Minute))
+	if stats.EarlyLeaveMinutes != 30 || stats.OvertimeMinutes != 0 {
+		t.Errorf("expected 30 early leave minutes, got %+v", stats)
+	}
+
+	stats = CalculateAttendanceTimeStats(schedule, monday.Add(9*time.Hour), monday.Add(16*time.Hour+55*time.Minute))
+	if stats.EarlyLeaveMinutes != 0 {
+		t.Errorf("expected early leave within tolerance to be ignored, got %+v", stats)
+	}
+
+	stats = CalculateAttendanceTimeStats(schedule, monday.Add(9*time.Hour), monday.Add(18*time.Hour+45*time.Minute))
+	if stats.OvertimeMinutes != 105 {
+		t.Errorf("expected 105 overtime minutes, got %+v", stats)
+	}
+
+	saturday := monday.AddDate(0, 0, 5)
+	stats = CalculateAttendanceTimeStats(schedule, saturday.Add(10*time.Hour), saturday.Add(14*time.Hour))
+	if stats.OvertimeMinutes != 240 || stats.LateMinutes != 0 {
+		t.Errorf("expected whole weekend shift as overtime, got %+v", stats)
+	}
+}
+
+func TestScheduleWindowOvernight(t *testing.T) {
+	schedule := &models.AttendanceSchedule{StartTime: "22:00", EndTime: "06:00"}
+	date := time.Date(2025, 1, 6, 21, 0, 0, 0, time.UTC)
+
+	start, end, err := ScheduleWindow(schedule, date)
+	if err != nil {
+		t.Fatalf("ScheduleWindow failed: %v", err)
+	}
+	if end.Sub(start) != 8*time.Hour {
+		t.Errorf("expected 8h overnight window, got %s - %s", start, end)
+	}
+}
+
+func TestCountWorkingDays(t *testing.T) {
+	// January 2025: 23 weekdays
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	if got := CountWorkingDays(0, start, end); got != 23 {
+		t.Errorf("expected 23 default working days, got %d", got)
+	}
+
+	saturdaysOnly := 1 << time.Saturday
+	if got := CountWorkingDays(saturdaysOnly, start, end); got != 4 {
+		t.Errorf("expected 4 Saturdays, got %d", got)
+	}
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// MaxSessionHours is the longest attendance session a correction may produce
+const MaxSessionHours = 24
+
+var ErrInvalidSessionTimes = errors.New("clock out must be after clock in, within 24 hours, and neither may be in the future")
+
+// AutoClockOutTimes returns the check out recorded for a forgotten session and the cut-off
+// after which the session is closed. The recorded check out is the scheduled end (or check
+// in plus defaultHours without a schedule or when clocked in after the scheduled end), so
+// total_hours is capped at the planned session. The cut-off is that check out plus graceHours.
+func AutoClockOutTimes(checkIn time.Time, scheduledEnd time.Time, graceHours, defaultHours float64) (checkOut time.Time, cutOff time.Time) {
+	checkOut = scheduledEnd
+	if !checkOut.After(checkIn) {
+		checkOut = checkIn.Add(hoursDuration(defaultHours))
+	}
+	return checkOut, checkOut.Add(hoursDuration(graceHours))
+}
+
+func hoursDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// ValidateSessionTimes checks corrected session times. checkOut is nil for a session that
+// stays open.
+func ValidateSessionTimes(checkIn time.Time, checkOut *time.Time, now time.Time) error {
+	if checkIn.IsZero() || checkIn.After(now) {
+		return ErrInvalidSessionTimes
+	}
+	if checkOut == nil {
+		return nil
+	}
+	if !checkOut.After(checkIn) || checkOut.After(now) || checkOut.Sub(checkIn) > MaxSessionHours*time.Hour {
+		return ErrInvalidSessionTimes
+	}
+	return nil
+}
+
+// WorkedHours returns the hours between check in and check out minus break minutes
+func WorkedHours(checkIn, checkOut time.Time, breakMinutes float64) float64 {
+	hours := checkOut.Sub(checkIn).Hours() - breakMinutes/60
+	if hours < 0 {
+		return 0
+	}
+	return hours
+}
+
+// BreakExceededMinutes returns how many minutes of a break go over the tenant limits: the
+// per-break maximum and what is left of the per-session total after usedBefore minutes of
+// earlier breaks. A limit of 0 means no limit.
+func BreakExceededMinutes(duration, usedBefore float64, maxBreakMinutes, maxTotalMinutes int) float64 {
+	allowed := math.Inf(1)
+	if maxBreakMinutes > 0 {
+		allowed = float64(maxBreakMinutes)
+	}
+	if maxTotalMinutes > 0 {
+		allowed = math.Min(allowed, math.Max(0, float64(maxTotalMinutes)-usedBefore))
+	}
+	return math.Max(0, duration-allowed)
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoClockOutTimes(t *testing.T) {
+	checkIn := time.Date(2026, 1, 5, 8, 55, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC)
+
+	checkOut, cutOff := AutoClockOutTimes(checkIn, end, 4, 8)
+	if !checkOut.Equal(end) || !cutOff.Equal(end.Add(4*time.Hour)) {
+		t.Errorf("expected check out at scheduled end and cut-off 4h later, got %v / %v", checkOut, cutOff)
+	}
+
+	// Tanpa jadwal: sesi dibatasi default 8 jam sejak clock in
+	checkOut, cutOff = AutoClockOutTimes(checkIn, time.Time{}, 2.5, 8)
+	if want := checkIn.Add(8 * time.Hour); !checkOut.Equal(want) || !cutOff.Equal(want.Add(150*time.Minute)) {
+		t.Errorf("expected check out at check in + 8h, got %v / %v", checkOut, cutOff)
+	}
+
+	// Clock in setelah jadwal selesai juga memakai durasi default
+	lateCheckIn := end.Add(time.Hour)
+	if checkOut, _ = AutoClockOutTimes(lateCheckIn, end, 4, 8); !checkOut.Equal(lateCheckIn.Add(8 * time.Hour)) {
+		t.Errorf("expected default session after a late clock in, got %v", checkOut)
+	}
+}
+
+func TestWorkedHours(t *testing.T) {
+	checkIn := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	if got := WorkedHours(checkIn, checkIn.Add(9*time.Hour), 60); got != 8 {
+		t.Errorf("expected 8 worked hours after a 60 minute break, got %v", got)
+	}
+	if got := WorkedHours(checkIn, checkIn.Add(30*time.Minute), 45); got != 0 {
+		t.Errorf("expected worked hours never below 0, got %v", got)
+	}
+}
+
+func TestValidateSessionTimes(t *testing.T) {
+	now := time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)
+	checkIn := time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+	checkOut := checkIn.Add(9 * time.Hour)
+
+	if err := ValidateSessionTimes(checkIn, &checkOut, now); err != nil {
+		t.Errorf("expected valid session, got %v", err)
+	}
+	if err := ValidateSessionTimes(checkIn, nil, now); err != nil {
+		t.Errorf("expected open session to be valid, got %v", err)
+	}
+
+	before := checkIn.Add(-time.Minute)
+	tooLong := checkIn.Add(25 * time.Hour)
+	future := now.Add(time.Hour)
+	for _, out := range []*time.Time{&before, &tooLong, &future} {
+		if err := ValidateSessionTimes(checkIn, out, now); err != ErrInvalidSessionTimes {
+			t.Errorf("expected clock out %v to be rejected, got %v", out, err)
+		}
+	}
+	if err := ValidateSessionTimes(future, nil, now); err != ErrInvalidSessionTimes {
+		t.Errorf("expected future clock in to be rejected, got %v", err)
+	}
+}
+
+func TestBreakExceededMinutes(t *testing.T) {
+	tests := []struct {
+		name               string
+		duration, used     float64
+		maxBreak, maxTotal int
+		expected           float64
+	}{
+		{"within limits", 45, 0, 60, 90, 0},
+		{"over per-break limit", 75, 0, 60, 90, 15},
+		{"over remaining total", 40, 70, 60, 90, 20},
+		{"total already used", 10, 95, 60, 90, 10},
+		{"no limits", 300, 120, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := BreakExceededMinutes(tt.duration, tt.used, tt.maxBreak, tt.maxTotal); got != tt.expected {
+			t.Errorf("%s: expected %v exceeded minutes, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// ShiftEarlyClockIn is how long before the shift start a clock in is tied to that shift
+const ShiftEarlyClockIn = 2 * time.Hour
+
+// ParseShiftDate parses a YYYY-MM-DD shift date at midnight in loc
+func ParseShiftDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// FindShiftInstance returns the rostered shift a clock in at the given time belongs to:
+// the clock in must fall between ShiftEarlyClockIn before the shift start and the shift end.
+// When windows overlap (a night shift ending as the morning shift starts) the shift with the
+// nearest start wins. Returns nil when the time is outside every rostered shift.
+func FindShiftInstance(shifts []models.RosteredShift, at time.Time) *models.RosteredShift {
+	var found *models.RosteredShift
+	var nearest time.Duration
+
+	for i := range shifts {
+		shiftDate, err := ParseShiftDate(shifts[i].ShiftDate, at.Location())
+		if err != nil {
+			continue
+		}
+		start, end, err := ScheduleWindow(&shifts[i].Schedule, shiftDate)
+		if err != nil || at.Before(start.Add(-ShiftEarlyClockIn)) || !at.Before(end) {
+			continue
+		}
+
+		distance := at.Sub(start)
+		if distance < 0 {
+			distance = -distance
+		}
+		if found == nil || distance < nearest {
+			found, nearest = &shifts[i], distance
+		}
+	}
+
+	return found
+}
+
+// CalculateShiftLateMinutes returns minutes late for a rostered shift starting on shiftDate.
+// The roster decides that the day is a working day, so schedule working days are ignored.
+func CalculateShiftLateMinutes(schedule *models.AttendanceSchedule, shiftDate, checkIn time.Time) float64 {
+	if schedule == nil {
+		return 0
+	}
+	return lateMinutesOn(schedule, shiftDate, checkIn)
+}
+
+// CalculateShiftTimeStats computes late, early leave and overtime minutes for a completed
+// rostered shift starting on shiftDate, also when the shift ends after midnight
+func CalculateShiftTimeStats(schedule *models.AttendanceSchedule, shiftDate, checkIn, checkOut time.Time) models.AttendanceTimeStats {
+	if schedule == nil || !checkOut.After(checkIn) {
+		return models.AttendanceTimeStats{}
+	}
+	return timeStatsOn(schedule, shiftDate, checkIn, checkOut)
+}
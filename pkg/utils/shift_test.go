@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func nightShift(date string) models.RosteredShift {
+	return models.RosteredShift{
+		ShiftDate: date,
+		Schedule:  models.AttendanceSchedule{Name: "Night", StartTime: "22:00", EndTime: "06:00", ToleranceLate: 10},
+	}
+}
+
+func morningShift(date string) models.RosteredShift {
+	return models.RosteredShift{
+		ShiftDate: date,
+		Schedule:  models.AttendanceSchedule{Name: "Morning", StartTime: "06:00", EndTime: "14:00"},
+	}
+}
+
+func TestFindShiftInstance(t *testing.T) {
+	shifts := []models.RosteredShift{nightShift("2026-01-05"), morningShift("2026-01-06"), nightShift("2026-01-06")}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want *models.RosteredShift
+	}{
+		{"early clock in before night shift", time.Date(2026, 1, 5, 21, 30, 0, 0, time.UTC), &shifts[0]},
+		{"late clock in after midnight", time.Date(2026, 1, 6, 0, 30, 0, 0, time.UTC), &shifts[0]},
+		{"overlap picks nearest start", time.Date(2026, 1, 6, 5, 30, 0, 0, time.UTC), &shifts[1]},
+		{"second night shift on the next day", time.Date(2026, 1, 6, 21, 55, 0, 0, time.UTC), &shifts[2]},
+		{"outside every shift", time.Date(2026, 1, 6, 16, 0, 0, 0, time.UTC), nil},
+	}
+
+	for _, tc := range cases {
+		if got := FindShiftInstance(shifts, tc.at); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCalculateShiftTimeStats(t *testing.T) {
+	shift := nightShift("2026-01-05")
+	shiftDate := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	checkIn := time.Date(2026, 1, 6, 0, 15, 0, 0, time.UTC) // 2 jam 15 menit terlambat
+	if got := CalculateShiftLateMinutes(&shift.Schedule, shiftDate, checkIn); got != 135 {
+		t.Errorf("expected 135 late minutes after midnight, got %.0f", got)
+	}
+
+	stats := CalculateShiftTimeStats(&shift.Schedule, shiftDate,
+		time.Date(2026, 1, 5, 21, 58, 0, 0, time.UTC), time.Date(2026, 1, 6, 7, 0, 0, 0, time.UTC))
+	if stats.LateMinutes != 0 || stats.EarlyLeaveMinutes != 0 || stats.OvertimeMinutes != 60 {
+		t.Errorf("expected 60 overtime minutes only, got %+v", stats)
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+// ResolvePresence menentukan status kehadiran anggota tim pada date (YYYY-MM-DD, timezone tenant).
+// Sesi yang masih terbuka sejak kemarin (shift malam) tetap dihitung clocked in. Jam ditampilkan
+// dalam loc.
+func ResolvePresence(record models.TeamPresenceRecord, date string, loc *time.Location) models.TeamMemberPresence {
+	member := record.TeamMemberPresence
+	member.LastCheckInAt = inLocation(member.LastCheckInAt, loc)
+	member.BreakStartedAt = inLocation(member.BreakStartedAt, loc)
+	checkOut := inLocation(record.LastCheckOutAt, loc)
+
+	yesterday := date
+	if d, err := time.Parse("2006-01-02", date); err == nil {
+		yesterday = d.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	open := member.LastCheckInAt != nil && checkOut == nil && member.LastCheckInDate >= yesterday
+	today := member.LastCheckInAt != nil && member.LastCheckInDate == date
+
+	switch {
+	case open && member.BreakStartedAt != nil:
+		member.Status = models.PresenceOnBreak
+	case open:
+		member.Status = models.PresenceClockedIn
+	case today:
+		member.Status = models.PresenceClockedOut
+	case member.LeaveType != "":
+		member.Status = models.PresenceOnLeave
+	case record.DayType == models.PresenceHoliday || record.DayType == models.PresenceDayOff:
+		member.Status = record.DayType
+	default:
+		member.Status = models.PresenceNotArrived
+	}
+
+	if open || today {
+		member.CheckInTime = member.LastCheckInAt
+		member.CheckOutTime = checkOut
+		member.Late = member.LateMinutes > 0
+	} else {
+		// Attendance terakhir dari hari lain hanya dipakai untuk kantor terakhir
+		member.LateMinutes = 0
+		member.WorkMode = ""
+		member.BreakType = ""
+		member.BreakStartedAt = nil
+	}
+	if member.BreakStartedAt == nil {
+		member.BreakType = ""
+	}
+	return member
+}
+
+// SummarizePresence counts the members per presence state
+func SummarizePresence(members []models.TeamMemberPresence) models.TeamPresenceSummary {
+	summary := models.TeamPresenceSummary{Total: len(members)}
+	for _, member := range members {
+		switch member.Status {
+		case models.PresenceClockedIn:
+			summary.ClockedIn++
+		case models.PresenceOnBreak:
+			summary.OnBreak++
+		case models.PresenceClockedOut:
+			summary.ClockedOut++
+		case models.PresenceOnLeave:
+			summary.OnLeave++
+		case models.PresenceHoliday, models.PresenceDayOff:
+			summary.Off++
+		default:
+			summary.NotArrived++
+		}
+		if member.Late {
+			summary.Late++
+		}
+	}
+	return summary
+}
+
+func inLocation(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(loc)
+	return &local
+}
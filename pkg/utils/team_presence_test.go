@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nepskuy/be-godplan/pkg/models"
+)
+
+func TestResolvePresence(t *testing.T) {
+	loc := time.UTC
+	checkIn := time.Date(2026, 1, 6, 8, 20, 0, 0, time.UTC)
+	checkOut := checkIn.Add(9 * time.Hour)
+	nightShift := time.Date(2026, 1, 5, 22, 0, 0, 0, time.UTC)
+
+	record := func(date string, in, out, breakAt *time.Time) models.TeamPresenceRecord {
+		return models.TeamPresenceRecord{
+			TeamMemberPresence: models.TeamMemberPresence{
+				LastCheckInDate: date, LastCheckInAt: in, BreakStartedAt: breakAt, LateMinutes: 20, BreakType: "lunch",
+			},
+			LastCheckOutAt: out,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		record models.TeamPresenceRecord
+		status string
+		late   bool
+	}{
+		{"clocked in late", record("2026-01-06", &checkIn, nil, nil), models.PresenceClockedIn, true},
+		{"on break", record("2026-01-06", &checkIn, nil, &checkOut), models.PresenceOnBreak, true},
+		{"clocked out", record("2026-01-06", &checkIn, &checkOut, nil), models.PresenceClockedOut, true},
+		{"night shift from yesterday", record("2026-01-05", &nightShift, nil, nil), models.PresenceClockedIn, true},
+		{"yesterday finished", record("2026-01-05", &nightShift, &checkIn, nil), models.PresenceNotArrived, false},
+		{"never clocked in", models.TeamPresenceRecord{}, models.PresenceNotArrived, false},
+		{"on leave", models.TeamPresenceRecord{TeamMemberPresence: models.TeamMemberPresence{LeaveType: "Cuti Tahunan"}}, models.PresenceOnLeave, false},
+		{"holiday", models.TeamPresenceRecord{DayType: models.PresenceHoliday}, models.PresenceHoliday, false},
+	}
+	for _, tt := range tests {
+		got := ResolvePresence(tt.record, "2026-01-06", loc)
+		if got.Status != tt.status || got.Late != tt.late {
+			t.Errorf("%s: expected %s (late %v), got %s (late %v)", tt.name, tt.status, tt.late, got.Status, got.Late)
+		}
+	}
+
+	summary := SummarizePresence([]models.TeamMemberPresence{
+		{Status: models.PresenceClockedIn, Late: true},
+		{Status: models.PresenceOnBreak},
+		{Status: models.PresenceDayOff},
+		{Status: models.PresenceNotArrived},
+	})
+	if summary.Total != 4 || summary.ClockedIn != 1 || summary.OnBreak != 1 || summary.Off != 1 || summary.NotArrived != 1 || summary.Late != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	// Embed the IANA database, serverless runtimes (Vercel) do not ship /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+// DefaultTimezone dipakai untuk tenant yang belum mengatur timezone
+const DefaultTimezone = "Asia/Jakarta"
+
+var ErrInvalidTimezone = errors.New("timezone must be a valid IANA name, e.g. Asia/Jakarta")
+
+// LoadTimezone parses an IANA timezone name. "Local" and an empty name are rejected so a
+// tenant never silently follows the server timezone.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// ResolveTimezone returns the first valid timezone of names (e.g. office, then tenant),
+// falling back to DefaultTimezone
+func ResolveTimezone(names ...string) *time.Location {
+	for _, name := range names {
+		if loc, err := LoadTimezone(name); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// Greeting mengembalikan salam sesuai jam lokal t
+func Greeting(t time.Time) string {
+	hour := t.Hour()
+	switch {
+	case hour < 12:
+		return "Selamat Pagi"
+	case hour < 18:
+		return "Selamat Siang"
+	default:
+		return "Selamat Malam"
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	for _, name := range []string{"Asia/Jakarta", "Asia/Makassar", "Asia/Jayapura", " UTC "} {
+		if _, err := LoadTimezone(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Local", "WIB", "Asia/Bandung"} {
+		if _, err := LoadTimezone(name); err != ErrInvalidTimezone {
+			t.Errorf("expected %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestResolveTimezone(t *testing.T) {
+	if got := ResolveTimezone("", "Asia/Makassar").String(); got != "Asia/Makassar" {
+		t.Errorf("expected tenant timezone when office has none, got %s", got)
+	}
+	if got := ResolveTimezone("Asia/Jayapura", "Asia/Makassar").String(); got != "Asia/Jayapura" {
+		t.Errorf("expected office timezone to win, got %s", got)
+	}
+	if got := ResolveTimezone("invalid").String(); got != DefaultTimezone {
+		t.Errorf("expected default timezone, got %s", got)
+	}
+}
+
+func TestAttendanceDateInTimezone(t *testing.T) {
+	// 06:30 WIB is still the previous day in UTC
+	clockIn := time.Date(2026, 1, 5, 23, 30, 0, 0, time.UTC)
+	local := clockIn.In(ResolveTimezone("Asia/Jakarta"))
+
+	if got := local.Format("2006-01-02"); got != "2026-01-06" {
+		t.Errorf("expected attendance date 2026-01-06, got %s", got)
+	}
+	if got := Greeting(local); got != "Selamat Pagi" {
+		t.Errorf("expected morning greeting, got %s", got)
+	}
+	if got := Greeting(clockIn); got != "Selamat Malam" {
+		t.Errorf("expected evening greeting in UTC, got %s", got)
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteXLSX(&buf, "Recap", []string{"Name", "Days"}, [][]interface{}{
+		{"Budi & Sons", 20},
+		{"Siti", 18.5},
+	})
+	if err != nil {
+		t.Fatalf("WriteXLSX failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip archive: %v", err)
+	}
+
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(data)
+		}
+	}
+	if sheet == "" {
+		t.Fatal("sheet1.xml not found in workbook")
+	}
+
+	for _, want := range []string{`<c r="A1" t="inlineStr">`, "Budi &amp; Sons", `<c r="B2"><v>20</v></c>`, `<c r="B3"><v>18.5</v></c>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("expected sheet to contain %q", want)
+		}
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, want := range cases {
+		if got := xlsxColumnName(index); got != want {
+			t.Errorf("column %d: expected %s, got %s", index, want, got)
+		}
+	}
+}